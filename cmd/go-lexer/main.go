@@ -0,0 +1,64 @@
+// Command go-lexer works with go-lexer language definition files.
+//
+// Usage:
+//
+//	go-lexer gen [flags] definition
+//
+// The gen command loads a JSON or YAML definition file, see package definition, and writes
+// a standalone scanner for it, see package gen for the flags. With -test, the differential
+// test loads the same definition file at test time, unless -config-import and -config-expr
+// say otherwise, so run the command from the generated package's directory, e.g.
+//
+//	//go:generate go run github.com/jrsteele09/go-lexer/cmd/go-lexer gen -pkg mylang -test scanner_gen_diff_test.go mylang.yaml
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jrsteele09/go-lexer/lexer/definition"
+	"github.com/jrsteele09/go-lexer/lexer/gen"
+)
+
+const usage = "usage: go-lexer gen [flags] definition"
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	switch args[0] {
+	case "gen":
+		return runGen(args[1:])
+	}
+	return fmt.Errorf("go-lexer: unknown command %q\n%s", args[0], usage)
+}
+
+// runGen generates a scanner for the definition file that ends args, passing the flags
+// before it to gen.Run.
+func runGen(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[len(args)-1], "-") {
+		return errors.New(usage)
+	}
+	path := args[len(args)-1]
+	config, err := definition.LoadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Flags given later override these defaults
+	defaults := []string{
+		"-config-import", "github.com/jrsteele09/go-lexer/lexer/definition",
+		"-config-expr", fmt.Sprintf("definition.MustLoadFile(%q)", filepath.ToSlash(path)),
+	}
+	return gen.Run(config, append(defaults, args[:len(args)-1]...))
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGen(t *testing.T) {
+	dir := t.TempDir()
+	scanner, test := filepath.Join(dir, "scanner_gen.go"), filepath.Join(dir, "scanner_gen_diff_test.go")
+	definition := "../../lexer/definition/testdata/basic.yaml"
+	require.NoError(t, run([]string{"gen", "-pkg", "basic", "-o", scanner, "-test", test, definition}))
+
+	src, err := os.ReadFile(scanner)
+	require.NoError(t, err)
+	require.Contains(t, string(src), "package basic")
+	src, err = os.ReadFile(test)
+	require.NoError(t, err)
+	require.Contains(t, string(src), `definition.MustLoadFile("../../lexer/definition/testdata/basic.yaml")`)
+
+	// The differential test's runtime config can be given instead
+	require.NoError(t, run([]string{"gen", "-pkg", "basic", "-o", scanner, "-test", test, "-config-import", "example.com/basic", "-config-expr", "basic.Config()", definition}))
+	src, err = os.ReadFile(test)
+	require.NoError(t, err)
+	require.Contains(t, string(src), "basic.Config()")
+	require.NotContains(t, string(src), "MustLoadFile")
+}
+
+func TestGenErrors(t *testing.T) {
+	for message, args := range map[string][]string{
+		"usage: go-lexer gen":              nil,
+		`unknown command "build"`:          {"build"},
+		"usage: go-lexer gen [flags]":      {"gen", "-pkg"},
+		"no such file or directory":        {"gen", "-pkg", "basic", "missing.yaml"},
+		"unknown file extension":           {"gen", "-pkg", "basic", "basic.txt"},
+		`gen: invalid package name "1bad"`: {"gen", "-pkg", "1bad", "-o", filepath.Join(t.TempDir(), "x.go"), "../../lexer/definition/testdata/basic.json"},
+	} {
+		require.ErrorContains(t, run(args), message, args)
+	}
+}
@@ -0,0 +1,227 @@
+package lexer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AnonymousLabelStyle is the syntax of an assembler's anonymous labels.
+type AnonymousLabelStyle uint8
+
+const (
+	// NoAnonymousLabels lexes '+', '-' and ':' as symbols.
+	NoAnonymousLabels AnonymousLabelStyle = iota
+
+	// ACMEAnonymousLabels are runs of '+' or '-', as in ACME: a line starting with "-"
+	// defines a label that "bne -" branches back to, and "++" refers to the second "+"
+	// label forward.
+	ACMEAnonymousLabels
+
+	// CA65AnonymousLabels are defined by a ':' starting a line and referred to by ':'
+	// followed by a run of '+' or '-', as in ca65, e.g. "bne :-" and "jmp :++".
+	CA65AnonymousLabels
+)
+
+// AssemblerProfile configures the lexing of assembly language, see LanguageConfig.Assembler.
+// Mnemonics, directives and registers are matched like keywords, using the language's
+// KeywordCase.
+//
+// Anonymous labels are only recognised at the start of a line, or after a mnemonic or
+// directive, and when followed by white space, a comma or a comment. Anywhere else '+', '-'
+// and ':' are lexed as symbols, so "lda #-1" and "a-b" are unaffected. The token's Value is
+// the int label offset: -2 for "--" and ":--", 1 for "+" and ":+", and 0 for ca65's ':'.
+type AssemblerProfile struct {
+	Mnemonics  map[string]TokenIdentifier // Instruction mnemonics, e.g. "lda"
+	Directives map[string]TokenIdentifier // Directives with their prefix, e.g. ".byte" or "!org"
+	Registers  map[string]TokenIdentifier // Registers after a mnemonic or comma, e.g. "x" in "lda $10,x" or "a" in "asl a"
+
+	LocalLabelPrefixes string              // Runes starting local labels, e.g. "@" for ca65's "@loop" or "." for ACME's ".loop"
+	LocalLabel         TokenIdentifier     // Identifier of local label tokens
+	AnonymousLabels    AnonymousLabelStyle // Syntax of anonymous labels
+	AnonymousLabel     TokenIdentifier     // Identifier of anonymous label tokens
+	Immediate          TokenIdentifier     // Identifier of the '#' immediate prefix, NullType to leave '#' to Symbols
+}
+
+// tokenSet returns the identifiers of the profile's tokens in an assembler category.
+func (asm *AssemblerProfile) tokenSet(category Category) TokenSet {
+	var s TokenSet
+	add := func(table map[string]TokenIdentifier) {
+		for _, id := range table {
+			s.Add(id)
+		}
+	}
+	switch category {
+	case MnemonicCategory:
+		add(asm.Mnemonics)
+	case DirectiveCategory:
+		add(asm.Directives)
+	case RegisterCategory:
+		add(asm.Registers)
+	case LabelCategory:
+		if asm.LocalLabelPrefixes != "" {
+			s.Add(asm.LocalLabel)
+		}
+		if asm.AnonymousLabels != NoAnonymousLabels {
+			s.Add(asm.AnonymousLabel)
+		}
+	}
+	return s
+}
+
+// assemblerEntry is a mnemonic, directive or register.
+type assemblerEntry struct {
+	name     string // As written in the profile
+	id       TokenIdentifier
+	category Category
+}
+
+// assemblerTables are the lookup tables a TokenCreator builds from an AssemblerProfile.
+type assemblerTables struct {
+	profile  *AssemblerProfile
+	entries  map[string]assemblerEntry // Mnemonics, directives and registers, case folded unless keywords are case-sensitive
+	fold     bool
+	prefixes string // Runes starting directives and local labels that aren't identifier runes
+}
+
+func newAssemblerTables(ll *LanguageConfig) *assemblerTables {
+	asm := ll.Assembler
+	if asm == nil {
+		return nil
+	}
+	t := &assemblerTables{profile: asm, entries: make(map[string]assemblerEntry), fold: ll.KeywordCase != CaseSensitive}
+	add := func(table map[string]TokenIdentifier, category Category) {
+		for name, id := range table {
+			t.entries[t.key(name)] = assemblerEntry{name: name, id: id, category: category}
+		}
+	}
+	add(asm.Registers, RegisterCategory)
+	add(asm.Mnemonics, MnemonicCategory)
+	add(asm.Directives, DirectiveCategory)
+
+	addPrefix := func(r rune) {
+		if !ll.isIdentifierChar(r, 0) && !strings.ContainsRune(t.prefixes, r) {
+			t.prefixes += string(r)
+		}
+	}
+	for directive := range asm.Directives {
+		if r, _ := utf8.DecodeRuneInString(directive); r != utf8.RuneError {
+			addPrefix(r)
+		}
+	}
+	for _, r := range asm.LocalLabelPrefixes {
+		addPrefix(r)
+	}
+	return t
+}
+
+// key returns the key of a name in the entries table.
+func (t *assemblerTables) key(name string) string {
+	if t.fold {
+		return FoldCase(name)
+	}
+	return name
+}
+
+// isPrefix reports whether s is a lone directive or local label prefix, e.g. "." or "!".
+func (t *assemblerTables) isPrefix(s []byte) bool {
+	r, size := utf8.DecodeRune(s)
+	return size == len(s) && size > 0 && strings.ContainsRune(t.prefixes, r)
+}
+
+// assemblerToken returns the token of an identifier that's a mnemonic, directive, register or
+// local label.
+func (tf *TokenCreator) assemblerToken(identifier string) (Token, bool) {
+	t := tf.asm
+	if entry, found := t.entries[t.key(identifier)]; found && (entry.category != RegisterCategory || tf.startsRegister()) {
+		literal := identifier
+		if tf.languageConfig.KeywordCase == CaseInsensitiveCanonical {
+			literal = entry.name
+		}
+		return newToken(entry.category, entry.id, literal, nil), true
+	}
+
+	if r, _ := utf8.DecodeRuneInString(identifier); strings.ContainsRune(t.profile.LocalLabelPrefixes, r) {
+		return newToken(LabelCategory, t.profile.LocalLabel, identifier, nil), true
+	}
+	return Token{}, false
+}
+
+// startsStatementOrOperand reports whether the next token is the first of its line, or
+// follows a mnemonic or directive.
+func (tf *TokenCreator) startsStatementOrOperand() bool {
+	return !tf.hasPrevious || tf.previous.Category == MnemonicCategory || tf.previous.Category == DirectiveCategory
+}
+
+// startsRegister reports whether the next token follows a mnemonic or a comma, where a
+// register may be.
+func (tf *TokenCreator) startsRegister() bool {
+	return tf.hasPrevious && (tf.previous.Category == MnemonicCategory || tf.previous.Literal == ",")
+}
+
+// assemblerTokenizer starts the tokenizer of an immediate prefix, anonymous label, directive
+// or local label starting with r, reporting false if r doesn't start any of them.
+func (tf *TokenCreator) assemblerTokenizer(r rune) ([]Token, bool) {
+	asm := tf.asm.profile
+	switch {
+	case r == '#' && asm.Immediate != NullType:
+		return tf.emit(newToken(SymbolCategory, asm.Immediate, "#", byte('#'))), true
+	case tf.anonymousLabels.starts(r) && tf.startsStatementOrOperand():
+		tf.SetTokenizer(tf.anonymousLabels.start(r))
+		return nil, true
+	case strings.ContainsRune(tf.asm.prefixes, r):
+		tf.SetTokenizer(tf.identifiers.start(string(r)))
+		return nil, true
+	}
+	return nil, false
+}
+
+// anonymousLabelTokenizer lexes anonymous labels such as "--" and ":+".
+type anonymousLabelTokenizer struct {
+	tf      *TokenCreator
+	style   AnonymousLabelStyle
+	label   []byte
+	handler TokenizerHandler
+}
+
+// starts reports whether r may start an anonymous label.
+func (a *anonymousLabelTokenizer) starts(r rune) bool {
+	switch a.style {
+	case ACMEAnonymousLabels:
+		return r == '+' || r == '-'
+	case CA65AnonymousLabels:
+		return r == ':'
+	}
+	return false
+}
+
+func (a *anonymousLabelTokenizer) start(r rune) TokenizerHandler {
+	a.label = append(a.label[:0], byte(r))
+	if a.handler == nil {
+		a.handler = a.tokenize
+	}
+	return a.handler
+}
+
+func (a *anonymousLabelTokenizer) tokenize(r rune) ([]Token, completed, error) {
+	tf := a.tf
+	last := a.label[len(a.label)-1]
+	if (r == '+' || r == '-') && (rune(last) == r || (last == ':' && len(a.label) == 1)) {
+		a.label = append(a.label, byte(r))
+		return nil, false, tf.checkLength(len(a.label))
+	}
+
+	if _, comment := tf.languageConfig.Comments[string(r)]; !unicode.IsSpace(r) && r != ',' && !comment {
+		// Not a label, e.g. the '-' of "lda -1"
+		tf.SetTokenizer(tf.symbols.start(string(a.label)))
+		return tf.symbols.tokenize(r)
+	}
+
+	tf.SetOverFlow(r)
+	label := string(a.label)
+	offset := len(strings.TrimLeft(label, ":"))
+	if strings.ContainsRune(label, '-') {
+		offset = -offset
+	}
+	return tf.emit(newToken(LabelCategory, tf.asm.profile.AnonymousLabel, label, offset)), true, nil
+}
@@ -0,0 +1,175 @@
+package lexer_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	LdaMnemonic lexer.TokenIdentifier = iota + lexer.LastStdLiteral + 100
+	BneMnemonic
+	AslMnemonic
+	ByteDirective
+	OrgDirective
+	XRegister
+	ARegister
+	LocalLabel
+	AnonymousLabel
+	ImmediateToken
+	AsmLabel
+	AsmComma
+	AsmOpenParen
+	AsmCloseParen
+	AsmMinus
+	AsmNot
+	AsmNotEqual
+	AsmColon
+)
+
+func newAssemblerLanguage(style lexer.AnonymousLabelStyle, localLabels string) *lexer.LanguageConfig {
+	return lexer.NewLanguage("6502").
+		Mnemonic("lda", LdaMnemonic).
+		Mnemonic("bne", BneMnemonic).
+		Mnemonic("asl", AslMnemonic).
+		Directive(".byte", ByteDirective).
+		Directive("!org", OrgDirective).
+		Register("x", XRegister).
+		Register("a", ARegister).
+		LocalLabels(localLabels, LocalLabel).
+		AnonymousLabels(style, AnonymousLabel).
+		Immediate(ImmediateToken).
+		Symbol(',', AsmComma).
+		Symbol('(', AsmOpenParen).
+		Symbol(')', AsmCloseParen).
+		Symbol('-', AsmMinus).
+		Symbol('!', AsmNot).
+		Operator("!=", AsmNotEqual).
+		LineComment(";").
+		HexPrefix("$").
+		KeywordCase(lexer.CaseInsensitive).
+		IdentifierTermination(":").
+		TokenCreator(func(identifier string) lexer.Token { return lexer.NewToken(AsmLabel, identifier, nil) }).
+		MustBuild()
+}
+
+func tokenCategories(tokens []lexer.Token) []lexer.Category {
+	categories := make([]lexer.Category, len(tokens))
+	for i, t := range tokens {
+		categories[i] = t.Category
+	}
+	return categories
+}
+
+func TestAssemblerProfile(t *testing.T) {
+	l := lexer.NewLexer(newAssemblerLanguage(lexer.ACMEAnonymousLabels, "@"))
+
+	tokens, err := l.TokenizeLine("loop: LDA ($FF),X ; load", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{AsmLabel, LdaMnemonic, AsmOpenParen, lexer.HexLiteral, AsmCloseParen, AsmComma, XRegister, lexer.EndOfLineType}, tokenIDs(tokens))
+	require.Equal(t, lexer.MnemonicCategory, tokens[1].Category)
+	require.Equal(t, lexer.RegisterCategory, tokens[6].Category)
+	require.Equal(t, "X", tokens[6].Literal)
+
+	tokens, err = l.TokenizeLine("@skip: lda #1", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{LocalLabel, LdaMnemonic, ImmediateToken, lexer.IntegerLiteral, lexer.EndOfLineType}, tokenIDs(tokens))
+	require.Equal(t, "@skip:", tokens[0].Literal)
+	require.Equal(t, lexer.LabelCategory, tokens[0].Category)
+
+	tokens, err = l.TokenizeLine(".byte 1,x,a", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{ByteDirective, lexer.IntegerLiteral, AsmComma, XRegister, AsmComma, ARegister, lexer.EndOfLineType}, tokenIDs(tokens))
+	require.Equal(t, lexer.DirectiveCategory, tokens[0].Category)
+
+	// Registers only follow a mnemonic or a comma
+	tokens, err = l.TokenizeLine("a: asl a", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{AsmLabel, AslMnemonic, ARegister, lexer.EndOfLineType}, tokenIDs(tokens))
+	tokens, err = l.TokenizeLine("x", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, AsmLabel, tokens[0].ID)
+
+	// A lone directive prefix is a symbol
+	tokens, err = l.TokenizeLine("!org $1000 ! x != 1", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{OrgDirective, lexer.HexLiteral, AsmNot, AsmLabel, AsmNotEqual, lexer.IntegerLiteral, lexer.EndOfLineType}, tokenIDs(tokens))
+}
+
+func TestACMEAnonymousLabels(t *testing.T) {
+	l := lexer.NewLexer(newAssemblerLanguage(lexer.ACMEAnonymousLabels, "."))
+
+	tokens, err := l.TokenizeLine("-- bne -- ;back", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{AnonymousLabel, BneMnemonic, AnonymousLabel, lexer.EndOfLineType}, tokenIDs(tokens))
+	require.Equal(t, -2, tokens[0].Value)
+	require.Equal(t, "--", tokens[2].Literal)
+
+	tokens, err = l.TokenizeLine("bne +", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, AnonymousLabel, tokens[1].ID)
+	require.Equal(t, 1, tokens[1].Value)
+
+	// Elsewhere '-' is a symbol
+	tokens, err = l.TokenizeLine("lda -1,x", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{LdaMnemonic, AsmMinus, lexer.IntegerLiteral, AsmComma, XRegister, lexer.EndOfLineType}, tokenIDs(tokens))
+	tokens, err = l.TokenizeLine("lda #a - b", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, AsmMinus, tokens[3].ID)
+
+	// ACME's local labels start with '.', as directives may
+	tokens, err = l.TokenizeLine(".loop .byte 0", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.Category{lexer.LabelCategory, lexer.DirectiveCategory, lexer.LiteralCategory, lexer.TriviaCategory}, tokenCategories(tokens))
+}
+
+func TestCA65AnonymousLabels(t *testing.T) {
+	l := lexer.NewLexer(newAssemblerLanguage(lexer.CA65AnonymousLabels, "@"))
+
+	tokens, err := l.TokenizeLine(": bne :-", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{AnonymousLabel, BneMnemonic, AnonymousLabel, lexer.EndOfLineType}, tokenIDs(tokens))
+	require.Equal(t, 0, tokens[0].Value)
+	require.Equal(t, ":-", tokens[2].Literal)
+	require.Equal(t, -1, tokens[2].Value)
+
+	tokens, err = l.TokenizeLine("bne :++", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, tokens[1].Value)
+}
+
+func TestValidateAssembler(t *testing.T) {
+	config := newAssemblerLanguage(lexer.ACMEAnonymousLabels, "@")
+	config.Assembler.Mnemonics["lda"] = XRegister
+	config.Assembler.Directives["!o-rg"] = OrgDirective + 50
+	config.Assembler.AnonymousLabel = lexer.NullType
+	config.Keywords["bne"] = BneMnemonic + 50
+
+	err := config.Validate()
+	require.ErrorContains(t, err, fmt.Sprintf(`token identifier %d shared by mnemonic "lda", register "x"`, XRegister))
+	require.ErrorContains(t, err, `directive "!o-rg" isn't lexed as an identifier`)
+	require.ErrorContains(t, err, `mnemonic "bne" is also a keyword`)
+	require.ErrorContains(t, err, "anonymous labels have the null token identifier")
+	require.True(t, errors.Is(err, lexer.ErrConflict))
+}
+
+func TestAssemblerTokenSets(t *testing.T) {
+	config := newAssemblerLanguage(lexer.ACMEAnonymousLabels, "@")
+	require.Equal(t, []lexer.TokenIdentifier{LdaMnemonic, BneMnemonic, AslMnemonic}, config.TokenSet(lexer.MnemonicCategory).IDs())
+	require.Equal(t, []lexer.TokenIdentifier{LocalLabel, AnonymousLabel}, config.TokenSet(lexer.LabelCategory).IDs())
+	require.Equal(t, "Mnemonic", lexer.MnemonicCategory.String())
+}
+
+func TestCompiledAssembler(t *testing.T) {
+	source := "-- lda ($ff),x ; load\n!org $1000 ! x != 1\n@skip: bne -- \n.byte 1,x,a\n"
+	expected, err := lexer.NewLexer(newAssemblerLanguage(lexer.ACMEAnonymousLabels, "@")).Tokenize(strings.NewReader(source), "test.asm")
+	require.NoError(t, err)
+	actual, err := lexer.NewLexer(newAssemblerLanguage(lexer.ACMEAnonymousLabels, "@").Compile()).Tokenize(strings.NewReader(source), "test.asm")
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
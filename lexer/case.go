@@ -0,0 +1,143 @@
+package lexer
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+)
+
+// CaseSensitivity controls how keywords are matched.
+type CaseSensitivity uint8
+
+const (
+	// CaseSensitive matches keywords exactly as they are written in Keywords.
+	CaseSensitive CaseSensitivity = iota
+
+	// CaseInsensitive matches keywords regardless of case, keeping the source text as the
+	// token's Literal, e.g. "Let".
+	CaseInsensitive
+
+	// CaseInsensitiveCanonical matches keywords regardless of case and uses the keyword as
+	// written in Keywords as the token's Literal, e.g. "let" for "LET".
+	CaseInsensitiveCanonical
+)
+
+// FoldCase returns the Unicode case folding of s, the form used to compare keywords and
+// identifiers regardless of case. For example "LET", "Let" and "let" all fold to "let",
+// and "STRASSE" and "straße" both fold to "strasse".
+func FoldCase(s string) string {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			return cases.Fold().String(s)
+		}
+		if 'A' <= c && c <= 'Z' {
+			return foldASCII(s, i)
+		}
+	}
+	return s
+}
+
+// foldASCII lower cases s, which has no upper case letters before index i, switching to
+// full Unicode folding if it finds a non-ASCII rune.
+func foldASCII(s string, i int) string {
+	b := make([]byte, len(s))
+	copy(b, s[:i])
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			return cases.Fold().String(s)
+		}
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		b[i] = c
+	}
+	return string(b)
+}
+
+// caseFolding holds the case folded keyword and comment tables used by a TokenCreator when
+// keywords are case-insensitive.
+type caseFolding struct {
+	keywords map[string]string // Folded keyword to keyword
+	comments map[string]string // Folded comment opener to opener
+}
+
+func newCaseFolding(ll *LanguageConfig) *caseFolding {
+	if ll.KeywordCase == CaseSensitive {
+		return nil
+	}
+	f := &caseFolding{
+		keywords: make(map[string]string, len(ll.Keywords)),
+		comments: make(map[string]string),
+	}
+	for keyword := range ll.Keywords {
+		f.keywords[FoldCase(keyword)] = keyword
+	}
+	for open := range ll.Comments {
+		if ll.isIdentifierShaped(open) {
+			f.comments[FoldCase(open)] = open
+		}
+	}
+	return f
+}
+
+// tokenFromIdentifier is LanguageConfig.tokenFromIdentifier, applying the language's
+// identifier normalization, keyword case sensitivity and identifier folding.
+func (tf *TokenCreator) tokenFromIdentifier(identifier string) Token {
+	ll := tf.languageConfig
+	identifier = ll.normalizeIdentifier(identifier)
+	if tf.asm != nil {
+		if t, found := tf.assemblerToken(identifier); found {
+			return t
+		}
+	}
+	if tf.folding == nil && !ll.FoldIdentifiers {
+		return ll.tokenFromIdentifier(identifier)
+	}
+
+	folded := FoldCase(identifier)
+	if tf.folding != nil {
+		if keyword, found := tf.folding.keywords[folded]; found {
+			literal := identifier
+			if ll.KeywordCase == CaseInsensitiveCanonical {
+				literal = keyword
+			}
+			return newToken(KeywordCategory, ll.Keywords[keyword], literal, nil)
+		}
+	} else if id, found := ll.Keywords[identifier]; found {
+		return newToken(KeywordCategory, id, identifier, nil)
+	}
+
+	if ll.FoldIdentifiers {
+		identifier = folded
+	}
+	return ll.tokenFromCreators(identifier)
+}
+
+// keywordToken returns the token of an identifier that's a keyword in the compiled trie,
+// without building a string for it. It reports false, leaving the identifier to
+// tokenFromIdentifier, if the language isn't compiled, the identifier isn't in the trie,
+// or the language has assembler rules or normalizes identifiers.
+func (tf *TokenCreator) keywordToken(identifier []byte) (Token, bool) {
+	ll := tf.languageConfig
+	if ll.compiled == nil || tf.asm != nil || ll.NormalizeIdentifiers || ll.KeywordCase == CaseInsensitive {
+		return Token{}, false
+	}
+	node := ll.compiled.keyword(identifier, ll.KeywordCase)
+	if node == nil {
+		return Token{}, false
+	}
+	return newToken(KeywordCategory, node.keywordID, node.keyword, nil), true
+}
+
+// isStartOfComment reports whether an identifier opens a comment, ignoring case if keywords
+// are case-insensitive, and if so moves the comment parser into the comment.
+func (tf *TokenCreator) isStartOfComment(identifier string) bool {
+	if tf.folding != nil {
+		if open, found := tf.folding.comments[FoldCase(identifier)]; found {
+			identifier = open
+		}
+	}
+	return tf.commentParser.IsStartOfComment(identifier)
+}
@@ -0,0 +1,78 @@
+package lexer_test
+
+import (
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoldCase(t *testing.T) {
+	require.Equal(t, "let", lexer.FoldCase("LET"))
+	require.Equal(t, "let", lexer.FoldCase("Let"))
+	require.Equal(t, "let", lexer.FoldCase("let"))
+	require.Equal(t, "a_1$", lexer.FoldCase("A_1$"))
+	require.Equal(t, "strasse", lexer.FoldCase("STRASSE"))
+	require.Equal(t, "strasse", lexer.FoldCase("straße"))
+	require.Equal(t, "σοφία", lexer.FoldCase("ΣΟΦΊΑ"))
+	require.Equal(t, lexer.FoldCase("Kelvin"), lexer.FoldCase("Kelvin")) // Kelvin sign
+}
+
+func TestKeywordCase(t *testing.T) {
+	tests := []struct {
+		sensitivity lexer.CaseSensitivity
+		ids         []lexer.TokenIdentifier
+		literals    []string
+	}{
+		{lexer.CaseSensitive, []lexer.TokenIdentifier{IntegerVariableToken, IntegerVariableToken, LetStatementToken}, []string{"LET", "Let", "let"}},
+		{lexer.CaseInsensitive, []lexer.TokenIdentifier{LetStatementToken, LetStatementToken, LetStatementToken}, []string{"LET", "Let", "let"}},
+		{lexer.CaseInsensitiveCanonical, []lexer.TokenIdentifier{LetStatementToken, LetStatementToken, LetStatementToken}, []string{"let", "let", "let"}},
+	}
+	for _, tt := range tests {
+		config := NewBasicLanguage()
+		config.KeywordCase = tt.sensitivity
+
+		tokens, err := lexer.NewLexer(config).TokenizeLine("LET Let let", "testfile", 0)
+		require.NoError(t, err)
+		require.Equal(t, append(tt.ids, lexer.EndOfLineType), tokenIDs(tokens))
+		for i, literal := range tt.literals {
+			require.Equal(t, literal, tokens[i].Literal)
+		}
+	}
+}
+
+func TestCaseInsensitiveComments(t *testing.T) {
+	config := NewBasicLanguage()
+	config.KeywordCase = lexer.CaseInsensitive
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine("PRINT a REM a comment", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{PrintStatementToken, IntegerVariableToken, lexer.EndOfLineType}, tokenIDs(tokens))
+}
+
+func TestFoldIdentifiers(t *testing.T) {
+	config := NewBasicLanguage()
+	config.FoldIdentifiers = true
+
+	tokens, err := lexer.NewLexer(config.Compile()).TokenizeLine("Total TOTAL Name$ let LET", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{
+		IntegerVariableToken, IntegerVariableToken, StringVariableToken, LetStatementToken, IntegerVariableToken, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+	require.Equal(t, "total", tokens[0].Literal)
+	require.Equal(t, "total", tokens[1].Literal)
+	require.Equal(t, "name$", tokens[2].Literal)
+	require.Equal(t, "let", tokens[4].Literal, "keywords are still case-sensitive, so LET is a folded identifier")
+}
+
+func TestValidateCaseInsensitiveKeywords(t *testing.T) {
+	config := lexer.NewLexerLanguage(lexer.LanguageConfig{
+		Keywords:    map[string]lexer.TokenIdentifier{"if": 10, "IF": 11, "rem": 12},
+		Comments:    map[string]string{"REM": "\n"},
+		KeywordCase: lexer.CaseInsensitive,
+	})
+	require.Equal(t, []string{
+		`conflict: keywords "IF" and "if" differ only in case`,
+		`conflict: comment "REM" is also a keyword`,
+	}, problems(config.Validate()))
+}
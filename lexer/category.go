@@ -0,0 +1,216 @@
+package lexer
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+)
+
+// Category classifies tokens by how they were produced, so that parsers can test for
+// "any keyword" or "any literal" without listing token identifiers.
+type Category uint8
+
+const (
+	// NoCategory is the category of a token that hasn't been categorized.
+	NoCategory Category = iota
+
+	// KeywordCategory is the category of tokens from the Keywords table.
+	KeywordCategory
+
+	// OperatorCategory is the category of tokens from the Operators table.
+	OperatorCategory
+
+	// SymbolCategory is the category of tokens from the Symbols table.
+	SymbolCategory
+
+	// LiteralCategory is the category of number, hex, binary and string literals.
+	LiteralCategory
+
+	// IdentifierCategory is the category of tokens made by TokenCreators.
+	IdentifierCategory
+
+	// CommentCategory is the category of comments, which are only tokens when the language
+	// has KeepComments.
+	CommentCategory
+
+	// TriviaCategory is the category of end of line and end of file tokens.
+	TriviaCategory
+
+	// CustomCategory is the category of tokens from custom prefix tokenizers that don't
+	// categorize their own tokens.
+	CustomCategory
+
+	// MnemonicCategory is the category of tokens from an assembler's Mnemonics table.
+	MnemonicCategory
+
+	// DirectiveCategory is the category of tokens from an assembler's Directives table.
+	DirectiveCategory
+
+	// RegisterCategory is the category of tokens from an assembler's Registers table.
+	RegisterCategory
+
+	// LabelCategory is the category of an assembler's local and anonymous labels.
+	LabelCategory
+)
+
+var categoryNames = [...]string{
+	NoCategory:         "NoCategory",
+	KeywordCategory:    "Keyword",
+	OperatorCategory:   "Operator",
+	SymbolCategory:     "Symbol",
+	LiteralCategory:    "Literal",
+	IdentifierCategory: "Identifier",
+	CommentCategory:    "Comment",
+	TriviaCategory:     "Trivia",
+	CustomCategory:     "Custom",
+	MnemonicCategory:   "Mnemonic",
+	DirectiveCategory:  "Directive",
+	RegisterCategory:   "Register",
+	LabelCategory:      "Label",
+}
+
+// String returns the name of the category, e.g. "Keyword".
+func (c Category) String() string {
+	if int(c) < len(categoryNames) {
+		return categoryNames[c]
+	}
+	return "Category(" + strconv.Itoa(int(c)) + ")"
+}
+
+// TokenSet is a set of token identifiers, stored as a bitset, for FIRST and FOLLOW set
+// style parsing. The reserved range from FirstReservedType has a small bitset of its own, so
+// that a set holding, e.g., IdentifierType is no bigger than the custom identifiers in it.
+// The zero value is an empty set.
+type TokenSet struct {
+	words    []uint64                  // Identifiers below FirstReservedType
+	reserved [reservedTypeWords]uint64 // Identifiers from FirstReservedType
+}
+
+// reservedTypeWords is the number of words of the bitset of the reserved range.
+const reservedTypeWords = (math.MaxInt16 - int(FirstReservedType) + 64) / 64
+
+// NewTokenSet returns a set of the given token identifiers.
+func NewTokenSet(ids ...TokenIdentifier) TokenSet {
+	var s TokenSet
+	s.Add(ids...)
+	return s
+}
+
+// Add adds token identifiers to the set. Negative identifiers are ignored.
+func (s *TokenSet) Add(ids ...TokenIdentifier) {
+	for _, id := range ids {
+		switch {
+		case id < 0:
+			continue
+		case id >= FirstReservedType:
+			bit := int(id - FirstReservedType)
+			s.reserved[bit/64] |= 1 << (uint(bit) % 64)
+			continue
+		}
+		word := int(id) / 64
+		if word >= len(s.words) {
+			s.words = append(s.words, make([]uint64, word+1-len(s.words))...)
+		}
+		s.words[word] |= 1 << (uint(id) % 64)
+	}
+}
+
+// Contains reports whether id is in the set.
+func (s TokenSet) Contains(id TokenIdentifier) bool {
+	if id >= FirstReservedType {
+		bit := int(id - FirstReservedType)
+		return s.reserved[bit/64]&(1<<(uint(bit)%64)) != 0
+	}
+	word := int(id) / 64
+	return id >= 0 && word < len(s.words) && s.words[word]&(1<<(uint(id)%64)) != 0
+}
+
+// Union returns a new set of the identifiers in s or any of the other sets.
+func (s TokenSet) Union(others ...TokenSet) TokenSet {
+	union := TokenSet{words: append([]uint64(nil), s.words...), reserved: s.reserved}
+	for _, other := range others {
+		if len(other.words) > len(union.words) {
+			union.words = append(union.words, make([]uint64, len(other.words)-len(union.words))...)
+		}
+		for i, word := range other.words {
+			union.words[i] |= word
+		}
+		for i, word := range other.reserved {
+			union.reserved[i] |= word
+		}
+	}
+	return union
+}
+
+// Len returns the number of identifiers in the set.
+func (s TokenSet) Len() int {
+	n := 0
+	for _, word := range s.words {
+		n += bits.OnesCount64(word)
+	}
+	for _, word := range s.reserved {
+		n += bits.OnesCount64(word)
+	}
+	return n
+}
+
+// IDs returns the identifiers in the set in ascending order.
+func (s TokenSet) IDs() []TokenIdentifier {
+	ids := make([]TokenIdentifier, 0, s.Len())
+	ids = appendBits(ids, s.words, 0)
+	return appendBits(ids, s.reserved[:], FirstReservedType)
+}
+
+// appendBits appends the identifiers of the bits set in words, the first bit being first.
+func appendBits(ids []TokenIdentifier, words []uint64, first TokenIdentifier) []TokenIdentifier {
+	for i, word := range words {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			ids = append(ids, first+TokenIdentifier(i*64+bit))
+			word &= word - 1
+		}
+	}
+	return ids
+}
+
+// TokenSet returns the identifiers of the language's tokens in a category: the Keywords,
+// Operators or Symbols tables, the standard literals, end of line and end of file for
+// TriviaCategory, CommentType if the language keeps comments, or the tables and labels of
+// an Assembler profile. Other categories aren't known from the configuration and return an
+// empty set.
+func (ll *LanguageConfig) TokenSet(category Category) TokenSet {
+	var s TokenSet
+	switch category {
+	case KeywordCategory:
+		for _, id := range ll.Keywords {
+			s.Add(id)
+		}
+	case OperatorCategory:
+		for _, id := range ll.Operators {
+			s.Add(id)
+		}
+	case SymbolCategory:
+		for _, id := range ll.Symbols {
+			s.Add(id)
+		}
+	case LiteralCategory:
+		s.Add(IntegerLiteral, NumberLiteral, HexLiteral, StringLiteral)
+		if ll.LineNumberLabels {
+			s.Add(LineNumberType)
+		}
+		if ll.Parameters != "" {
+			s.Add(ParameterType)
+		}
+	case CommentCategory:
+		if ll.KeepComments {
+			s.Add(CommentType)
+		}
+	case TriviaCategory:
+		s.Add(EndOfLineType, EOFType)
+	case MnemonicCategory, DirectiveCategory, RegisterCategory, LabelCategory:
+		if asm := ll.Assembler; asm != nil {
+			s = asm.tokenSet(category)
+		}
+	}
+	return s
+}
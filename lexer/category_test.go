@@ -0,0 +1,89 @@
+package lexer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenCategories(t *testing.T) {
+	tokens, err := NewBasicLexer().Tokenize(strings.NewReader(`if a <= $10 + "s"`), "testfile")
+	require.NoError(t, err)
+
+	categories := make([]lexer.Category, len(tokens))
+	for i, token := range tokens {
+		categories[i] = token.Category
+	}
+	require.Equal(t, []lexer.Category{
+		lexer.KeywordCategory, lexer.IdentifierCategory, lexer.OperatorCategory, lexer.LiteralCategory,
+		lexer.SymbolCategory, lexer.LiteralCategory, lexer.TriviaCategory, lexer.TriviaCategory,
+	}, categories)
+}
+
+func TestTokenCreatorCategoryIsKept(t *testing.T) {
+	config := lexer.NewLanguage("registers").
+		TokenCreator(func(identifier string) lexer.Token {
+			token := lexer.NewToken(lexer.LastStdLiteral, identifier, nil)
+			token.Category = lexer.KeywordCategory
+			return token
+		}).
+		MustBuild()
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine("x", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, lexer.KeywordCategory, tokens[0].Category)
+}
+
+func TestCategoryString(t *testing.T) {
+	require.Equal(t, "Keyword", lexer.KeywordCategory.String())
+	require.Equal(t, "Category(200)", lexer.Category(200).String())
+}
+
+func TestTokenSet(t *testing.T) {
+	var empty lexer.TokenSet
+	require.False(t, empty.Contains(lexer.NullType))
+	require.Equal(t, 0, empty.Len())
+
+	statements := lexer.NewTokenSet(IfStatementToken, PrintStatementToken)
+	literals := lexer.NewTokenSet(lexer.IntegerLiteral, lexer.StringLiteral, -1)
+	first := statements.Union(literals)
+
+	require.True(t, first.Contains(IfStatementToken))
+	require.True(t, first.Contains(lexer.StringLiteral))
+	require.False(t, first.Contains(AddSymbolToken))
+	require.False(t, first.Contains(-1))
+	require.False(t, statements.Contains(lexer.StringLiteral), "Union must not modify its receiver")
+	require.Equal(t, []lexer.TokenIdentifier{
+		lexer.IntegerLiteral, lexer.StringLiteral, PrintStatementToken, IfStatementToken,
+	}, first.IDs())
+
+	first.Add(0x7FFF)
+	require.True(t, first.Contains(0x7FFF))
+	require.Equal(t, 5, first.Len())
+
+	// Reserved identifiers are kept apart from the others
+	reserved := lexer.NewTokenSet(lexer.CommentType, lexer.IdentifierType)
+	require.False(t, reserved.Contains(lexer.CommentType-lexer.FirstReservedType))
+	union := reserved.Union(statements, first)
+	require.Equal(t, []lexer.TokenIdentifier{
+		lexer.IntegerLiteral, lexer.StringLiteral, PrintStatementToken, IfStatementToken,
+		lexer.IdentifierType, lexer.CommentType, 0x7FFF,
+	}, union.IDs())
+	require.Equal(t, 7, union.Len())
+	require.False(t, statements.Contains(lexer.CommentType))
+}
+
+func TestLanguageTokenSet(t *testing.T) {
+	config := NewBasicLanguage()
+	keywords := config.TokenSet(lexer.KeywordCategory)
+	require.Equal(t, len(KeywordTokens), keywords.Len())
+	require.True(t, keywords.Contains(IfStatementToken))
+	require.False(t, keywords.Contains(AddSymbolToken))
+
+	require.True(t, config.TokenSet(lexer.OperatorCategory).Contains(LessThanOrEqualToken))
+	require.True(t, config.TokenSet(lexer.SymbolCategory).Contains(AddSymbolToken))
+	require.True(t, config.TokenSet(lexer.LiteralCategory).Contains(lexer.HexLiteral))
+	require.Equal(t, 0, config.TokenSet(lexer.CommentCategory).Len())
+}
@@ -0,0 +1,192 @@
+// Package charset decodes the 8-bit character sets of retro computers, so that source files
+// exported from emulators can be lexed directly. Each Charset is an encoding.Encoding for use
+// with lexer.WithCharset:
+//
+//	l := lexer.NewLexer(config, lexer.WithCharset(charset.PETSCIIUpper))
+//
+// Printable characters decode to their Unicode equivalents, e.g. PETSCII's pound sign to '£'.
+// Control codes decode to mnemonics in braces, as used by BASIC listings and tools such as
+// petcat: PETSCII's clear screen is "{clr}" and reverse on is "{rvs on}". Characters without
+// a Unicode equivalent or a mnemonic decode to their code in hex, e.g. "{$a6}". Encoding
+// accepts the same forms, with mnemonics in any case.
+package charset
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/transform"
+)
+
+// Latin1 is ISO 8859-1, the character set of many 8-bit micros' text files and printers.
+var Latin1 encoding.Encoding = charmap.ISO8859_1
+
+// Charset is an 8-bit character set with mnemonics for its control codes.
+type Charset struct {
+	name   string
+	decode [256]string     // Text of each code
+	encode map[string]byte // Folded mnemonic or rune to code
+}
+
+// newCharset returns a character set decoding codes 0x20 to 0x7E as ASCII, and every other
+// code as its hex form until set by the caller.
+func newCharset(name string) *Charset {
+	c := &Charset{name: name, encode: make(map[string]byte)}
+	for code := 0; code < 256; code++ {
+		if code >= 0x20 && code < 0x7F {
+			c.set(byte(code), string(rune(code)))
+		} else {
+			c.unset(byte(code))
+		}
+	}
+	return c
+}
+
+// set decodes code as text, a rune or a mnemonic in braces, and encodes text as code.
+func (c *Charset) set(code byte, text string) {
+	c.alias(code, text)
+	c.encode[foldMnemonic(text)] = code
+}
+
+// alias decodes code as text, which encodes to another code.
+func (c *Charset) alias(code byte, text string) {
+	if old, found := c.encode[foldMnemonic(c.decode[code])]; found && old == code {
+		delete(c.encode, foldMnemonic(c.decode[code]))
+	}
+	c.decode[code] = text
+}
+
+// unset decodes code as its hex form.
+func (c *Charset) unset(code byte) {
+	c.alias(code, hexMnemonic(code))
+}
+
+// setRange decodes the codes from first as the runes of text.
+func (c *Charset) setRange(first byte, text string) {
+	code := first
+	for _, r := range text {
+		c.set(code, string(r))
+		code++
+	}
+}
+
+// setMnemonics decodes codes as mnemonics, given without their braces.
+func (c *Charset) setMnemonics(mnemonics map[byte]string) {
+	for code, mnemonic := range mnemonics {
+		c.set(code, "{"+mnemonic+"}")
+	}
+}
+
+// String returns the name of the character set.
+func (c *Charset) String() string {
+	return c.name
+}
+
+// DecodeByte returns the text of a code: a rune, or a mnemonic in braces.
+func (c *Charset) DecodeByte(code byte) string {
+	return c.decode[code]
+}
+
+// EncodeText returns the code of a rune or a mnemonic in braces, e.g. "{CLR}".
+func (c *Charset) EncodeText(text string) (byte, bool) {
+	code, found := c.encode[foldMnemonic(text)]
+	if !found && isMnemonic(text) && len(text) == 5 && text[1] == '$' {
+		n, err := strconv.ParseUint(text[2:4], 16, 8)
+		return byte(n), err == nil
+	}
+	return code, found
+}
+
+// NewDecoder returns a decoder from the character set to UTF-8.
+func (c *Charset) NewDecoder() *encoding.Decoder {
+	return &encoding.Decoder{Transformer: &decoder{charset: c}}
+}
+
+// NewEncoder returns an encoder from UTF-8 to the character set. Text that the character set
+// can't represent is an error.
+func (c *Charset) NewEncoder() *encoding.Encoder {
+	return &encoding.Encoder{Transformer: &encoder{charset: c}}
+}
+
+type decoder struct {
+	transform.NopResetter
+	charset *Charset
+}
+
+func (d *decoder) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		text := d.charset.decode[src[nSrc]]
+		if nDst+len(text) > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += copy(dst[nDst:], text)
+		nSrc++
+	}
+	return nDst, nSrc, nil
+}
+
+type encoder struct {
+	transform.NopResetter
+	charset *Charset
+}
+
+// maxMnemonicLength is the length of the longest mnemonic, in braces, that the encoder looks for.
+const maxMnemonicLength = 16
+
+func (e *encoder) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		if nDst >= len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+
+		if src[nSrc] == '{' {
+			end := bytes.IndexByte(src[nSrc:min(len(src), nSrc+maxMnemonicLength)], '}')
+			if end < 0 && !atEOF && len(src)-nSrc < maxMnemonicLength {
+				return nDst, nSrc, transform.ErrShortSrc
+			}
+			if end > 0 {
+				if code, found := e.charset.EncodeText(string(src[nSrc : nSrc+end+1])); found {
+					dst[nDst] = code
+					nDst++
+					nSrc += end + 1
+					continue
+				}
+			}
+		}
+
+		r, size := utf8.DecodeRune(src[nSrc:])
+		if r == utf8.RuneError && size <= 1 && !atEOF && !utf8.FullRune(src[nSrc:]) {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		code, found := e.charset.encode[string(src[nSrc:nSrc+size])]
+		if !found {
+			return nDst, nSrc, fmt.Errorf("charset: %q can't be encoded in %s", r, e.charset.name)
+		}
+		dst[nDst] = code
+		nDst++
+		nSrc += size
+	}
+	return nDst, nSrc, nil
+}
+
+// hexMnemonic returns the mnemonic of a code without a name, e.g. "{$a6}".
+func hexMnemonic(code byte) string {
+	return fmt.Sprintf("{$%02x}", code)
+}
+
+func isMnemonic(text string) bool {
+	return len(text) > 2 && text[0] == '{' && text[len(text)-1] == '}'
+}
+
+// foldMnemonic lower cases mnemonics so that "{CLR}" and "{clr}" are the same.
+func foldMnemonic(text string) string {
+	if isMnemonic(text) {
+		return strings.ToLower(text)
+	}
+	return text
+}
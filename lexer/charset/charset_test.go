@@ -0,0 +1,79 @@
+package charset_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/jrsteele09/go-lexer/lexer/charset"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding"
+)
+
+func decode(t *testing.T, enc encoding.Encoding, src []byte) string {
+	t.Helper()
+	text, err := enc.NewDecoder().Bytes(src)
+	require.NoError(t, err)
+	return string(text)
+}
+
+func TestPETSCII(t *testing.T) {
+	src := []byte{'1', '0', ' ', 0x99, ' ', '"', 0x93, 0x12, 'H', 'I', 0x92, 0x5C, '"', 0x3B, 0xFF, 0x0D}
+	require.Equal(t, "10 {lgrn} \"{clr}{rvs on}HI{rvs off}£\";π\r", decode(t, charset.PETSCIIUpper, src))
+
+	require.Equal(t, "Hello {$a6}", decode(t, charset.PETSCIILower, []byte{0xC8, 'E', 'L', 'L', 'O', ' ', 0xA6}))
+	require.Equal(t, "Hi", decode(t, charset.PETSCIILower, []byte{0x68, 0x49}))
+}
+
+func TestEncode(t *testing.T) {
+	encoded, err := charset.PETSCIIUpper.NewEncoder().Bytes([]byte("PRINT \"{CLR}{rvs on}£{$a6}\";π"))
+	require.NoError(t, err)
+	require.Equal(t, "PRINT \"\x93\x12\x5C\xA6\";\xFF", string(encoded))
+
+	_, err = charset.PETSCIIUpper.NewEncoder().Bytes([]byte("{unknown}"))
+	require.ErrorContains(t, err, "can't be encoded in PETSCII (upper case)")
+
+	encoded, err = charset.PETSCIILower.NewEncoder().Bytes([]byte("Hello"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xC8, 'E', 'L', 'L', 'O'}, encoded)
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, cs := range []*charset.Charset{charset.PETSCIIUpper, charset.PETSCIILower, charset.ATASCII, charset.ZXSpectrum} {
+		src := make([]byte, 256)
+		for i := range src {
+			src[i] = byte(i)
+		}
+		text := decode(t, cs, src)
+		encoded, err := cs.NewEncoder().Bytes([]byte(text))
+		require.NoError(t, err, cs.String())
+
+		// Codes decoding to the same text as another code encode to that code
+		for code := range src {
+			expected, found := cs.EncodeText(cs.DecodeByte(byte(code)))
+			require.True(t, found, "%s %#x", cs, code)
+			require.Equal(t, expected, encoded[code], "%s %#x", cs, code)
+		}
+	}
+}
+
+func TestOtherCharsets(t *testing.T) {
+	require.Equal(t, "{clr}♠|\n", decode(t, charset.ATASCII, []byte{0x7D, 0x7B, 0x7C, 0x9B}))
+	require.Equal(t, "£{ink}{$02}▞©\r", decode(t, charset.ZXSpectrum, []byte{0x60, 0x10, 0x02, 0x89, 0x7F, 0x0D}))
+	require.Equal(t, "café", decode(t, charset.Latin1, []byte{'c', 'a', 'f', 0xE9}))
+}
+
+func TestLexPETSCII(t *testing.T) {
+	config := lexer.NewLanguage("cbm").
+		Keyword("PRINT", lexer.LastStdLiteral).
+		Symbol(';', lexer.LastStdLiteral+1).
+		MustBuild()
+	src := []byte{'P', 'R', 'I', 'N', 'T', ' ', '"', 0x93, 'A', '"', 0x0D, 'P', 'R', 'I', 'N', 'T', 0x0D}
+
+	l := lexer.NewLexer(config, lexer.WithCharset(charset.PETSCIIUpper))
+	tokens, err := l.Tokenize(bytes.NewReader(src), "program.prg")
+	require.NoError(t, err)
+	require.Equal(t, "{clr}A", tokens[1].Value)
+	require.Equal(t, "\r", tokens[2].Literal)
+	require.Equal(t, uint(2), tokens[3].SourceLine)
+}
@@ -0,0 +1,135 @@
+package charset
+
+// PETSCII mnemonics of the Commodore 64's control codes, shared by both of its character sets.
+var petsciiMnemonics = map[byte]string{
+	0x03: "stop",
+	0x05: "wht",
+	0x08: "dish",
+	0x09: "ensh",
+	0x0E: "swlc",
+	0x11: "down",
+	0x12: "rvs on",
+	0x13: "home",
+	0x14: "del",
+	0x1C: "red",
+	0x1D: "rght",
+	0x1E: "grn",
+	0x1F: "blu",
+	0x81: "orng",
+	0x85: "f1",
+	0x86: "f3",
+	0x87: "f5",
+	0x88: "f7",
+	0x89: "f2",
+	0x8A: "f4",
+	0x8B: "f6",
+	0x8C: "f8",
+	0x8D: "sret",
+	0x8E: "swuc",
+	0x90: "blk",
+	0x91: "up",
+	0x92: "rvs off",
+	0x93: "clr",
+	0x94: "inst",
+	0x95: "brn",
+	0x96: "lred",
+	0x97: "gry1",
+	0x98: "gry2",
+	0x99: "lgrn",
+	0x9A: "lblu",
+	0x9B: "gry3",
+	0x9C: "pur",
+	0x9D: "left",
+	0x9E: "yel",
+	0x9F: "cyn",
+}
+
+// PETSCIIUpper is the Commodore 64's unshifted character set, of upper case letters and
+// graphics, in which BASIC programs are usually typed.
+var PETSCIIUpper = newPETSCII("PETSCII (upper case)", func(c *Charset) {
+	c.alias(0x7E, "π")
+	c.alias(0xDE, "π")
+	c.set(0xFF, "π")
+})
+
+// PETSCIILower is the Commodore 64's shifted character set, of lower and upper case letters.
+var PETSCIILower = newPETSCII("PETSCII (lower case)", func(c *Charset) {
+	c.setRange(0x41, "abcdefghijklmnopqrstuvwxyz")
+	for code := byte(0x61); code <= 0x7A; code++ {
+		c.alias(code, string(rune('A'+code-0x61)))
+	}
+	c.setRange(0xC1, "ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+})
+
+func newPETSCII(name string, letters func(c *Charset)) *Charset {
+	c := newCharset(name)
+	c.set(0x0D, "\r")
+	c.set(0x0A, "\n")
+	c.setRange(0x5C, "£↑←")
+	for code := byte(0x60); code < 0x80; code++ {
+		c.unset(code)
+	}
+	c.set(0xA0, "\u00a0")
+	c.setMnemonics(petsciiMnemonics)
+	letters(c)
+	return c
+}
+
+// ATASCII is the character set of the Atari 8-bit computers. Codes from 0x80 are the inverse
+// video forms of those below them, apart from the control codes.
+var ATASCII = newATASCII()
+
+func newATASCII() *Charset {
+	c := newCharset("ATASCII")
+	c.set(0x9B, "\n")
+	c.set(0x60, "♦")
+	c.set(0x7B, "♠")
+	c.setMnemonics(map[byte]string{
+		0x1B: "esc",
+		0x1C: "up",
+		0x1D: "down",
+		0x1E: "left",
+		0x1F: "rght",
+		0x7D: "clr",
+		0x7E: "bksp",
+		0x7F: "tab",
+		0x9C: "dell",
+		0x9D: "insl",
+		0x9E: "ctab",
+		0x9F: "stab",
+		0xFD: "bell",
+		0xFE: "del",
+		0xFF: "ins",
+	})
+	return c
+}
+
+// ZXSpectrum is the character set of the Sinclair ZX Spectrum. The block graphics decode to
+// Unicode block elements. The user defined graphics and BASIC keyword tokens from 0x90 have
+// no fixed form and decode to their code, e.g. "{$f5}" for PRINT. As the character set has
+// braces of its own, text such as "{ink}" encodes to the control code rather than the runes.
+var ZXSpectrum = newZXSpectrum()
+
+func newZXSpectrum() *Charset {
+	c := newCharset("ZX Spectrum")
+	c.set(0x0D, "\r")
+	c.set(0x5E, "↑")
+	c.set(0x60, "£")
+	c.set(0x7F, "©")
+	c.alias(0x80, " ")
+	c.setRange(0x81, "▝▘▀▗▐▚▜▖▞▌▛▄▟▙█")
+	c.setMnemonics(map[byte]string{
+		0x06: "comma",
+		0x08: "left",
+		0x09: "rght",
+		0x10: "ink",
+		0x11: "paper",
+		0x12: "flash",
+		0x13: "bright",
+		0x14: "inverse",
+		0x15: "over",
+		0x16: "at",
+		0x17: "tab",
+	})
+	return c
+}
@@ -1,5 +1,10 @@
 package comments
 
+import (
+	"strings"
+	"unicode/utf8"
+)
+
 type CommentParser struct {
 	commentStart string
 	commentEnd   string
@@ -35,14 +40,21 @@ func (p *CommentParser) ParseEndOfComment(r rune) bool {
 		return true
 	}
 
-	ce := []rune(p.commentEnd)
-
-	if len(p.parsedEnd) >= len(ce) || ce[len(p.parsedEnd)] != r {
+	// Keep the longest end of the parsed runes that may start the closing delimiter, e.g.
+	// the last '*' of "**" for "*/". As it's a prefix of the delimiter, it's kept as a slice
+	// of it rather than built up.
+	var buf [utf8.UTFMax]byte
+	next := buf[:utf8.EncodeRune(buf[:], r)]
+	parsed := p.parsedEnd
+	for parsed != "" && !(strings.HasPrefix(p.commentEnd, parsed) && strings.HasPrefix(p.commentEnd[len(parsed):], string(next))) {
+		_, size := utf8.DecodeRuneInString(parsed)
+		parsed = parsed[size:]
+	}
+	if parsed == "" && !strings.HasPrefix(p.commentEnd, string(next)) {
 		p.parsedEnd = ""
 		return false
 	}
-
-	p.parsedEnd += string(r)
+	p.parsedEnd = p.commentEnd[:len(parsed)+len(next)]
 	if p.parsedEnd != p.commentEnd {
 		return false
 	}
@@ -51,6 +63,12 @@ func (p *CommentParser) ParseEndOfComment(r rune) bool {
 	return true
 }
 
+// Delimiters returns the opening and closing delimiters of the comment being parsed, or
+// empty strings outside a comment.
+func (p *CommentParser) Delimiters() (open, close string) {
+	return p.commentStart, p.commentEnd
+}
+
 func (p *CommentParser) IsNewLineComment() bool {
 	return p.commentEnd == "\n"
 }
@@ -60,3 +78,21 @@ func (p *CommentParser) Reset() {
 	p.commentEnd = ""
 	p.parsedEnd = ""
 }
+
+// State is what a CommentParser knows about the comment it's in, to save and restore it,
+// e.g. at the start of each line. States are comparable.
+type State struct {
+	commentStart string
+	commentEnd   string
+	parsedEnd    string
+}
+
+// State returns the current state, e.g. the block comment left open at the end of a line.
+func (p *CommentParser) State() State {
+	return State{commentStart: p.commentStart, commentEnd: p.commentEnd, parsedEnd: p.parsedEnd}
+}
+
+// SetState restores a state returned by State.
+func (p *CommentParser) SetState(s State) {
+	p.commentStart, p.commentEnd, p.parsedEnd = s.commentStart, s.commentEnd, s.parsedEnd
+}
@@ -20,6 +20,9 @@ func TestCommentParser(t *testing.T) {
 	require.False(t, cp.IsStartOfComment("abc"))
 	require.True(t, cp.IsStartOfComment("/*"))
 	require.True(t, cp.InComment())
+	open, close := cp.Delimiters()
+	require.Equal(t, "/*", open)
+	require.Equal(t, "*/", close)
 
 	testEndOfCommentString := "abc dd eee fff*/some more text"
 	var commentEnd bool
@@ -30,3 +33,19 @@ func TestCommentParser(t *testing.T) {
 	require.True(t, commentEnd)
 	require.False(t, cp.InComment())
 }
+
+func TestCommentParserState(t *testing.T) {
+	cp := comments.NewCommentParser(testComments)
+	outside := cp.State()
+	require.True(t, cp.IsStartOfComment("/*"))
+	cp.ParseEndOfComment('*')
+	inside := cp.State()
+	require.NotEqual(t, outside, inside)
+
+	cp.SetState(outside)
+	require.False(t, cp.InComment())
+	cp.SetState(inside)
+	require.True(t, cp.InComment())
+	require.True(t, cp.ParseEndOfComment('/'))
+	require.Equal(t, outside, cp.State())
+}
@@ -0,0 +1,240 @@
+package lexer
+
+import "unicode/utf8"
+
+// Identifier classes of ASCII runes, precomputed by Compile.
+const (
+	identifierStart uint8 = 1 << iota // Valid as the first rune of an identifier
+	identifierPart                    // Valid after the first rune of an identifier
+)
+
+// trieNode is a node in the byte trie built by LanguageConfig.Compile.
+// Keywords, operators, prefix tokenizer triggers, such as the number formats "$" and "0x",
+// and comment openers share a single trie so that the longest match for any of them can
+// be found in one walk, without building substrings.
+type trieNode struct {
+	next       [256]*trieNode
+	operator   TokenIdentifier
+	isOperator bool
+	tokenizer  TokenizerFunc
+	isComment  bool
+	keyword    string // The keyword as written in Keywords, "" if the node isn't a keyword
+	keywordID  TokenIdentifier
+}
+
+func (n *trieNode) insert(s string) *trieNode {
+	node := n
+	for i := 0; i < len(s); i++ {
+		child := node.next[s[i]]
+		if child == nil {
+			child = &trieNode{}
+			node.next[s[i]] = child
+		}
+		node = child
+	}
+	return node
+}
+
+// find walks the trie along s, returning nil if s is not a path in the trie.
+func (n *trieNode) find(s string) *trieNode {
+	return trieFind(n, s)
+}
+
+func trieFind[S ~string | ~[]byte](n *trieNode, s S) *trieNode {
+	node := n
+	for i := 0; i < len(s) && node != nil; i++ {
+		node = node.next[s[i]]
+	}
+	return node
+}
+
+// findRune continues a walk from n along the UTF-8 encoding of r.
+func (n *trieNode) findRune(r rune) *trieNode {
+	if n == nil {
+		return nil
+	}
+	if r < utf8.RuneSelf {
+		return n.next[byte(r)]
+	}
+	var buf [utf8.UTFMax]byte
+	size := utf8.EncodeRune(buf[:], r)
+	node := n
+	for i := 0; i < size && node != nil; i++ {
+		node = node.next[buf[i]]
+	}
+	return node
+}
+
+// compiledLanguage holds the lookup tables generated from a LanguageConfig by Compile.
+type compiledLanguage struct {
+	root        *trieNode
+	asciiSymbol [utf8.RuneSelf]TokenIdentifier
+	isSymbol    [utf8.RuneSelf]bool
+	identifier  [utf8.RuneSelf]uint8
+}
+
+// Compile turns the Keywords, Operators, Symbols, PrefixTokenizers and Comments tables into
+// a single byte trie plus rune indexed symbol tables, which the tokenizers then use in
+// place of per-rune map lookups and substring scans.
+// The tables are a snapshot: Compile must be called again if the config's maps are
+// modified afterwards. It returns the config so it can be chained with NewLexerLanguage.
+func (ll *LanguageConfig) Compile() *LanguageConfig {
+	c := &compiledLanguage{root: &trieNode{}}
+
+	for op, id := range ll.Operators {
+		node := c.root.insert(op)
+		node.operator = id
+		node.isOperator = true
+	}
+	for prefix, tokenizer := range ll.PrefixTokenizers {
+		c.root.insert(prefix).tokenizer = tokenizer
+	}
+	for start := range ll.Comments {
+		c.root.insert(start).isComment = true
+	}
+	c.insertKeywords(ll)
+	for r, id := range ll.Symbols {
+		if r < utf8.RuneSelf {
+			c.asciiSymbol[r] = id
+			c.isSymbol[r] = true
+		}
+	}
+	for r := rune(0); r < utf8.RuneSelf; r++ {
+		if ll.identifierRule(r, 0) {
+			c.identifier[r] |= identifierStart
+		}
+		if ll.identifierRule(r, 1) {
+			c.identifier[r] |= identifierPart
+		}
+	}
+
+	ll.compiled = c
+	return ll
+}
+
+// insertKeywords adds the ASCII keywords that aren't comment openers to the trie, case
+// folded unless keywords are case-sensitive. Other keywords are left to the Keywords table.
+func (c *compiledLanguage) insertKeywords(ll *LanguageConfig) {
+	openers := make(map[string]bool, len(ll.Comments))
+	for open := range ll.Comments {
+		openers[FoldCase(open)] = true
+	}
+	for keyword, id := range ll.Keywords {
+		key := keyword
+		if ll.KeywordCase != CaseSensitive {
+			key = FoldCase(keyword)
+		}
+		if openers[FoldCase(keyword)] || !isASCII(key) {
+			continue
+		}
+		node := c.root.insert(key)
+		node.keyword, node.keywordID = keyword, id
+	}
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// keyword returns the trie node of the keyword an ASCII identifier matches, or nil if it
+// isn't in the trie, ignoring ASCII case unless keywords are case-sensitive.
+func (c *compiledLanguage) keyword(identifier []byte, keywordCase CaseSensitivity) *trieNode {
+	node := c.root
+	for i := 0; i < len(identifier) && node != nil; i++ {
+		b := identifier[i]
+		if keywordCase != CaseSensitive && 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		node = node.next[b]
+	}
+	if node == nil || node.keyword == "" {
+		return nil
+	}
+	return node
+}
+
+// isIdentifierChar reports whether r is valid at byte position pos of an identifier.
+func (ll *LanguageConfig) isIdentifierChar(r rune, pos int) bool {
+	if ll.Identifiers != nil {
+		return ll.Identifiers(r, pos)
+	}
+	if ll.compiled != nil && r >= 0 && r < utf8.RuneSelf {
+		if pos == 0 {
+			return ll.compiled.identifier[r]&identifierStart != 0
+		}
+		return ll.compiled.identifier[r]&identifierPart != 0
+	}
+	return ll.identifierRule(r, pos)
+}
+
+// mayStartComment reports whether s could be a comment opener. Without compiled tables
+// it only rules out runs longer than longest, the longestSymbolRun, leaving the decision to
+// the comment parser.
+func (ll *LanguageConfig) mayStartComment(s []byte, longest int) bool {
+	if ll.compiled != nil {
+		node := trieFind(ll.compiled.root, s)
+		return node != nil && node.isComment
+	}
+	return len(s) <= longest
+}
+
+// longestSymbolRun returns the length of the longest operator or comment opener, so that
+// without compiled tables a long run of symbols isn't searched beyond it.
+func (ll *LanguageConfig) longestSymbolRun() int {
+	longest := 0
+	for operator := range ll.Operators {
+		longest = max(longest, len(operator))
+	}
+	for start := range ll.Comments {
+		longest = max(longest, len(start))
+	}
+	return longest
+}
+
+// symbol returns the token identifier for a single rune symbol.
+func (ll *LanguageConfig) symbol(r rune) (TokenIdentifier, bool) {
+	if ll.compiled != nil && r >= 0 && r < utf8.RuneSelf {
+		return ll.compiled.asciiSymbol[r], ll.compiled.isSymbol[r]
+	}
+	id, found := ll.Symbols[r]
+	return id, found
+}
+
+// operator returns the token identifier for a multi-character operator.
+func (ll *LanguageConfig) operator(s string) (TokenIdentifier, bool) {
+	if ll.compiled != nil {
+		if node := ll.compiled.root.find(s); node != nil && node.isOperator {
+			return node.operator, true
+		}
+		return NullType, false
+	}
+	id, found := ll.Operators[s]
+	return id, found
+}
+
+// prefixTokenizer returns the prefix tokenizer registered for prefix followed by r.
+func (ll *LanguageConfig) prefixTokenizer(prefix []byte, r rune) TokenizerFunc {
+	if ll.compiled != nil {
+		if node := trieFind(ll.compiled.root, prefix).findRune(r); node != nil {
+			return node.tokenizer
+		}
+		return nil
+	}
+	return ll.PrefixTokenizers[string(prefix)+string(r)]
+}
+
+// prefixTokenizerBytes returns the prefix tokenizer registered for prefix.
+func (ll *LanguageConfig) prefixTokenizerBytes(prefix []byte) TokenizerFunc {
+	if ll.compiled != nil {
+		if node := trieFind(ll.compiled.root, prefix); node != nil {
+			return node.tokenizer
+		}
+		return nil
+	}
+	return ll.PrefixTokenizers[string(prefix)]
+}
@@ -0,0 +1,124 @@
+package lexer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	commentparser "github.com/jrsteele09/go-lexer/lexer/comments"
+	"github.com/stretchr/testify/require"
+)
+
+// benchmarkProgram exercises keywords, operators, symbols, prefix tokenizers, strings and comments.
+var benchmarkProgram = strings.Repeat(`let a = 10 + $ff * (b <= 20) // trailing comment
+for i = 1 to 10: print "hello", %0101, a$, 0x1234 <> 100.25
+loop: if a == b /* block
+comment */ next i ; asm comment
+`, 500)
+
+// TestCompiledLanguageMatchesUncompiled checks that compiling a language doesn't change the token stream.
+func TestCompiledLanguageMatchesUncompiled(t *testing.T) {
+	expected, err := NewBasicLexer().Tokenize(strings.NewReader(benchmarkProgram), "bench.bas")
+	require.NoError(t, err)
+
+	compiled := lexer.NewLexer(NewBasicLanguage().Compile())
+	actual, err := compiled.Tokenize(strings.NewReader(benchmarkProgram), "bench.bas")
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestCompiledOperators(t *testing.T) {
+	l := lexer.NewLexer(NewBasicLanguage().Compile())
+	tokens, err := l.TokenizeLine("=<>+-*/+++ <= >=", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{
+		EqualsSymbolToken, NotEqualToken, AddSymbolToken, MinusSymbolToken, MultiplySymbolToken,
+		DivideSymbolToken, IncrementToken, AddSymbolToken, LessThanOrEqualToken, GreaterThanOrEqualToken,
+		lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+}
+
+func TestCompiledKeywords(t *testing.T) {
+	source := "PRINT Print left$ LEFT$ x rem hi\nprintx rem\n"
+	for _, keywordCase := range []lexer.CaseSensitivity{lexer.CaseSensitive, lexer.CaseInsensitive, lexer.CaseInsensitiveCanonical} {
+		language := func() *lexer.LanguageConfig {
+			return lexer.NewLexerLanguage(lexer.LanguageConfig{
+				Keywords:              map[string]lexer.TokenIdentifier{"print": 10, "left$": 11, "rem": 12},
+				Comments:              map[string]string{"rem": "\n"},
+				IdentifierTermination: "$",
+				IdentifierFallback:    true,
+				KeepComments:          true,
+				KeywordCase:           keywordCase,
+			})
+		}
+		expected, err := lexer.NewLexer(language()).Tokenize(strings.NewReader(source), "testfile")
+		require.NoError(t, err)
+		actual, err := lexer.NewLexer(language().Compile()).Tokenize(strings.NewReader(source), "testfile")
+		require.NoError(t, err)
+		require.Equal(t, expected, actual, "case %d", keywordCase)
+	}
+}
+
+func BenchmarkTokenize(b *testing.B) {
+	benchmarkTokenize(b, NewBasicLanguage())
+}
+
+func BenchmarkTokenizeCompiled(b *testing.B) {
+	benchmarkTokenize(b, NewBasicLanguage().Compile())
+}
+
+// BenchmarkTokenCreator is the baseline for BenchmarkTokenize: the Lexer before Compile
+// drove a new TokenCreator for each line, rune by rune, as here.
+func BenchmarkTokenCreator(b *testing.B) {
+	language := NewBasicLanguage()
+	lines := strings.SplitAfter(benchmarkProgram, "\n")
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkProgram)))
+	for i := 0; i < b.N; i++ {
+		commentParser := commentparser.NewCommentParser(language.Comments)
+		var tokens []lexer.Token
+		for _, line := range lines {
+			tf := lexer.NewTokenCreator(commentParser, language)
+			for _, r := range line {
+				if commentParser.InComment() {
+					if commentParser.IsNewLineComment() {
+						commentParser.Reset()
+						break
+					}
+					commentParser.ParseEndOfComment(r)
+					continue
+				}
+				for {
+					lineTokens, err := tf.Tokenize(r)
+					if err != nil {
+						b.Fatal(err)
+					}
+					tokens = append(tokens, lineTokens...)
+					if !tf.HasRuneOverflow() {
+						break
+					}
+					r = tf.OverflowRune()
+				}
+			}
+		}
+	}
+}
+
+func benchmarkTokenize(b *testing.B, language *lexer.LanguageConfig) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkProgram)))
+	for i := 0; i < b.N; i++ {
+		l := lexer.NewLexer(language)
+		if _, err := l.Tokenize(strings.NewReader(benchmarkProgram), "bench.bas"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func tokenIDs(tokens []lexer.Token) []lexer.TokenIdentifier {
+	ids := make([]lexer.TokenIdentifier, len(tokens))
+	for i, t := range tokens {
+		ids[i] = t.ID
+	}
+	return ids
+}
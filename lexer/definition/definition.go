@@ -0,0 +1,482 @@
+// Package definition loads lexer language configurations from declarative JSON or YAML files,
+// so that languages can be shared with non-Go tools and changed without a rebuild.
+//
+// A definition names its token identifiers and refers to them by name from its tables:
+//
+//	name: basic
+//	tokens:
+//	  If: 0x8B
+//	  Add: 0xAA
+//	  Variable: 0xF5
+//	keywords:
+//	  if: If
+//	symbols:
+//	  "+": Add
+//	comments:
+//	  "//": "\n"
+//	  "/*": "*/"
+//	identifiers:
+//	  extended_runes: "_"
+//	  token: Variable
+//	numbers:
+//	  "$": HexTokenizer
+//	string_delimiters: "\""
+//	keyword_case: insensitive
+//	line_numbers: ascending
+//
+// Assembly languages add an assembler section, see AssemblerRules:
+//
+//	assembler:
+//	  mnemonics: {lda: Lda}
+//	  directives: {".byte": Byte}
+//	  registers: {x: X}
+//	  local_labels: "@"
+//	  local_label: LocalLabel
+//	  anonymous_labels: acme
+//	  anonymous_label: AnonymousLabel
+//	  immediate: Immediate
+//
+// Token references may also be numeric literals such as "0x8B", or the names of the lexer's
+// standard tokens such as "StringLiteral"; "token: IdentifierType" gives languages a generic
+// identifier token. Number formats reference prefix tokenizers by
+// name: the built-in ones, or any registered with lexer.RegisterTokenizer.
+package definition
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the encoding of a definition file.
+type Format int
+
+const (
+	// JSON is a definition encoded as JSON.
+	JSON Format = iota
+	// YAML is a definition encoded as YAML.
+	YAML
+)
+
+// Definition is a declarative language definition.
+type Definition struct {
+	Name              string             `json:"name" yaml:"name"`
+	Tokens            map[string]TokenID `json:"tokens" yaml:"tokens"`       // Token names to identifiers
+	Keywords          map[string]string  `json:"keywords" yaml:"keywords"`   // Keyword to token reference
+	Operators         map[string]string  `json:"operators" yaml:"operators"` // Multi-character operator to token reference
+	Symbols           map[string]string  `json:"symbols" yaml:"symbols"`     // Single-rune symbol to token reference
+	Comments          map[string]string  `json:"comments" yaml:"comments"`   // Comment open to close delimiter
+	Identifiers       IdentifierRules    `json:"identifiers" yaml:"identifiers"`
+	Numbers           map[string]string  `json:"numbers" yaml:"numbers"` // Number prefix to tokenizer name, e.g. "0x": HexTokenizer
+	StringDelimiters  string             `json:"string_delimiters" yaml:"string_delimiters"`
+	Whitespace        string             `json:"whitespace" yaml:"whitespace"`                 // Runes separating tokens, any Unicode white space if empty
+	StringEscapes     string             `json:"string_escapes" yaml:"string_escapes"`         // "backslash" (the default) or "doubled", e.g. 'it''s'
+	DollarQuotes      bool               `json:"dollar_quotes" yaml:"dollar_quotes"`           // PostgreSQL's $tag$...$tag$ strings
+	QuotedIdentifiers map[string]string  `json:"quoted_identifiers" yaml:"quoted_identifiers"` // Quoted identifier open to close delimiter, e.g. "[": "]"
+	Parameters        string             `json:"parameters" yaml:"parameters"`                 // Runes starting parameters, e.g. ":$?"
+	KeepComments      bool               `json:"keep_comments" yaml:"keep_comments"`           // Emit comments as CommentType tokens
+	KeywordCase       string             `json:"keyword_case" yaml:"keyword_case"`             // "sensitive" (the default), "insensitive" or "canonical"
+	LineNumbers       string             `json:"line_numbers" yaml:"line_numbers"`             // "labels" or "ascending" for LineNumberType tokens, see lexer.LanguageConfig
+	Assembler         *AssemblerRules    `json:"assembler" yaml:"assembler"`
+}
+
+// AssemblerRules describe an assembly language, see lexer.AssemblerProfile.
+type AssemblerRules struct {
+	Mnemonics       map[string]string `json:"mnemonics" yaml:"mnemonics"`               // Mnemonic to token reference
+	Directives      map[string]string `json:"directives" yaml:"directives"`             // Directive, with its prefix, to token reference
+	Registers       map[string]string `json:"registers" yaml:"registers"`               // Register to token reference
+	LocalLabels     string            `json:"local_labels" yaml:"local_labels"`         // Runes starting local labels, e.g. "@"
+	LocalLabel      string            `json:"local_label" yaml:"local_label"`           // Token reference for local labels
+	AnonymousLabels string            `json:"anonymous_labels" yaml:"anonymous_labels"` // "acme" or "ca65"
+	AnonymousLabel  string            `json:"anonymous_label" yaml:"anonymous_label"`   // Token reference for anonymous labels
+	Immediate       string            `json:"immediate" yaml:"immediate"`               // Token reference for the '#' immediate prefix
+}
+
+// IdentifierRules describe how identifiers are recognised and which tokens they produce.
+type IdentifierRules struct {
+	ExtendedRunes string            `json:"extended_runes" yaml:"extended_runes"` // Extra runes valid inside an identifier
+	Termination   string            `json:"termination" yaml:"termination"`       // Runes that end an identifier and are included in it
+	Token         string            `json:"token" yaml:"token"`                   // Token reference for identifiers that aren't keywords
+	Suffixes      map[string]string `json:"suffixes" yaml:"suffixes"`             // Identifier suffix to token reference, e.g. "$": StringVariable
+	Fold          bool              `json:"fold" yaml:"fold"`                     // Case fold identifiers, see lexer.FoldCase
+	Sigils        string            `json:"sigils" yaml:"sigils"`                 // Runes that may start an identifier in addition to letters, e.g. "$@"
+	Profile       string            `json:"profile" yaml:"profile"`               // "default", "uax31" or "ascii", see lexer.IdentifierProfile
+	NFC           bool              `json:"nfc" yaml:"nfc"`                       // Normalize identifiers to Unicode NFC
+}
+
+// keywordCases maps the keyword_case values of a definition to the lexer's case sensitivities.
+var keywordCases = map[string]lexer.CaseSensitivity{
+	"":            lexer.CaseSensitive,
+	"sensitive":   lexer.CaseSensitive,
+	"insensitive": lexer.CaseInsensitive,
+	"canonical":   lexer.CaseInsensitiveCanonical,
+}
+
+// anonymousLabelStyles maps the anonymous_labels values of a definition to the lexer's styles.
+var anonymousLabelStyles = map[string]lexer.AnonymousLabelStyle{
+	"":     lexer.NoAnonymousLabels,
+	"acme": lexer.ACMEAnonymousLabels,
+	"ca65": lexer.CA65AnonymousLabels,
+}
+
+// identifierProfiles maps the identifier profile values of a definition to the lexer's profiles.
+var identifierProfiles = map[string]lexer.IdentifierProfile{
+	"":        lexer.DefaultIdentifiers,
+	"default": lexer.DefaultIdentifiers,
+	"uax31":   lexer.UAX31Identifiers,
+	"ascii":   lexer.ASCIIIdentifiers,
+}
+
+// TokenID is a token identifier in a definition file, written as a number or a numeric string
+// such as "0x8B".
+type TokenID lexer.TokenIdentifier
+
+// UnmarshalJSON decodes a TokenID from a JSON number or string.
+func (id *TokenID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+	return id.parse(s)
+}
+
+// UnmarshalYAML decodes a TokenID from a YAML scalar.
+func (id *TokenID) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: token identifier must be a scalar", node.Line)
+	}
+	return id.parse(node.Value)
+}
+
+func (id *TokenID) parse(s string) error {
+	value, err := strconv.ParseInt(strings.TrimSpace(s), 0, 16)
+	if err != nil {
+		return fmt.Errorf("invalid token identifier %q", s)
+	}
+	*id = TokenID(value)
+	return nil
+}
+
+// Parse decodes a definition in the given format.
+func Parse(data []byte, format Format) (*Definition, error) {
+	var def Definition
+	switch format {
+	case JSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&def); err != nil {
+			return nil, fmt.Errorf("definition: %w", err)
+		}
+	case YAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&def); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("definition: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("definition: unknown format %d", format)
+	}
+	return &def, nil
+}
+
+// Read decodes a definition in the given format from r.
+func Read(r io.Reader, format Format) (*Definition, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("definition: %w", err)
+	}
+	return Parse(data, format)
+}
+
+// ReadFile decodes a definition file, choosing the format from its extension:
+// ".json" for JSON, ".yaml" or ".yml" for YAML.
+func ReadFile(path string) (*Definition, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("definition: %w", err)
+	}
+	def, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return def, nil
+}
+
+// LoadFile reads a definition file and returns its language configuration.
+func LoadFile(path string) (*lexer.LanguageConfig, error) {
+	def, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := def.LanguageConfig()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// MustLoadFile is like LoadFile but panics if the definition can't be loaded.
+func MustLoadFile(path string) *lexer.LanguageConfig {
+	config, err := LoadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	return config
+}
+
+// FormatFromPath returns the format of a definition file from its extension.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return JSON, nil
+	case ".yaml", ".yml":
+		return YAML, nil
+	}
+	return 0, fmt.Errorf("definition: unknown file extension for %s", path)
+}
+
+// TokenID resolves a token reference: a token name from the definition, a standard token
+// name, or a numeric literal.
+func (d *Definition) TokenID(ref string) (lexer.TokenIdentifier, error) {
+	if id, found := d.Tokens[ref]; found {
+		return lexer.TokenIdentifier(id), nil
+	}
+	for _, id := range lexer.StandardTokens() {
+		if id.String() == ref {
+			return id, nil
+		}
+	}
+	var id TokenID
+	if err := id.parse(ref); err != nil {
+		return lexer.NullType, fmt.Errorf("unknown token %q", ref)
+	}
+	return lexer.TokenIdentifier(id), nil
+}
+
+// LanguageConfig builds the lexer configuration described by the definition.
+func (d *Definition) LanguageConfig() (*lexer.LanguageConfig, error) {
+	config := lexer.LanguageConfig{
+		Name:                    d.Name,
+		Keywords:                make(map[string]lexer.TokenIdentifier, len(d.Keywords)),
+		Operators:               make(map[string]lexer.TokenIdentifier, len(d.Operators)),
+		Symbols:                 make(map[rune]lexer.TokenIdentifier, len(d.Symbols)),
+		Comments:                make(map[string]string, len(d.Comments)),
+		PrefixTokenizers:        make(map[string]lexer.TokenizerFunc, len(d.Numbers)),
+		ExtendedIdentifierRunes: d.Identifiers.ExtendedRunes,
+		IdentifierTermination:   d.Identifiers.Termination,
+		StringDelimiters:        d.StringDelimiters,
+		Whitespace:              d.Whitespace,
+		DollarQuotedStrings:     d.DollarQuotes,
+		Parameters:              d.Parameters,
+		KeepComments:            d.KeepComments,
+		TokenNames:              make(map[lexer.TokenIdentifier]string, len(d.Tokens)),
+		FoldIdentifiers:         d.Identifiers.Fold,
+		NormalizeIdentifiers:    d.Identifiers.NFC,
+	}
+
+	profile, found := identifierProfiles[d.Identifiers.Profile]
+	if !found {
+		return nil, fmt.Errorf("unknown identifier profile %q", d.Identifiers.Profile)
+	}
+	config.IdentifierProfile = profile
+
+	if sigils := d.Identifiers.Sigils; sigils != "" {
+		extended, termination := config.ExtendedIdentifierRunes, config.IdentifierTermination
+		config.Identifiers = func(r rune, pos int) bool {
+			return (pos == 0 && strings.ContainsRune(sigils, r)) || profile.IsIdentifierChar(r, pos, extended, termination)
+		}
+	}
+
+	keywordCase, found := keywordCases[d.KeywordCase]
+	if !found {
+		return nil, fmt.Errorf("unknown keyword case %q", d.KeywordCase)
+	}
+	config.KeywordCase = keywordCase
+
+	switch d.StringEscapes {
+	case "", "backslash":
+	case "doubled":
+		config.DoubledQuoteEscapes = true
+	default:
+		return nil, fmt.Errorf("unknown string escapes %q", d.StringEscapes)
+	}
+
+	switch d.LineNumbers {
+	case "":
+	case "labels", "ascending":
+		config.LineNumberLabels = true
+		config.AscendingLineNumbers = d.LineNumbers == "ascending"
+	default:
+		return nil, fmt.Errorf("unknown line numbers %q", d.LineNumbers)
+	}
+
+	for name, id := range d.Tokens {
+		config.TokenNames[lexer.TokenIdentifier(id)] = name
+	}
+
+	for keyword, ref := range d.Keywords {
+		id, err := d.TokenID(ref)
+		if err != nil {
+			return nil, fmt.Errorf("keyword %q: %w", keyword, err)
+		}
+		config.Keywords[keyword] = id
+	}
+	for operator, ref := range d.Operators {
+		id, err := d.TokenID(ref)
+		if err != nil {
+			return nil, fmt.Errorf("operator %q: %w", operator, err)
+		}
+		config.Operators[operator] = id
+	}
+	for symbol, ref := range d.Symbols {
+		r, size := utf8.DecodeRuneInString(symbol)
+		if size == 0 || size != len(symbol) {
+			return nil, fmt.Errorf("symbol %q must be a single rune", symbol)
+		}
+		id, err := d.TokenID(ref)
+		if err != nil {
+			return nil, fmt.Errorf("symbol %q: %w", symbol, err)
+		}
+		config.Symbols[r] = id
+	}
+	for open, close := range d.Comments {
+		config.Comments[open] = close
+	}
+	if len(d.QuotedIdentifiers) > 0 {
+		config.QuotedIdentifiers = make(map[rune]rune, len(d.QuotedIdentifiers))
+	}
+	for open, close := range d.QuotedIdentifiers {
+		openRune, openSize := utf8.DecodeRuneInString(open)
+		closeRune, closeSize := utf8.DecodeRuneInString(close)
+		if openSize == 0 || openSize != len(open) || closeSize == 0 || closeSize != len(close) {
+			return nil, fmt.Errorf("quoted identifier %q, %q must be single runes", open, close)
+		}
+		config.QuotedIdentifiers[openRune] = closeRune
+	}
+	for prefix, name := range d.Numbers {
+		tokenizer, found := lexer.TokenizerByName(name)
+		if !found {
+			return nil, fmt.Errorf("number prefix %q: unknown tokenizer %q", prefix, name)
+		}
+		config.PrefixTokenizers[prefix] = tokenizer
+	}
+
+	creators, err := d.identifierCreators()
+	if err != nil {
+		return nil, err
+	}
+	config.TokenCreators = creators
+
+	if d.Assembler != nil {
+		if config.Assembler, err = d.assemblerProfile(); err != nil {
+			return nil, err
+		}
+	}
+
+	return lexer.NewLexerLanguage(config), nil
+}
+
+// assemblerProfile builds the assembler profile described by the assembler rules.
+func (d *Definition) assemblerProfile() (*lexer.AssemblerProfile, error) {
+	rules := d.Assembler
+	style, found := anonymousLabelStyles[rules.AnonymousLabels]
+	if !found {
+		return nil, fmt.Errorf("unknown anonymous labels %q", rules.AnonymousLabels)
+	}
+	asm := &lexer.AssemblerProfile{LocalLabelPrefixes: rules.LocalLabels, AnonymousLabels: style}
+
+	table := func(kind string, refs map[string]string) (map[string]lexer.TokenIdentifier, error) {
+		ids := make(map[string]lexer.TokenIdentifier, len(refs))
+		for name, ref := range refs {
+			id, err := d.TokenID(ref)
+			if err != nil {
+				return nil, fmt.Errorf("%s %q: %w", kind, name, err)
+			}
+			ids[name] = id
+		}
+		return ids, nil
+	}
+	var err error
+	if asm.Mnemonics, err = table("mnemonic", rules.Mnemonics); err != nil {
+		return nil, err
+	}
+	if asm.Directives, err = table("directive", rules.Directives); err != nil {
+		return nil, err
+	}
+	if asm.Registers, err = table("register", rules.Registers); err != nil {
+		return nil, err
+	}
+
+	for _, token := range []struct {
+		kind string
+		ref  string
+		id   *lexer.TokenIdentifier
+	}{
+		{"local label", rules.LocalLabel, &asm.LocalLabel},
+		{"anonymous label", rules.AnonymousLabel, &asm.AnonymousLabel},
+		{"immediate", rules.Immediate, &asm.Immediate},
+	} {
+		if token.ref == "" {
+			continue
+		}
+		if *token.id, err = d.TokenID(token.ref); err != nil {
+			return nil, fmt.Errorf("%s: %w", token.kind, err)
+		}
+	}
+	return asm, nil
+}
+
+// identifierCreators returns token creators for the identifier rules: suffix rules first,
+// longest suffix first, then the default identifier token.
+func (d *Definition) identifierCreators() ([]func(string) lexer.Token, error) {
+	var creators []func(string) lexer.Token
+
+	suffixes := make([]string, 0, len(d.Identifiers.Suffixes))
+	for suffix := range d.Identifiers.Suffixes {
+		suffixes = append(suffixes, suffix)
+	}
+	sort.Slice(suffixes, func(i, j int) bool {
+		if len(suffixes[i]) != len(suffixes[j]) {
+			return len(suffixes[i]) > len(suffixes[j])
+		}
+		return suffixes[i] < suffixes[j]
+	})
+	for _, suffix := range suffixes {
+		id, err := d.TokenID(d.Identifiers.Suffixes[suffix])
+		if err != nil {
+			return nil, fmt.Errorf("identifier suffix %q: %w", suffix, err)
+		}
+		suffix := suffix
+		creators = append(creators, func(identifier string) lexer.Token {
+			if !strings.HasSuffix(identifier, suffix) {
+				return lexer.Token{}
+			}
+			return lexer.NewToken(id, identifier, nil)
+		})
+	}
+
+	if d.Identifiers.Token != "" {
+		id, err := d.TokenID(d.Identifiers.Token)
+		if err != nil {
+			return nil, fmt.Errorf("identifier token: %w", err)
+		}
+		creators = append(creators, func(identifier string) lexer.Token {
+			return lexer.NewToken(id, identifier, nil)
+		})
+	}
+	return creators, nil
+}
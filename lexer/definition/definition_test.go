@@ -0,0 +1,296 @@
+package definition_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/jrsteele09/go-lexer/lexer/definition"
+	"github.com/stretchr/testify/require"
+)
+
+const program = `start: let a$ = "hi" // greeting
+if b <> $ff + %101 print c_1 /* done */`
+
+func TestLoadFile(t *testing.T) {
+	for _, path := range []string{"testdata/basic.yaml", "testdata/basic.json"} {
+		t.Run(path, func(t *testing.T) {
+			config, err := definition.LoadFile(path)
+			require.NoError(t, err)
+			require.Equal(t, "basic", config.Name)
+			require.Equal(t, "Variable", config.TokenName(0xF5))
+
+			tokens, err := lexer.NewLexer(config).Tokenize(strings.NewReader(program), path)
+			require.NoError(t, err)
+			require.Equal(t, []lexer.TokenIdentifier{
+				0xF2, 0x88, 0xF4, 0xB2, lexer.StringLiteral, lexer.EndOfLineType,
+				0x8B, 0xF5, 0xF9, lexer.HexLiteral, 0xAA, lexer.IntegerLiteral, 0x83, 0xF5, lexer.EndOfLineType,
+				lexer.EOFType,
+			}, tokenIDs(tokens))
+			require.Equal(t, "start:", tokens[0].Literal)
+			require.Equal(t, uint8(0xff), tokens[9].Value)
+			require.Equal(t, uint8(5), tokens[11].Value)
+			require.Equal(t, "c_1", tokens[13].Literal)
+		})
+	}
+}
+
+func TestFormatsAreEquivalent(t *testing.T) {
+	fromYAML, err := definition.ReadFile("testdata/basic.yaml")
+	require.NoError(t, err)
+	fromJSON, err := definition.ReadFile("testdata/basic.json")
+	require.NoError(t, err)
+	require.Equal(t, fromYAML, fromJSON)
+}
+
+func TestTokenReferences(t *testing.T) {
+	def, err := definition.Parse([]byte(`
+tokens:
+  Plus: 10
+keywords:
+  str: StringLiteral
+  raw: "0x20"
+symbols:
+  "+": Plus
+`), definition.YAML)
+	require.NoError(t, err)
+
+	config, err := def.LanguageConfig()
+	require.NoError(t, err)
+	require.Equal(t, lexer.StringLiteral, config.Keywords["str"])
+	require.Equal(t, lexer.TokenIdentifier(0x20), config.Keywords["raw"])
+	require.Equal(t, lexer.TokenIdentifier(10), config.Symbols['+'])
+}
+
+func TestStringDelimiters(t *testing.T) {
+	def, err := definition.Parse([]byte(`{"string_delimiters": "'"}`), definition.JSON)
+	require.NoError(t, err)
+	config, err := def.LanguageConfig()
+	require.NoError(t, err)
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine(`'a'`, "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, lexer.StringLiteral, tokens[0].ID)
+	require.Equal(t, "a", tokens[0].Value)
+}
+
+func TestWhitespace(t *testing.T) {
+	def, err := definition.Parse([]byte(`{"whitespace": " \t", "numbers": {"\"": "JSONStringTokenizer"}}`), definition.JSON)
+	require.NoError(t, err)
+	config, err := def.LanguageConfig()
+	require.NoError(t, err)
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine("\t\"a\\u0062\" ", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, "ab", tokens[0].Value)
+
+	_, err = lexer.NewLexer(config).TokenizeLine("\f\"a\"", "testfile", 0)
+	require.ErrorContains(t, err, "unknown character")
+}
+
+func TestSQLQuoting(t *testing.T) {
+	def, err := definition.Parse([]byte(`
+string_delimiters: "'"
+string_escapes: doubled
+dollar_quotes: true
+quoted_identifiers: {"[": "]"}
+parameters: ":?"
+identifiers: {token: IdentifierType}
+`), definition.YAML)
+	require.NoError(t, err)
+	config, err := def.LanguageConfig()
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine("[a b] 'it''s' $$x$$ :id ?", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{
+		lexer.IdentifierType, lexer.StringLiteral, lexer.StringLiteral, lexer.ParameterType, lexer.ParameterType, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+	require.Equal(t, "a b", tokens[0].Literal)
+	require.Equal(t, "it's", tokens[1].Value)
+
+	for _, source := range []string{`{"string_escapes": "none"}`, `{"quoted_identifiers": {"[[": "]]"}}`} {
+		def, err := definition.Parse([]byte(source), definition.JSON)
+		require.NoError(t, err)
+		_, err = def.LanguageConfig()
+		require.Error(t, err, source)
+	}
+}
+
+func TestCaseOptions(t *testing.T) {
+	def, err := definition.Parse([]byte(`
+tokens: {Let: 10, Variable: 11}
+keywords: {let: Let}
+keyword_case: canonical
+identifiers: {token: Variable, fold: true}
+`), definition.YAML)
+	require.NoError(t, err)
+	config, err := def.LanguageConfig()
+	require.NoError(t, err)
+	require.Equal(t, lexer.CaseInsensitiveCanonical, config.KeywordCase)
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine("LET Total", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, "let", tokens[0].Literal)
+	require.Equal(t, "total", tokens[1].Literal)
+
+	def.KeywordCase = "lower"
+	_, err = def.LanguageConfig()
+	require.Error(t, err)
+}
+
+func TestIdentifierSigils(t *testing.T) {
+	def, err := definition.Parse([]byte(`{"identifiers": {"sigils": "$@", "token": "IdentifierType"}}`), definition.JSON)
+	require.NoError(t, err)
+	config, err := def.LanguageConfig()
+	require.NoError(t, err)
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine("$var @attr name", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{
+		lexer.IdentifierType, lexer.IdentifierType, lexer.IdentifierType, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+	require.Equal(t, "$var", tokens[0].Literal)
+}
+
+func TestIdentifierProfile(t *testing.T) {
+	def, err := definition.Parse([]byte(`
+tokens: {Cafe: 10, Name: 11}
+keywords: {"caf\u00e9": Cafe}
+identifiers: {profile: uax31, nfc: true, token: Name}
+`), definition.YAML)
+	require.NoError(t, err)
+	config, err := def.LanguageConfig()
+	require.NoError(t, err)
+	require.Equal(t, lexer.UAX31Identifiers, config.IdentifierProfile)
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine("cafe\u0301 snake_case", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{10, 11, lexer.EndOfLineType}, tokenIDs(tokens))
+
+	def.Identifiers.Profile = "latin"
+	_, err = def.LanguageConfig()
+	require.Error(t, err)
+}
+
+func TestLineNumbers(t *testing.T) {
+	def, err := definition.Parse([]byte(`{"tokens": {"Goto": 10}, "keywords": {"goto": "Goto"}, "line_numbers": "ascending"}`), definition.JSON)
+	require.NoError(t, err)
+	config, err := def.LanguageConfig()
+	require.NoError(t, err)
+	require.True(t, config.LineNumberLabels)
+	require.True(t, config.AscendingLineNumbers)
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine("10 goto 10", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{lexer.LineNumberType, 10, lexer.IntegerLiteral, lexer.EndOfLineType}, tokenIDs(tokens))
+
+	def.LineNumbers = "descending"
+	_, err = def.LanguageConfig()
+	require.Error(t, err)
+}
+
+func TestKeepComments(t *testing.T) {
+	def, err := definition.Parse([]byte(`{"tokens": {"Rem": 10}, "keywords": {"rem": "Rem"}, "comments": {"rem": "\n"}, "keep_comments": true}`), definition.JSON)
+	require.NoError(t, err)
+	config, err := def.LanguageConfig()
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine("rem hello", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{10, lexer.CommentType, lexer.EndOfLineType}, tokenIDs(tokens))
+	require.Equal(t, " hello", tokens[1].Literal)
+}
+
+func TestAssembler(t *testing.T) {
+	def, err := definition.Parse([]byte(`
+tokens: {Lda: 10, Byte: 11, X: 12, Local: 13, Anonymous: 14, Immediate: 15, Comma: 16}
+symbols: {",": Comma}
+keyword_case: insensitive
+assembler:
+  mnemonics: {lda: Lda}
+  directives: {".byte": Byte}
+  registers: {x: X}
+  local_labels: "@"
+  local_label: Local
+  anonymous_labels: ca65
+  anonymous_label: Anonymous
+  immediate: Immediate
+`), definition.YAML)
+	require.NoError(t, err)
+	config, err := def.LanguageConfig()
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine(": LDA #1,x", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{14, 10, 15, lexer.IntegerLiteral, 16, 12, lexer.EndOfLineType}, tokenIDs(tokens))
+
+	def.Assembler.AnonymousLabels = "dasm"
+	_, err = def.LanguageConfig()
+	require.Error(t, err)
+
+	def.Assembler.AnonymousLabels = "acme"
+	def.Assembler.LocalLabel = "Missing"
+	_, err = def.LanguageConfig()
+	require.ErrorContains(t, err, `local label: unknown token "Missing"`)
+}
+
+func TestRegisteredTokenizer(t *testing.T) {
+	require.NoError(t, lexer.RegisterTokenizer("DefinitionTestHexTokenizer", lexer.HexTokenizer))
+	require.Error(t, lexer.RegisterTokenizer("DefinitionTestHexTokenizer", lexer.HexTokenizer))
+
+	def, err := definition.Parse([]byte(`numbers: {"&": DefinitionTestHexTokenizer}`), definition.YAML)
+	require.NoError(t, err)
+	config, err := def.LanguageConfig()
+	require.NoError(t, err)
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine("&1f", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, lexer.HexLiteral, tokens[0].ID)
+	require.Equal(t, uint8(0x1f), tokens[0].Value)
+}
+
+func TestInvalidDefinitions(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		format definition.Format
+	}{
+		{"unknown field", `keyword: {}`, definition.YAML},
+		{"unknown JSON field", `{"keyword": {}}`, definition.JSON},
+		{"invalid token identifier", `tokens: {If: eleven}`, definition.YAML},
+		{"unknown token", `keywords: {if: If}`, definition.YAML},
+		{"multi-rune symbol", `symbols: {"<=": 10}`, definition.YAML},
+		{"unknown tokenizer", `numbers: {"$": OctalTokenizer}`, definition.YAML},
+		{"unknown identifier token", `identifiers: {token: Variable}`, definition.YAML},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def, err := definition.Parse([]byte(tt.source), tt.format)
+			if err == nil {
+				_, err = def.LanguageConfig()
+			}
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	format, err := definition.FormatFromPath("lang.YML")
+	require.NoError(t, err)
+	require.Equal(t, definition.YAML, format)
+
+	_, err = definition.LoadFile("lang.toml")
+	require.Error(t, err)
+}
+
+func tokenIDs(tokens []lexer.Token) []lexer.TokenIdentifier {
+	ids := make([]lexer.TokenIdentifier, len(tokens))
+	for i, token := range tokens {
+		ids[i] = token.ID
+	}
+	return ids
+}
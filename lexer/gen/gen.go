@@ -0,0 +1,279 @@
+// Package gen generates standalone Go scanners from a lexer.LanguageConfig.
+//
+// The generated scanner is a dependency-free, switch-based implementation of the runtime
+// lexer with the same token IDs and semantics. Alongside it, gen can emit a differential
+// test that runs both implementations over a corpus of source files and checks that they
+// produce identical token streams.
+package gen
+
+import (
+	"bytes"
+	_ "embed"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"sort"
+	"strconv"
+	"text/template"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+)
+
+//go:embed templates/scanner.go.tmpl
+var scannerTemplate string
+
+//go:embed templates/diff_test.go.tmpl
+var diffTestTemplate string
+
+// DefaultCorpusDir is the directory the differential test reads its corpus from by default.
+const DefaultCorpusDir = "testdata/corpus"
+
+// Options controls the generated files.
+type Options struct {
+	Package      string // Package name of the generated files
+	ConfigImport string // Import path of the package providing the runtime LanguageConfig, used by the differential test
+	ConfigExpr   string // Go expression giving the runtime *lexer.LanguageConfig, e.g. "mylang.Config()"
+	CorpusDir    string // Directory of source files lexed by the differential test, DefaultCorpusDir if empty
+}
+
+// standardTokens are the lexer's standard token identifiers, re-declared by the generated scanner.
+var standardTokens = func() []namedToken {
+	var tokens []namedToken
+	for _, id := range lexer.StandardTokens() {
+		tokens = append(tokens, namedToken{Name: id.String(), ID: id})
+	}
+	return tokens
+}()
+
+// categories are the lexer's token categories, re-declared by the generated scanner.
+var categories = []namedCategory{
+	{"NoCategory", lexer.NoCategory},
+	{"KeywordCategory", lexer.KeywordCategory},
+	{"OperatorCategory", lexer.OperatorCategory},
+	{"SymbolCategory", lexer.SymbolCategory},
+	{"LiteralCategory", lexer.LiteralCategory},
+	{"IdentifierCategory", lexer.IdentifierCategory},
+	{"CommentCategory", lexer.CommentCategory},
+	{"TriviaCategory", lexer.TriviaCategory},
+	{"CustomCategory", lexer.CustomCategory},
+	{"MnemonicCategory", lexer.MnemonicCategory},
+	{"DirectiveCategory", lexer.DirectiveCategory},
+	{"RegisterCategory", lexer.RegisterCategory},
+	{"LabelCategory", lexer.LabelCategory},
+}
+
+// tokenizerStates maps the built-in prefix tokenizers to the generated scanner's states.
+var tokenizerStates = map[string]string{
+	"NumberTokenizer":     "numberState",
+	"BinaryTokenizer":     "binaryState",
+	"HexTokenizer":        "hexState",
+	"StringTokenizer":     "stringState",
+	"IdentifierTokenizer": "identifierState",
+	"SymbolTokenizer":     "symbolState",
+}
+
+type namedToken struct {
+	Name string
+	ID   lexer.TokenIdentifier
+}
+
+type namedCategory struct {
+	Name     string
+	Category lexer.Category
+}
+
+type tableEntry struct {
+	Key string
+	ID  lexer.TokenIdentifier
+}
+
+type commentEntry struct {
+	Open  string
+	Close string
+}
+
+type prefixEntry struct {
+	Key   string
+	State string
+}
+
+type templateData struct {
+	Options
+	StandardTokens          []namedToken
+	Categories              []namedCategory
+	Keywords                []tableEntry
+	Operators               []tableEntry
+	Symbols                 []tableEntry
+	Comments                []commentEntry
+	Prefixes                []prefixEntry
+	ExtendedIdentifierRunes string
+	IdentifierTermination   string
+	StringDelimiters        string
+	IdentifierFallback      bool
+}
+
+// Generate returns the gofmt'd source of a standalone scanner for config.
+func Generate(config *lexer.LanguageConfig, opts Options) ([]byte, error) {
+	data, err := newTemplateData(config, opts)
+	if err != nil {
+		return nil, err
+	}
+	return execute("scanner", scannerTemplate, data)
+}
+
+// GenerateTest returns the gofmt'd source of a differential test comparing the scanner
+// produced by Generate against the runtime lexer configured by opts.ConfigExpr.
+func GenerateTest(config *lexer.LanguageConfig, opts Options) ([]byte, error) {
+	if opts.ConfigImport == "" || opts.ConfigExpr == "" {
+		return nil, fmt.Errorf("gen: the differential test requires ConfigImport and ConfigExpr")
+	}
+	data, err := newTemplateData(config, opts)
+	if err != nil {
+		return nil, err
+	}
+	return execute("diff_test", diffTestTemplate, data)
+}
+
+// Main is the entry point for a go:generate program producing a scanner for config, e.g.
+//
+//	func main() { gen.Main(mylang.Config()) }
+//
+// It reads its options from the command line, see Run.
+func Main(config *lexer.LanguageConfig) {
+	if err := Run(config, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Run parses args as command line flags and writes the scanner, and optionally the
+// differential test, for config.
+func Run(config *lexer.LanguageConfig, args []string) error {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	var opts Options
+	flags.StringVar(&opts.Package, "pkg", "", "package name of the generated files")
+	flags.StringVar(&opts.ConfigImport, "config-import", "", "import path providing the runtime language config")
+	flags.StringVar(&opts.ConfigExpr, "config-expr", "", "Go expression giving the runtime *lexer.LanguageConfig")
+	flags.StringVar(&opts.CorpusDir, "corpus", DefaultCorpusDir, "corpus directory used by the differential test")
+	output := flags.String("o", "scanner_gen.go", "scanner output file")
+	testOutput := flags.String("test", "", "differential test output file, not generated if empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	src, err := Generate(config, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		return fmt.Errorf("gen: %w", err)
+	}
+
+	if *testOutput == "" {
+		return nil
+	}
+	src, err = GenerateTest(config, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*testOutput, src, 0o644); err != nil {
+		return fmt.Errorf("gen: %w", err)
+	}
+	return nil
+}
+
+func newTemplateData(config *lexer.LanguageConfig, opts Options) (*templateData, error) {
+	if !token.IsIdentifier(opts.Package) {
+		return nil, fmt.Errorf("gen: invalid package name %q", opts.Package)
+	}
+	if opts.CorpusDir == "" {
+		opts.CorpusDir = DefaultCorpusDir
+	}
+	if config.KeywordCase != lexer.CaseSensitive || config.FoldIdentifiers {
+		return nil, fmt.Errorf("gen: case-insensitive keywords and identifier folding are not supported")
+	}
+	if config.Identifiers != nil {
+		return nil, fmt.Errorf("gen: the Identifiers predicate is not supported")
+	}
+	if config.IdentifierProfile != lexer.DefaultIdentifiers || config.NormalizeIdentifiers {
+		return nil, fmt.Errorf("gen: identifier profiles and normalization are not supported")
+	}
+	if config.Whitespace != "" {
+		return nil, fmt.Errorf("gen: custom white space is not supported")
+	}
+	if len(config.QuotedIdentifiers) > 0 || config.DoubledQuoteEscapes || config.DollarQuotedStrings || config.Parameters != "" {
+		return nil, fmt.Errorf("gen: quoted identifiers, doubled quote escapes, dollar quotes and parameters are not supported")
+	}
+	if config.LineNumberLabels {
+		return nil, fmt.Errorf("gen: line number labels are not supported")
+	}
+	if config.KeepComments {
+		return nil, fmt.Errorf("gen: kept comments are not supported")
+	}
+	if config.Assembler != nil {
+		return nil, fmt.Errorf("gen: assembler profiles are not supported")
+	}
+
+	data := &templateData{
+		Options:                 opts,
+		StandardTokens:          standardTokens,
+		Categories:              categories,
+		ExtendedIdentifierRunes: config.ExtendedIdentifierRunes,
+		IdentifierTermination:   config.IdentifierTermination,
+		StringDelimiters:        config.StringDelimiters,
+		IdentifierFallback:      config.IdentifierFallback,
+	}
+	if data.StringDelimiters == "" {
+		data.StringDelimiters = lexer.DefaultStringDelimiters
+	}
+
+	for keyword, id := range config.Keywords {
+		data.Keywords = append(data.Keywords, tableEntry{Key: keyword, ID: id})
+	}
+	for operator, id := range config.Operators {
+		data.Operators = append(data.Operators, tableEntry{Key: operator, ID: id})
+	}
+	for r, id := range config.Symbols {
+		data.Symbols = append(data.Symbols, tableEntry{Key: strconv.QuoteRune(r), ID: id})
+	}
+	for open, close := range config.Comments {
+		data.Comments = append(data.Comments, commentEntry{Open: open, Close: close})
+	}
+	for prefix, tokenizer := range config.PrefixTokenizers {
+		name, _ := lexer.TokenizerName(tokenizer)
+		state, found := tokenizerStates[name]
+		if !found {
+			return nil, fmt.Errorf("gen: prefix tokenizer for %q is not a built-in tokenizer", prefix)
+		}
+		data.Prefixes = append(data.Prefixes, prefixEntry{Key: prefix, State: state})
+	}
+
+	sortEntries(data.Keywords)
+	sortEntries(data.Operators)
+	sortEntries(data.Symbols)
+	sort.Slice(data.Comments, func(i, j int) bool { return data.Comments[i].Open < data.Comments[j].Open })
+	sort.Slice(data.Prefixes, func(i, j int) bool { return data.Prefixes[i].Key < data.Prefixes[j].Key })
+	return data, nil
+}
+
+func sortEntries(entries []tableEntry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+}
+
+func execute(name, text string, data *templateData) ([]byte, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("gen: parsing %s template: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("gen: executing %s template: %w", name, err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("gen: formatting %s: %w", name, err)
+	}
+	return src, nil
+}
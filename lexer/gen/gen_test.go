@@ -0,0 +1,119 @@
+package gen_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/jrsteele09/go-lexer/lexer/gen"
+	"github.com/jrsteele09/go-lexer/lexer/gen/internal/exampleconfig"
+	"github.com/stretchr/testify/require"
+)
+
+var exampleOptions = gen.Options{
+	Package:      "example",
+	ConfigImport: "github.com/jrsteele09/go-lexer/lexer/gen/internal/exampleconfig",
+	ConfigExpr:   "exampleconfig.Config()",
+}
+
+// TestExampleUpToDate checks that the committed example matches the generator's output;
+// run go generate ./lexer/gen/... to update it.
+func TestExampleUpToDate(t *testing.T) {
+	src, err := gen.Generate(exampleconfig.Config(), exampleOptions)
+	require.NoError(t, err)
+	committed, err := os.ReadFile(filepath.Join("internal", "example", "scanner_gen.go"))
+	require.NoError(t, err)
+	require.Equal(t, string(committed), string(src))
+
+	src, err = gen.GenerateTest(exampleconfig.Config(), exampleOptions)
+	require.NoError(t, err)
+	committed, err = os.ReadFile(filepath.Join("internal", "example", "scanner_gen_diff_test.go"))
+	require.NoError(t, err)
+	require.Equal(t, string(committed), string(src))
+}
+
+func TestCustomPrefixTokenizerIsRejected(t *testing.T) {
+	config := exampleconfig.Config()
+	config.PrefixTokenizers["#"] = func(tf *lexer.TokenCreator, initialString string) lexer.TokenizerHandler {
+		return lexer.HexTokenizer(tf, initialString)
+	}
+	_, err := gen.Generate(config, exampleOptions)
+	require.ErrorContains(t, err, "not a built-in tokenizer")
+}
+
+func TestCaseInsensitiveKeywordsAreRejected(t *testing.T) {
+	config := exampleconfig.Config()
+	config.KeywordCase = lexer.CaseInsensitive
+	_, err := gen.Generate(config, exampleOptions)
+	require.ErrorContains(t, err, "not supported")
+}
+
+func TestIdentifierFallback(t *testing.T) {
+	config := exampleconfig.Config()
+	config.IdentifierFallback = true
+	src, err := gen.Generate(config, exampleOptions)
+	require.NoError(t, err)
+	require.Contains(t, string(src), "identifierFallback      = true")
+
+	config.Identifiers = func(r rune, pos int) bool { return r == '$' }
+	_, err = gen.Generate(config, exampleOptions)
+	require.ErrorContains(t, err, "not supported")
+}
+
+func TestIdentifierProfilesAreRejected(t *testing.T) {
+	config := exampleconfig.Config()
+	config.IdentifierProfile = lexer.UAX31Identifiers
+	_, err := gen.Generate(config, exampleOptions)
+	require.ErrorContains(t, err, "not supported")
+
+	config = exampleconfig.Config()
+	config.NormalizeIdentifiers = true
+	_, err = gen.Generate(config, exampleOptions)
+	require.ErrorContains(t, err, "not supported")
+}
+
+func TestCustomWhitespaceIsRejected(t *testing.T) {
+	config := exampleconfig.Config()
+	config.Whitespace = lexer.JSONWhitespace
+	_, err := gen.Generate(config, exampleOptions)
+	require.ErrorContains(t, err, "gen: custom white space is not supported")
+}
+
+func TestSQLQuotingIsRejected(t *testing.T) {
+	config := exampleconfig.Config()
+	config.Parameters = "?"
+	_, err := gen.Generate(config, exampleOptions)
+	require.ErrorContains(t, err, "gen: quoted identifiers, doubled quote escapes, dollar quotes and parameters are not supported")
+}
+
+func TestLineNumberLabelsAreRejected(t *testing.T) {
+	config := exampleconfig.Config()
+	config.LineNumberLabels = true
+	_, err := gen.Generate(config, exampleOptions)
+	require.ErrorContains(t, err, "gen: line number labels are not supported")
+}
+
+func TestKeptCommentsAreRejected(t *testing.T) {
+	config := exampleconfig.Config()
+	config.KeepComments = true
+	_, err := gen.Generate(config, exampleOptions)
+	require.ErrorContains(t, err, "gen: kept comments are not supported")
+}
+
+func TestAssemblerProfilesAreRejected(t *testing.T) {
+	config := exampleconfig.Config()
+	config.Assembler = &lexer.AssemblerProfile{Mnemonics: map[string]lexer.TokenIdentifier{"lda": 100}}
+	_, err := gen.Generate(config, exampleOptions)
+	require.ErrorContains(t, err, "gen: assembler profiles are not supported")
+}
+
+func TestInvalidPackageName(t *testing.T) {
+	_, err := gen.Generate(exampleconfig.Config(), gen.Options{Package: "not a package"})
+	require.Error(t, err)
+}
+
+func TestDifferentialTestRequiresConfig(t *testing.T) {
+	_, err := gen.GenerateTest(exampleconfig.Config(), gen.Options{Package: "example"})
+	require.Error(t, err)
+}
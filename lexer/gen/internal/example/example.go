@@ -0,0 +1,5 @@
+// Package example contains a scanner generated from the exampleconfig language, and the
+// differential test that checks it against the runtime lexer.
+package example
+
+//go:generate go run generate.go -pkg example -config-import github.com/jrsteele09/go-lexer/lexer/gen/internal/exampleconfig -config-expr exampleconfig.Config() -test scanner_gen_diff_test.go
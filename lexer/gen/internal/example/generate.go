@@ -0,0 +1,12 @@
+//go:build ignore
+
+package main
+
+import (
+	"github.com/jrsteele09/go-lexer/lexer/gen"
+	"github.com/jrsteele09/go-lexer/lexer/gen/internal/exampleconfig"
+)
+
+func main() {
+	gen.Main(exampleconfig.Config())
+}
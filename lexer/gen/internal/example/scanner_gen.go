@@ -0,0 +1,807 @@
+// Code generated by go-lexer gen. DO NOT EDIT.
+
+package example
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Standard token identifiers, matching those of the lexer package.
+const (
+	NullType       int16 = 0
+	EOFType        int16 = 1
+	EndOfLineType  int16 = 2
+	IntegerLiteral int16 = 3
+	NumberLiteral  int16 = 4
+	HexLiteral     int16 = 5
+	StringLiteral  int16 = 6
+	IdentifierType int16 = 32512
+	LineNumberType int16 = 32513
+	ParameterType  int16 = 32514
+	CommentType    int16 = 32515
+)
+
+// Token categories, matching those of the lexer package.
+const (
+	NoCategory         uint8 = 0
+	KeywordCategory    uint8 = 1
+	OperatorCategory   uint8 = 2
+	SymbolCategory     uint8 = 3
+	LiteralCategory    uint8 = 4
+	IdentifierCategory uint8 = 5
+	CommentCategory    uint8 = 6
+	TriviaCategory     uint8 = 7
+	CustomCategory     uint8 = 8
+	MnemonicCategory   uint8 = 9
+	DirectiveCategory  uint8 = 10
+	RegisterCategory   uint8 = 11
+	LabelCategory      uint8 = 12
+)
+
+// Token represents a single lexical token, mirroring lexer.Token.
+type Token struct {
+	ID           int16
+	Category     uint8
+	Literal      string
+	Value        any
+	Filename     string
+	SourceLine   uint
+	SourceColumn uint
+}
+
+// TokenCreators are called in order for identifiers that aren't keywords, in place of the
+// LanguageConfig.TokenCreators the scanner was generated from. A creator declines an
+// identifier by returning a token with the ID NullType.
+var TokenCreators []func(identifier string) Token
+
+const (
+	extendedIdentifierRunes = "_"
+	identifierTermination   = ":$"
+	stringDelimiters        = "\"'`"
+	identifierFallback      = false
+)
+
+// Tokenizer states
+const (
+	selectState = iota
+	numberState
+	binaryState
+	hexState
+	stringState
+	identifierState
+	symbolState
+)
+
+func keywordID(s string) (int16, bool) {
+	switch s {
+	case "for":
+		return 9, true
+	case "if":
+		return 7, true
+	case "let":
+		return 8, true
+	case "next":
+		return 11, true
+	case "print":
+		return 12, true
+	case "to":
+		return 10, true
+	}
+	return NullType, false
+}
+
+func operatorID(s string) (int16, bool) {
+	switch s {
+	case "<=":
+		return 26, true
+	case "<>":
+		return 28, true
+	case "==":
+		return 29, true
+	case ">=":
+		return 27, true
+	}
+	return NullType, false
+}
+
+func symbolID(r rune) (int16, bool) {
+	switch r {
+	case '$':
+		return 24, true
+	case '%':
+		return 25, true
+	case '(':
+		return 20, true
+	case ')':
+		return 21, true
+	case '*':
+		return 15, true
+	case '+':
+		return 13, true
+	case ',':
+		return 22, true
+	case '-':
+		return 14, true
+	case '/':
+		return 16, true
+	case ':':
+		return 23, true
+	case '<':
+		return 18, true
+	case '=':
+		return 17, true
+	case '>':
+		return 19, true
+	}
+	return NullType, false
+}
+
+func commentClose(s string) (string, bool) {
+	switch s {
+	case "/*":
+		return "*/", true
+	case "//":
+		return "\n", true
+	case ";":
+		return "\n", true
+	case "rem":
+		return "\n", true
+	}
+	return "", false
+}
+
+func prefixState(s string) int {
+	switch s {
+	case "$":
+		return hexState
+	case "%0":
+		return binaryState
+	case "%1":
+		return binaryState
+	case "0x":
+		return hexState
+	}
+	return selectState
+}
+
+// Scanner performs lexical analysis on a stream of input. Like lexer.Lexer it is stateful:
+// a block comment left open by one call continues into the next.
+type Scanner struct {
+	commentStart string
+	commentEnd   string
+	parsedEnd    string
+
+	state       int
+	overflow    rune
+	hasOverflow bool
+	out         []Token
+
+	number  []byte
+	binary  []byte
+	hex     []byte
+	str     []byte
+	quote   string
+	escaped bool
+	ident   []byte
+	symbols []byte
+}
+
+// NewScanner creates a new Scanner.
+func NewScanner() *Scanner {
+	return &Scanner{}
+}
+
+// Tokenize reads from an io.Reader line by line, tokenizes each line using TokenizeLine,
+// and returns all the generated tokens. The input is UTF-8, or UTF-16 with a byte order
+// mark, and its lines may be of any length and end with "\n", "\r\n" or "\r".
+func (s *Scanner) Tokenize(r io.Reader, filename string) ([]Token, error) {
+	var allTokens []Token
+	br := bufio.NewReader(decodeInput(r))
+	lineNo := uint(1)
+
+	for {
+		line, ending, err := readLine(br)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("[%s line: %d] %w", filename, lineNo, err)
+		}
+
+		allTokens, err = s.appendLineTokens(allTokens, line, ending, filename, lineNo)
+		if err != nil {
+			return nil, fmt.Errorf("[%s line: %d] %w", filename, lineNo, err)
+		}
+		lineNo++
+	}
+
+	return append(allTokens, Token{ID: EOFType, Category: TriviaCategory}), nil
+}
+
+// TokenizeLine tokenizes a single line of input and returns an array of tokens.
+func (s *Scanner) TokenizeLine(line string, filename string, lineNo uint) ([]Token, error) {
+	return s.appendLineTokens(nil, line, "\n", filename, lineNo)
+}
+
+// decodeInput returns a reader of the text of r as UTF-8, without any byte order mark.
+// UTF-16 input is recognised by its byte order mark.
+func decodeInput(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	bom, _ := br.Peek(3)
+	switch {
+	case bytes.HasPrefix(bom, []byte{0xEF, 0xBB, 0xBF}):
+		_, _ = br.Discard(3)
+	case bytes.HasPrefix(bom, []byte{0xFF, 0xFE}):
+		_, _ = br.Discard(2)
+		return &utf16Reader{r: br}
+	case bytes.HasPrefix(bom, []byte{0xFE, 0xFF}):
+		_, _ = br.Discard(2)
+		return &utf16Reader{r: br, bigEndian: true}
+	}
+	return br
+}
+
+// utf16Reader decodes UTF-16 to UTF-8, failing at an unpaired surrogate.
+type utf16Reader struct {
+	r         *bufio.Reader
+	bigEndian bool
+	column    int    // Byte offset in its line of the next rune decoded, for errors
+	decoded   []byte // Text decoded but not yet read
+	err       error  // Error to return once the decoded text has been read
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	if len(u.decoded) == 0 && u.err == nil {
+		u.decode(len(p))
+	}
+	if len(u.decoded) == 0 {
+		return 0, u.err
+	}
+	n := copy(p, u.decoded)
+	u.decoded = u.decoded[n:]
+	return n, nil
+}
+
+func (u *utf16Reader) decode(n int) {
+	u.decoded = u.decoded[:0]
+	for len(u.decoded) < n {
+		r, err := u.readRune()
+		if err != nil {
+			u.err = err
+			return
+		}
+		u.decoded = utf8.AppendRune(u.decoded, r)
+		if r == '\n' || r == '\r' {
+			u.column = 0
+		} else {
+			u.column += utf8.RuneLen(r)
+		}
+	}
+}
+
+func (u *utf16Reader) readRune() (rune, error) {
+	var unit [2]byte
+	if _, err := io.ReadFull(u.r, unit[:]); err == io.ErrUnexpectedEOF {
+		return 0, fmt.Errorf("invalid UTF-16 at column %d: odd number of bytes", u.column)
+	} else if err != nil {
+		return 0, err
+	}
+	r := u.rune(unit[:])
+	if !utf16.IsSurrogate(r) {
+		return r, nil
+	}
+	if next, err := u.r.Peek(2); err == nil && r < 0xDC00 {
+		if pair := utf16.DecodeRune(r, u.rune(next)); pair != utf8.RuneError {
+			_, _ = u.r.Discard(2)
+			return pair, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid UTF-16 at column %d: unpaired surrogate %U", u.column, r)
+}
+
+func (u *utf16Reader) rune(unit []byte) rune {
+	if u.bigEndian {
+		return rune(unit[0])<<8 | rune(unit[1])
+	}
+	return rune(unit[1])<<8 | rune(unit[0])
+}
+
+// readLine returns the next line of any length and its "\n", "\r\n" or "\r" line ending,
+// or io.EOF at the end of the input.
+func readLine(r *bufio.Reader) (string, string, error) {
+	var line []byte
+	for {
+		if r.Buffered() == 0 {
+			if _, err := r.Peek(1); err != nil {
+				if err == io.EOF && len(line) > 0 {
+					return string(line), "\n", nil
+				}
+				return "", "", err
+			}
+		}
+		buffered, _ := r.Peek(r.Buffered())
+		i := bytes.IndexAny(buffered, "\r\n")
+		if i < 0 {
+			line = append(line, buffered...)
+			_, _ = r.Discard(len(buffered))
+			continue
+		}
+
+		line = append(line, buffered[:i]...)
+		_, _ = r.Discard(i + 1)
+		if buffered[i] == '\n' {
+			return string(line), "\n", nil
+		}
+		if next, err := r.Peek(1); err == nil && next[0] == '\n' {
+			_, _ = r.Discard(1)
+			return string(line), "\r\n", nil
+		}
+		return string(line), "\r", nil
+	}
+}
+
+func (s *Scanner) appendLineTokens(lineTokens []Token, line string, ending string, filename string, lineNo uint) ([]Token, error) {
+	if !utf8.ValidString(line) {
+		for i, r := range line {
+			if _, size := utf8.DecodeRuneInString(line[i:]); r == utf8.RuneError && size == 1 {
+				return nil, fmt.Errorf("invalid UTF-8 at column %d", i)
+			}
+		}
+	}
+	lineStart := len(lineTokens)
+
+	addNewTokens := func(column int, tokens []Token) {
+		for _, token := range tokens {
+			token.SourceLine = lineNo
+			token.Filename = filename
+			token.SourceColumn = uint(column)
+			lineTokens = append(lineTokens, token)
+		}
+	}
+
+	addEndOfLine := func() {
+		if len(lineTokens) != lineStart {
+			addNewTokens(len(line), []Token{{ID: EndOfLineType, Category: TriviaCategory, Literal: ending}})
+		}
+	}
+
+	s.hasOverflow = false
+	s.state = selectState
+
+	for i, r := range line {
+		end := i + utf8.RuneLen(r)
+		if s.inComment() {
+			if s.commentEnd == "\n" {
+				break
+			}
+			s.parseEndOfComment(r)
+			continue
+		}
+
+		for {
+			tokens, err := s.tokenize(r)
+			if err != nil {
+				return nil, fmt.Errorf("Scanner.TokenizeLine: %w", err)
+			}
+			addNewTokens(i, tokens)
+			if s.inComment() || !s.hasOverflow {
+				break
+			}
+			s.hasOverflow = false
+			r = s.overflow
+		}
+		if s.inComment() {
+			s.startComment(line, end)
+		}
+	}
+
+	if !s.inComment() {
+		tokens, err := s.tokenize('\n')
+		if err != nil {
+			return nil, fmt.Errorf("Scanner.TokenizeLine: %w", err)
+		}
+		addNewTokens(len(line), tokens)
+		if s.inComment() { // An opener ending the line, e.g. "rem"
+			s.startComment(line, len(line))
+		}
+	}
+
+	// A line comment ends with the line, even if its opener ends the line, e.g. "rem"
+	if s.inComment() && s.commentEnd == "\n" {
+		s.commentStart, s.commentEnd, s.parsedEnd = "", "", ""
+	}
+	addEndOfLine()
+	return lineTokens, nil
+}
+
+// startComment passes the runes of line before end that follow the opener of the comment
+// just started, which the tokenizers took in, on to parseEndOfComment, as lexer.Lexer does.
+func (s *Scanner) startComment(line string, end int) {
+	from := end
+	for e := end; e >= len(s.commentStart); e-- {
+		if strings.EqualFold(line[e-len(s.commentStart):e], s.commentStart) {
+			from = e
+			break
+		}
+	}
+	for _, r := range line[from:end] {
+		if s.parseEndOfComment(r) {
+			return
+		}
+	}
+}
+
+func (s *Scanner) inComment() bool {
+	return s.commentStart != "" && s.commentEnd != ""
+}
+
+func (s *Scanner) isStartOfComment(str string) bool {
+	if s.inComment() {
+		return false
+	}
+	commentEnd, found := commentClose(str)
+	if found {
+		s.commentStart = str
+		s.commentEnd = commentEnd
+		s.parsedEnd = ""
+	}
+	return found
+}
+
+func (s *Scanner) parseEndOfComment(r rune) bool {
+	if s.commentEnd == "" {
+		return true
+	}
+
+	// Keep the longest end of the parsed runes that may start the closing delimiter
+	s.parsedEnd += string(r)
+	for !strings.HasPrefix(s.commentEnd, s.parsedEnd) {
+		_, size := utf8.DecodeRuneInString(s.parsedEnd)
+		s.parsedEnd = s.parsedEnd[size:]
+	}
+	if s.parsedEnd != s.commentEnd {
+		return false
+	}
+
+	s.commentStart, s.commentEnd, s.parsedEnd = "", "", ""
+	return true
+}
+
+func (s *Scanner) setOverflow(r rune) {
+	s.overflow = r
+	s.hasOverflow = true
+}
+
+func (s *Scanner) emit(t Token) []Token {
+	s.out = append(s.out[:0], t)
+	return s.out
+}
+
+func (s *Scanner) start(state int, initial string) {
+	switch state {
+	case numberState:
+		s.number = append(s.number[:0], initial...)
+	case binaryState:
+		s.binary = s.binary[:0]
+		if initial == "0" || initial == "1" {
+			s.binary = append(s.binary, initial...)
+		}
+	case hexState:
+		s.hex = s.hex[:0]
+		if initial == "" || initial == "$" {
+			s.hex = append(s.hex, "0x"...)
+		}
+	case stringState:
+		s.str = s.str[:0]
+		s.quote = initial
+		s.escaped = false
+	case identifierState:
+		s.ident = append(s.ident[:0], initial...)
+	case symbolState:
+		s.symbols = append(s.symbols[:0], initial...)
+	}
+	s.state = state
+}
+
+func (s *Scanner) tokenize(r rune) ([]Token, error) {
+	var tokens []Token
+	var completed bool
+	var err error
+
+	switch s.state {
+	case numberState:
+		tokens, completed, err = s.tokenizeNumber(r)
+	case binaryState:
+		tokens, completed, err = s.tokenizeBinary(r)
+	case hexState:
+		tokens, completed, err = s.tokenizeHex(r)
+	case stringState:
+		tokens, completed, err = s.tokenizeString(r)
+	case identifierState:
+		tokens, completed, err = s.tokenizeIdentifier(r)
+	case symbolState:
+		tokens, completed, err = s.tokenizeSymbol(r)
+	default:
+		tokens, completed, err = s.selectTokenizer(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if completed {
+		s.state = selectState
+	}
+	return tokens, nil
+}
+
+func (s *Scanner) selectTokenizer(r rune) ([]Token, bool, error) {
+	if s.inComment() || unicode.IsSpace(r) {
+		return nil, false, nil
+	}
+
+	if state := prefixState(string(r)); state != selectState {
+		s.start(state, string(r))
+	} else if _, found := symbolID(r); found {
+		s.start(symbolState, string(r))
+	} else if unicode.IsDigit(r) {
+		s.start(numberState, string(r))
+	} else if strings.ContainsRune(stringDelimiters, r) {
+		s.start(stringState, string(r))
+	} else if isIdentifierChar(r, 0) {
+		s.start(identifierState, string(r))
+	} else if !s.isStartOfComment(string(r)) {
+		return nil, false, errors.New("unknown character: \"" + string(r) + "\"")
+	}
+	return nil, false, nil
+}
+
+func (s *Scanner) tokenizeNumber(r rune) ([]Token, bool, error) {
+	if isDigit(r, len(s.number)) {
+		s.number = utf8.AppendRune(s.number, r)
+		return nil, false, nil
+	}
+	if state := prefixState(string(s.number) + string(r)); state != selectState {
+		s.number = utf8.AppendRune(s.number, r)
+		s.start(state, string(s.number))
+		return nil, false, nil
+	}
+
+	s.setOverflow(r)
+	literal := string(s.number)
+	if strings.Contains(literal, ".") {
+		number, err := strconv.ParseFloat(literal, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("numberTokenizer stringToNumber: %w", err)
+		}
+		return s.emit(Token{ID: NumberLiteral, Category: LiteralCategory, Literal: literal, Value: number}), true, nil
+	}
+	number, err := strconv.ParseInt(literal, 10, 64)
+	if err != nil {
+		return nil, false, fmt.Errorf("numberTokenizer stringToNumber: %w", err)
+	}
+	return s.emit(Token{ID: IntegerLiteral, Category: LiteralCategory, Literal: literal, Value: number}), true, nil
+}
+
+func (s *Scanner) tokenizeBinary(r rune) ([]Token, bool, error) {
+	if r == '0' || r == '1' {
+		s.binary = append(s.binary, byte(r))
+		return nil, false, nil
+	}
+
+	current := string(s.binary)
+	s.setOverflow(r)
+
+	result, err := strconv.ParseUint(current, 2, 64)
+	if err != nil {
+		return nil, true, fmt.Errorf("BinaryTokenizer BinaryStringToNumber [%w]", err)
+	}
+	var number any
+	switch {
+	case result <= math.MaxUint8:
+		number = uint8(result)
+	case result <= math.MaxUint16:
+		number = uint16(result)
+	case result <= math.MaxUint32:
+		number = uint32(result)
+	default:
+		number = result
+	}
+	return s.emit(Token{ID: IntegerLiteral, Category: LiteralCategory, Literal: current, Value: number}), true, nil
+}
+
+func (s *Scanner) tokenizeHex(r rune) ([]Token, bool, error) {
+	if unicode.IsDigit(r) || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F') {
+		s.hex = utf8.AppendRune(s.hex, r)
+		return nil, false, nil
+	}
+
+	s.setOverflow(r)
+
+	parsedString := string(s.hex)
+	hexString := parsedString
+	if len(hexString) > 2 && hexString[:2] == "0x" {
+		hexString = hexString[2:]
+	}
+	value, err := strconv.ParseInt(hexString, 16, 64)
+	if err != nil {
+		return nil, false, fmt.Errorf("HexTokenizer HexToNumber: %w", err)
+	}
+	var number any
+	switch l := len(hexString); {
+	case l > 0 && l <= 2:
+		number = uint8(value)
+	case l > 2 && l <= 4:
+		number = uint16(value)
+	case l > 4 && l <= 8:
+		number = uint32(value)
+	default:
+		number = uint64(value)
+	}
+	return s.emit(Token{ID: HexLiteral, Category: LiteralCategory, Literal: parsedString, Value: number}), true, nil
+}
+
+func (s *Scanner) tokenizeString(r rune) ([]Token, bool, error) {
+	if s.escaped {
+		s.escaped = false
+		switch r {
+		case 'n':
+			s.str = append(s.str, '\n')
+		case 'r':
+			s.str = append(s.str, '\r')
+		case 't':
+			s.str = append(s.str, '\t')
+		case '0':
+			s.str = append(s.str, '\000')
+		default:
+			s.str = utf8.AppendRune(s.str, r)
+		}
+		return nil, false, nil
+	}
+
+	if r == '\\' {
+		s.escaped = true
+		return nil, false, nil
+	}
+
+	if string(r) == s.quote {
+		return s.emit(Token{ID: StringLiteral, Category: LiteralCategory, Value: string(s.str)}), true, nil
+	}
+
+	s.str = utf8.AppendRune(s.str, r)
+	return nil, false, nil
+}
+
+func (s *Scanner) tokenizeIdentifier(r rune) ([]Token, bool, error) {
+	if !isIdentifierChar(r, len(s.ident)) {
+		s.setOverflow(r)
+
+		identifier := string(s.ident)
+		if s.isStartOfComment(identifier) {
+			return nil, false, nil
+		}
+		t := tokenFromIdentifier(identifier)
+		if t.ID == NullType {
+			return nil, true, fmt.Errorf("unknown identifier %s", identifier)
+		}
+		return s.emit(t), true, nil
+	}
+
+	s.ident = utf8.AppendRune(s.ident, r)
+
+	if strings.ContainsRune(identifierTermination, r) {
+		identifier := string(s.ident)
+		t := tokenFromIdentifier(identifier)
+		if t.ID == NullType {
+			return nil, true, fmt.Errorf("unknown identifier %s", identifier)
+		}
+		return s.emit(t), true, nil
+	}
+	return nil, false, nil
+}
+
+func (s *Scanner) tokenizeSymbol(r rune) ([]Token, bool, error) {
+	if prefixState(string(r)) != selectState {
+		return s.createSymbolTokens(r)
+	} else if s.isStartOfComment(string(s.symbols)) {
+		return nil, true, nil
+	} else if _, found := symbolID(r); found {
+		s.symbols = utf8.AppendRune(s.symbols, r)
+		return nil, false, nil
+	}
+	return s.createSymbolTokens(r)
+}
+
+func (s *Scanner) createSymbolTokens(overflowRune rune) ([]Token, bool, error) {
+	completed := true
+	symbols := s.symbols
+	if len(symbols) > 0 {
+		_, size := utf8.DecodeLastRune(symbols)
+		lastRune := string(symbols[len(symbols)-size:])
+		if state := prefixState(lastRune + string(overflowRune)); state != selectState {
+			symbols = append([]byte(nil), symbols[:len(symbols)-size]...)
+			s.start(state, lastRune)
+			completed = false
+		} else if state := prefixState(string(symbols)); state != selectState {
+			s.start(state, string(symbols))
+			s.setOverflow(overflowRune)
+			return nil, false, nil
+		}
+	}
+
+	s.setOverflow(overflowRune)
+	tokens := s.out[:0]
+
+	i := 0
+	for i < len(symbols) {
+		length := 0
+		for x := i + 1; x < len(symbols); x++ {
+			if _, found := operatorID(string(symbols[i : x+1])); found {
+				length = x + 1 - i
+			} else if s.isStartOfComment(string(symbols[i : x+1])) {
+				return nil, false, nil
+			}
+		}
+		if length > 0 {
+			longestSymbol := string(symbols[i : i+length])
+			id, _ := operatorID(longestSymbol)
+			tokens = append(tokens, Token{ID: id, Category: OperatorCategory, Literal: longestSymbol, Value: longestSymbol})
+			i += length
+			continue
+		}
+		if s.isStartOfComment(string(symbols)) {
+			return nil, false, nil
+		}
+		id, found := symbolID(rune(symbols[i]))
+		if !found {
+			return nil, false, fmt.Errorf("unknown symbol %s", string(symbols[i]))
+		}
+		tokens = append(tokens, Token{ID: id, Category: SymbolCategory, Literal: string(symbols[i]), Value: symbols[i]})
+		i++
+	}
+
+	s.out = tokens
+	return tokens, completed, nil
+}
+
+func tokenFromIdentifier(identifier string) Token {
+	if id, found := keywordID(identifier); found {
+		return Token{ID: id, Category: KeywordCategory, Literal: identifier}
+	}
+	for _, c := range TokenCreators {
+		if t := c(identifier); t.ID != NullType {
+			if t.Category == NoCategory {
+				t.Category = IdentifierCategory
+			}
+			return t
+		}
+	}
+	if identifierFallback {
+		return Token{ID: IdentifierType, Category: IdentifierCategory, Literal: identifier}
+	}
+	return Token{}
+}
+
+func isDigit(r rune, pos int) bool {
+	return unicode.IsDigit(r) || (pos > 0 && r == '.')
+}
+
+func isIdentifierChar(r rune, pos int) bool {
+	if unicode.IsLetter(r) {
+		return true
+	}
+	if pos > 0 && isDigit(r, pos) {
+		return true
+	}
+	if pos > 0 && strings.ContainsRune(identifierTermination, r) {
+		return true
+	}
+	return strings.ContainsRune(extendedIdentifierRunes, r)
+}
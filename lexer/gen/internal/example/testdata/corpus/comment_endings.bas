@@ -0,0 +1,11 @@
+let a = 1 //
+let b = 2
+print a rem
+5
+let c = 3 ;
+let d = 4 /**/ let e = 5
+let f = 6 /***/ let g = 7
+let h = 8 /*
+**/ let i = 9
+print h rem
+print i //
\ No newline at end of file
@@ -0,0 +1,6 @@
+/* a comment
+spanning
+several lines */ let x = 1
+let y = 2 /* inline */ let z = 3
+;let a = 10
+print x // done
@@ -0,0 +1,4 @@
+﻿let a = 1
+let b = "x"let c = a + b
+/* crlf
+comment */ let d = $ff
\ No newline at end of file
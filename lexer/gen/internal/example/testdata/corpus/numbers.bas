@@ -0,0 +1,3 @@
+let a = 0, 1, 123456789, 3.14159, 0.5
+let h = $0, $ff, $1234, $12345678, 0xbeef, 0x123456789a
+let b = %0, %1, %11111111, %100000000
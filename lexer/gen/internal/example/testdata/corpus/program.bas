@@ -0,0 +1,7 @@
+start: let a = 10 + $ff * (b <= 20) // trailing comment
+for i = 1 to 10: print "hello", %0101, a$, 0x1234 <> 100.25
+loop: if a == b /* block
+comment */ next i ; asm comment
+rem a whole line comment
+print "say \"hi\"\n\ttab", 'single', `back`
+let c=a>=b<>c-d/e
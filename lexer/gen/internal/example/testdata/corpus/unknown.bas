@@ -0,0 +1,2 @@
+let a = 10
+let b = a ~ 2
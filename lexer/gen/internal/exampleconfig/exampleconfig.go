@@ -0,0 +1,98 @@
+// Package exampleconfig provides the language the example scanner is generated from.
+package exampleconfig
+
+import (
+	"strings"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+)
+
+// Token identifiers of the example language
+const (
+	IfToken lexer.TokenIdentifier = iota + lexer.LastStdLiteral
+	LetToken
+	ForToken
+	ToToken
+	NextToken
+	PrintToken
+	AddToken
+	SubtractToken
+	MultiplyToken
+	DivideToken
+	EqualsToken
+	LessThanToken
+	GreaterThanToken
+	LeftParenthesisToken
+	RightParenthesisToken
+	CommaToken
+	ColonToken
+	DollarToken
+	PercentToken
+	LessThanOrEqualToken
+	GreaterThanOrEqualToken
+	NotEqualToken
+	EqualityToken
+	LabelToken
+	VariableToken
+	StringVariableToken
+)
+
+// Config returns the example language configuration.
+func Config() *lexer.LanguageConfig {
+	return lexer.NewLexerLanguage(lexer.LanguageConfig{
+		Keywords: map[string]lexer.TokenIdentifier{
+			"if":    IfToken,
+			"let":   LetToken,
+			"for":   ForToken,
+			"to":    ToToken,
+			"next":  NextToken,
+			"print": PrintToken,
+		},
+		Operators: map[string]lexer.TokenIdentifier{
+			"<=": LessThanOrEqualToken,
+			">=": GreaterThanOrEqualToken,
+			"<>": NotEqualToken,
+			"==": EqualityToken,
+		},
+		Symbols: map[rune]lexer.TokenIdentifier{
+			'+': AddToken,
+			'-': SubtractToken,
+			'*': MultiplyToken,
+			'/': DivideToken,
+			'=': EqualsToken,
+			'<': LessThanToken,
+			'>': GreaterThanToken,
+			'(': LeftParenthesisToken,
+			')': RightParenthesisToken,
+			',': CommaToken,
+			':': ColonToken,
+			'$': DollarToken,
+			'%': PercentToken,
+		},
+		Comments: map[string]string{
+			"//":  "\n",
+			"/*":  "*/",
+			"rem": "\n",
+			";":   "\n",
+		},
+		PrefixTokenizers: map[string]lexer.TokenizerFunc{
+			"$":  lexer.HexTokenizer,
+			"0x": lexer.HexTokenizer,
+			"%0": lexer.BinaryTokenizer,
+			"%1": lexer.BinaryTokenizer,
+		},
+		ExtendedIdentifierRunes: "_",
+		IdentifierTermination:   ":$",
+		TokenCreators:           []func(string) lexer.Token{variableTokenCreator},
+	})
+}
+
+func variableTokenCreator(identifier string) lexer.Token {
+	switch {
+	case strings.HasSuffix(identifier, ":"):
+		return lexer.NewToken(LabelToken, identifier, nil)
+	case strings.HasSuffix(identifier, "$"):
+		return lexer.NewToken(StringVariableToken, identifier, nil)
+	}
+	return lexer.NewToken(VariableToken, identifier, nil)
+}
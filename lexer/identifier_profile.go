@@ -0,0 +1,105 @@
+package lexer
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/jrsteele09/go-lexer/lexer/utils"
+	"golang.org/x/text/unicode/norm"
+)
+
+// IdentifierProfile selects the rules deciding which runes make up an identifier. The
+// ExtendedIdentifierRunes and IdentifierTermination runes are allowed in addition to the
+// profile's own. Set LanguageConfig.Identifiers for rules of your own.
+type IdentifierProfile uint8
+
+const (
+	// DefaultIdentifiers start identifiers with a letter and continue them with letters,
+	// digits and '.'.
+	DefaultIdentifiers IdentifierProfile = iota
+
+	// UAX31Identifiers start identifiers with an XID_Start rune and continue them with
+	// XID_Continue runes, as defined by Unicode Standard Annex #31. These include combining
+	// marks and connector punctuation such as '_'.
+	UAX31Identifiers
+
+	// ASCIIIdentifiers start identifiers with an ASCII letter and continue them with ASCII
+	// letters and digits.
+	ASCIIIdentifiers
+)
+
+// xidExcluded are the runes of ID_Start and ID_Continue that aren't in XID_Start and
+// XID_Continue, as their NFKC forms aren't identifiers.
+var xidExcluded = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x037a, Hi: 0x037a, Stride: 1},
+		{Lo: 0x309b, Hi: 0x309c, Stride: 1},
+		{Lo: 0xfc5e, Hi: 0xfc63, Stride: 1},
+		{Lo: 0xfdfa, Hi: 0xfdfb, Stride: 1},
+		{Lo: 0xfe70, Hi: 0xfe7e, Stride: 2},
+	},
+}
+
+// xidStartExcluded are the runes of XID_Continue that can't start an identifier although
+// they are letters.
+var xidStartExcluded = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x0e33, Hi: 0x0e33, Stride: 1},
+		{Lo: 0x0eb3, Hi: 0x0eb3, Stride: 1},
+		{Lo: 0xff9e, Hi: 0xff9f, Stride: 1},
+	},
+}
+
+// IsXIDStart reports whether r has the Unicode XID_Start property.
+func IsXIDStart(r rune) bool {
+	if r < 0x80 {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z'
+	}
+	return (unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start)) &&
+		!unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space, xidExcluded, xidStartExcluded)
+}
+
+// IsXIDContinue reports whether r has the Unicode XID_Continue property.
+func IsXIDContinue(r rune) bool {
+	if r < 0x80 {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_'
+	}
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue) &&
+		!unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space, xidExcluded)
+}
+
+// IsIdentifierChar reports whether r is valid at byte position pos of an identifier under
+// the profile, also allowing the extended runes anywhere and the termination runes after
+// the first position, like utils.IsIdentifierChar.
+func (p IdentifierProfile) IsIdentifierChar(r rune, pos int, extended, termination string) bool {
+	switch p {
+	case UAX31Identifiers:
+		if (pos == 0 && IsXIDStart(r)) || (pos > 0 && IsXIDContinue(r)) {
+			return true
+		}
+	case ASCIIIdentifiers:
+		if ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || (pos > 0 && '0' <= r && r <= '9') {
+			return true
+		}
+	default:
+		return utils.IsIdentifierChar(r, pos, extended, termination)
+	}
+	if pos > 0 && strings.ContainsRune(termination, r) {
+		return true
+	}
+	return strings.ContainsRune(extended, r)
+}
+
+// identifierRule reports whether r is valid at byte position pos of an identifier under the
+// language's identifier profile.
+func (ll *LanguageConfig) identifierRule(r rune, pos int) bool {
+	return ll.IdentifierProfile.IsIdentifierChar(r, pos, ll.ExtendedIdentifierRunes, ll.IdentifierTermination)
+}
+
+// normalizeIdentifier returns the NFC form of identifier if the language normalizes them.
+func (ll *LanguageConfig) normalizeIdentifier(identifier string) string {
+	if !ll.NormalizeIdentifiers || norm.NFC.IsNormalString(identifier) {
+		return identifier
+	}
+	return norm.NFC.String(identifier)
+}
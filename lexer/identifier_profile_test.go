@@ -0,0 +1,95 @@
+package lexer_test
+
+import (
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXIDProperties(t *testing.T) {
+	tests := []struct {
+		r     rune
+		start bool
+		part  bool
+	}{
+		{'a', true, true},
+		{'_', false, true},
+		{'7', false, true},
+		{'$', false, false},
+		{'é', true, true},
+		{'π', true, true},
+		{'漢', true, true},
+		{'\u0301', false, true}, // Combining acute accent
+		{'‿', false, true},      // Undertie, connector punctuation
+		{'·', false, true},      // Middle dot, Other_ID_Continue
+		{'℘', true, true},       // Script capital P, Other_ID_Start
+		{'ͺ', false, false},
+		{'ำ', false, true},
+		{'ⸯ', false, false}, // Vertical tilde, Pattern_Syntax
+		{'٣', false, true},
+		{' ', false, false},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.start, lexer.IsXIDStart(test.r), "XID_Start %U", test.r)
+		require.Equal(t, test.part, lexer.IsXIDContinue(test.r), "XID_Continue %U", test.r)
+	}
+}
+
+func TestUAX31Identifiers(t *testing.T) {
+	config := lexer.NewLanguage("unicode").
+		Symbol('=', EqualsSymbolToken).
+		IdentifierProfile(lexer.UAX31Identifiers).
+		IdentifierFallback().
+		MustBuild()
+
+	for _, language := range []*lexer.LanguageConfig{config, config.Compile()} {
+		tokens, err := lexer.NewLexer(language).TokenizeLine("naïve_x = größe2 = ét‿a", "testfile", 0)
+		require.NoError(t, err)
+		literals := make([]string, len(tokens))
+		for i, token := range tokens {
+			literals[i] = token.Literal
+		}
+		require.Equal(t, []string{"naïve_x", "=", "größe2", "=", "ét‿a", "\n"}, literals)
+
+		_, err = lexer.NewLexer(language).TokenizeLine("a.b", "testfile", 0)
+		require.Error(t, err, "'.' isn't an XID_Continue rune")
+	}
+}
+
+func TestASCIIIdentifiers(t *testing.T) {
+	config := lexer.NewLanguage("ascii").
+		IdentifierProfile(lexer.ASCIIIdentifiers).
+		IdentifierRunes("_").
+		IdentifierFallback().
+		MustBuild()
+
+	for _, language := range []*lexer.LanguageConfig{config, config.Compile()} {
+		tokens, err := lexer.NewLexer(language).TokenizeLine("_tmp x1", "testfile", 0)
+		require.NoError(t, err)
+		require.Equal(t, "_tmp", tokens[0].Literal)
+		require.Equal(t, "x1", tokens[1].Literal)
+
+		_, err = lexer.NewLexer(language).TokenizeLine("größe", "testfile", 0)
+		require.Error(t, err)
+	}
+}
+
+func TestNormalizeIdentifiers(t *testing.T) {
+	language := lexer.NewLanguage("nfc").
+		Keyword("café", IfStatementToken).
+		IdentifierProfile(lexer.UAX31Identifiers).
+		IdentifierFallback()
+
+	decomposed := "cafe\u0301"
+	tokens, err := lexer.NewLexer(language.MustBuild()).TokenizeLine(decomposed, "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, lexer.IdentifierType, tokens[0].ID)
+
+	config := language.NormalizeIdentifiers().MustBuild()
+	tokens, err = lexer.NewLexer(config).TokenizeLine(decomposed+" résumé", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, IfStatementToken, tokens[0].ID)
+	require.Equal(t, "café", tokens[0].Literal)
+	require.Equal(t, "résumé", tokens[1].Literal)
+}
@@ -0,0 +1,72 @@
+package lexer_test
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentifierFallback(t *testing.T) {
+	language := lexer.NewLanguage("fallback").Keyword("if", IfStatementToken).Symbol('=', EqualsSymbolToken)
+
+	_, err := lexer.NewLexer(language.MustBuild()).TokenizeLine("if a", "testfile", 0)
+	require.ErrorContains(t, err, "unknown identifier a")
+
+	config := language.IdentifierFallback().MustBuild()
+	tokens, err := lexer.NewLexer(config).TokenizeLine("if total = x1", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{
+		IfStatementToken, lexer.IdentifierType, EqualsSymbolToken, lexer.IdentifierType, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+	require.Equal(t, "total", tokens[1].Literal)
+	require.Equal(t, lexer.IdentifierCategory, tokens[1].Category)
+	require.Equal(t, "IdentifierType", config.TokenName(lexer.IdentifierType))
+}
+
+func TestIdentifierFallbackAfterTokenCreators(t *testing.T) {
+	config := NewBasicLanguage()
+	config.TokenCreators = []func(string) lexer.Token{BasicLangstringVariableTokenCreator}
+	config.IdentifierFallback = true
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine("a$ b", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{StringVariableToken, lexer.IdentifierType, lexer.EndOfLineType}, tokenIDs(tokens))
+}
+
+func TestIdentifiersPredicate(t *testing.T) {
+	// Sigils start identifiers, and BASIC style type markers end them
+	config := lexer.NewLanguage("sigils").
+		Symbol('=', EqualsSymbolToken).
+		IdentifierTermination("$%").
+		Identifiers(func(r rune, pos int) bool {
+			if pos == 0 {
+				return r == '$' || r == '@' || unicode.IsLetter(r)
+			}
+			return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '$' || r == '%'
+		}).
+		IdentifierFallback().
+		MustBuild()
+
+	for _, language := range []*lexer.LanguageConfig{config, config.Compile()} {
+		tokens, err := lexer.NewLexer(language).TokenizeLine("$var = @attr A$ B% c_d", "testfile", 0)
+		require.Error(t, err, "'_' isn't an identifier rune")
+
+		tokens, err = lexer.NewLexer(language).TokenizeLine("$var = @attr A$ B%", "testfile", 0)
+		require.NoError(t, err)
+		literals := make([]string, len(tokens))
+		for i, token := range tokens {
+			literals[i] = token.Literal
+		}
+		require.Equal(t, []string{"$var", "=", "@attr", "A$", "B%", "\n"}, literals)
+	}
+}
+
+func TestValidatePrefixShadowsIdentifiers(t *testing.T) {
+	_, err := lexer.NewLanguage("sigils").
+		Identifiers(func(r rune, pos int) bool { return r == '$' || unicode.IsLetter(r) }).
+		HexPrefix("$").
+		Build()
+	require.ErrorContains(t, err, `prefix tokenizer "$" shadows identifiers starting with it`)
+}
@@ -0,0 +1,243 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jrsteele09/go-lexer/lexer/comments"
+)
+
+// lineState is what the lines before a line leave open: a block comment, or a
+// dollar-quoted string. It's comparable, so that re-lexing can stop once a line starts in
+// the same state as it did before. The Lexer has no other state that spans lines.
+type lineState struct {
+	comment     comments.State
+	dollarQuote string // The "$tag$" of an open dollar-quoted string, "" if none
+}
+
+func (l *Lexer) lineState() lineState {
+	s := lineState{comment: l.commentParser.State()}
+	if l.tokenCreator.spansLines {
+		s.dollarQuote = string(l.tokenCreator.dollarQuotes.tag)
+	}
+	return s
+}
+
+// setLineState restores a state returned by lineState, which mustn't have an open
+// dollar-quoted string, as its text so far isn't kept.
+func (l *Lexer) setLineState(s lineState) {
+	l.commentParser.SetState(s.comment)
+	l.tokenCreator.spansLines = false
+}
+
+// IncrementalLexer keeps the tokens of a text, such as an editor's buffer, so that after
+// an edit only the lines whose tokens may have changed are lexed again: from the first
+// edited line until a line starts in the same state as it did before the edit, e.g. after
+// the end of a block comment that the edit opened.
+//
+// Its tokens are those Tokenize would return for the text, except that a line that fails
+// to lex has no tokens, an unterminated dollar-quoted string isn't an error, and line
+// numbers aren't checked for AscendingLineNumbers.
+type IncrementalLexer struct {
+	lexer    *Lexer
+	filename string
+	lines    []incrementalLine
+}
+
+// incrementalLine is a line of an IncrementalLexer's text.
+type incrementalLine struct {
+	text     string
+	ending   string    // "\n", "\r\n", "\r", or "" for the last line
+	entry    lineState // The state the line starts in
+	tokens   []Token
+	warnings []Warning
+	err      error
+}
+
+// Edit replaces the text of a range with Text. Lines are counted from 1 and columns are
+// byte offsets in their line, counted from 0, as for a Token's SourceLine and SourceColumn.
+type Edit struct {
+	StartLine, StartColumn uint
+	EndLine, EndColumn     uint
+	Text                   string
+}
+
+// TokenChange is how an edit changed the tokens: the tokens from Start up to End before the
+// edit were replaced by Tokens. The tokens after them are unchanged, except that their
+// SourceLine moved by LineDelta.
+type TokenChange struct {
+	Start     int
+	End       int
+	Tokens    []Token
+	LineDelta int
+}
+
+// NewIncrementalLexer lexes text with a Lexer for the language and options, keeping its
+// tokens to be updated by Edit.
+func NewIncrementalLexer(language *LanguageConfig, filename string, text string, opts ...Option) *IncrementalLexer {
+	il := &IncrementalLexer{lexer: NewLexer(language, opts...), filename: filename, lines: splitIncrementalLines(text)}
+	il.relex(0, len(il.lines))
+	return il
+}
+
+// Edit applies an edit to the text and re-lexes the lines it affects, returning how the
+// tokens changed. A range outside the text is an error, leaving the text unchanged.
+func (il *IncrementalLexer) Edit(e Edit) (TokenChange, error) {
+	if err := il.checkEdit(e); err != nil {
+		return TokenChange{}, err
+	}
+	first, last := int(e.StartLine-1), int(e.EndLine-1)
+	text := il.lines[first].text[:e.StartColumn] + e.Text + il.lines[last].text[e.EndColumn:] + il.lines[last].ending
+
+	// A "\r" ending next to the edited text may join a "\n" at its start or end into one
+	// "\r\n", so the lines on either side of a "\r" are split again with it
+	if first > 0 && il.lines[first-1].ending == "\r" {
+		first--
+		text = il.lines[first].text + il.lines[first].ending + text
+	}
+	if last+1 < len(il.lines) && il.lines[last].ending == "\r" {
+		last++
+		text += il.lines[last].text + il.lines[last].ending
+	}
+	replacement := splitIncrementalLines(text)
+	if il.lines[last].ending != "" {
+		replacement = replacement[:len(replacement)-1]
+	}
+	delta := len(replacement) - (last - first + 1)
+
+	// The text of a dollar-quoted string open at the start of the first line isn't kept,
+	// so lexing starts again from the line that opened it
+	start := first
+	for start > 0 && il.lines[start].entry.dollarQuote != "" {
+		start--
+	}
+	if len(replacement) > 0 {
+		replacement[0].entry = il.lines[first].entry
+	}
+
+	previous := il.lines
+	il.lines = make([]incrementalLine, 0, len(previous)+delta)
+	il.lines = append(append(append(il.lines, previous[:first]...), replacement...), previous[last+1:]...)
+	stop := il.relex(start, first+len(replacement))
+	if delta != 0 {
+		for _, line := range il.lines[stop:] {
+			for i := range line.tokens {
+				line.tokens[i].SourceLine = uint(int(line.tokens[i].SourceLine) + delta)
+			}
+			for i := range line.warnings {
+				line.warnings[i].SourceLine = uint(int(line.warnings[i].SourceLine) + delta)
+			}
+		}
+	}
+
+	change := TokenChange{Start: countTokens(previous[:start]), LineDelta: delta}
+	change.End = change.Start + countTokens(previous[start:stop-delta])
+	for _, line := range il.lines[start:stop] {
+		change.Tokens = append(change.Tokens, line.tokens...)
+	}
+	return change, nil
+}
+
+func (il *IncrementalLexer) checkEdit(e Edit) error {
+	switch {
+	case e.StartLine < 1 || e.EndLine > uint(len(il.lines)) || e.StartLine > e.EndLine:
+		return fmt.Errorf("invalid edit: lines %d to %d of %d", e.StartLine, e.EndLine, len(il.lines))
+	case e.StartColumn > uint(len(il.lines[e.StartLine-1].text)):
+		return fmt.Errorf("invalid edit: column %d of line %d", e.StartColumn, e.StartLine)
+	case e.EndColumn > uint(len(il.lines[e.EndLine-1].text)):
+		return fmt.Errorf("invalid edit: column %d of line %d", e.EndColumn, e.EndLine)
+	case e.StartLine == e.EndLine && e.StartColumn > e.EndColumn:
+		return fmt.Errorf("invalid edit: column %d is after column %d", e.StartColumn, e.EndColumn)
+	}
+	return nil
+}
+
+// relex lexes the lines from the line start, which mustn't start in an open dollar-quoted
+// string, until a line at or after the line settled starts in the state it started in
+// before, returning the index of that line. The lines before settled are new or changed.
+func (il *IncrementalLexer) relex(start int, settled int) int {
+	state := il.lines[start].entry
+	il.lexer.setLineState(state)
+	i := start
+	for ; i < len(il.lines); i++ {
+		line := &il.lines[i]
+		if i >= settled && line.entry == state && state.dollarQuote == "" {
+			break
+		}
+		ending := line.ending
+		if ending == "" {
+			ending = string(newLine) // As Tokenize gives the last line
+		}
+		line.entry = state
+		il.lexer.warnings = nil
+		line.tokens, line.err = il.lexer.appendLineTokens(nil, line.text, ending, il.filename, uint(i+1))
+		line.warnings = il.lexer.warnings
+		state = il.lexer.lineState()
+	}
+	return i
+}
+
+// Tokens returns the tokens of the text, ending with an EOFType token.
+func (il *IncrementalLexer) Tokens() []Token {
+	tokens := make([]Token, 0, countTokens(il.lines)+1)
+	for _, line := range il.lines {
+		tokens = append(tokens, line.tokens...)
+	}
+	return append(tokens, newToken(TriviaCategory, EOFType, "", nil))
+}
+
+// Warnings returns the findings of the security checks enabled by WithSecurityChecks in
+// the text, in order.
+func (il *IncrementalLexer) Warnings() []Warning {
+	var warnings []Warning
+	for _, line := range il.lines {
+		warnings = append(warnings, line.warnings...)
+	}
+	return warnings
+}
+
+// Text returns the text, with its edits.
+func (il *IncrementalLexer) Text() string {
+	var b strings.Builder
+	for _, line := range il.lines {
+		b.WriteString(line.text)
+		b.WriteString(line.ending)
+	}
+	return b.String()
+}
+
+// Err returns the error of the first line that failed to lex, if any.
+func (il *IncrementalLexer) Err() error {
+	for i, line := range il.lines {
+		if line.err != nil {
+			return fmt.Errorf("[%s line: %d] %w", il.filename, i+1, line.err)
+		}
+	}
+	return nil
+}
+
+// splitIncrementalLines splits text into lines at "\n", "\r\n" and "\r". The last line
+// has no ending, and is empty if the text ends with a line ending.
+func splitIncrementalLines(text string) []incrementalLine {
+	var lines []incrementalLine
+	for {
+		i := strings.IndexAny(text, "\r\n")
+		if i < 0 {
+			return append(lines, incrementalLine{text: text})
+		}
+		ending := text[i : i+1]
+		if strings.HasPrefix(text[i:], "\r\n") {
+			ending = "\r\n"
+		}
+		lines = append(lines, incrementalLine{text: text[:i], ending: ending})
+		text = text[i+len(ending):]
+	}
+}
+
+func countTokens(lines []incrementalLine) int {
+	n := 0
+	for _, line := range lines {
+		n += len(line.tokens)
+	}
+	return n
+}
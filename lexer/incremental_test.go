@@ -0,0 +1,153 @@
+package lexer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+// applyChange applies a TokenChange to the tokens from before the edit.
+func applyChange(tokens []lexer.Token, change lexer.TokenChange) []lexer.Token {
+	var result []lexer.Token
+	result = append(result, tokens[:change.Start]...)
+	result = append(result, change.Tokens...)
+	for _, t := range tokens[change.End:] {
+		if t.ID != lexer.EOFType {
+			t.SourceLine = uint(int(t.SourceLine) + change.LineDelta)
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+// requireIncrementalEdits applies edits one by one, checking that the tokens are those of
+// lexing the edited text from scratch, and that each change turns the old tokens into them.
+func requireIncrementalEdits(t *testing.T, language func() *lexer.LanguageConfig, text string, edits []lexer.Edit, changes []lexer.TokenChange) {
+	il := lexer.NewIncrementalLexer(language(), "testfile", text)
+	for i, edit := range edits {
+		before := il.Tokens()
+		change, err := il.Edit(edit)
+		require.NoError(t, err)
+		require.NoError(t, il.Err())
+
+		expected, err := lexer.NewLexer(language()).Tokenize(strings.NewReader(il.Text()), "testfile")
+		require.NoError(t, err, il.Text())
+		require.Equal(t, expected[:len(expected)-1], il.Tokens()[:len(expected)-1], il.Text())
+		require.Equal(t, il.Tokens(), applyChange(before, change))
+
+		change.Tokens = nil
+		require.Equal(t, changes[i], change, "edit %d", i)
+	}
+}
+
+func TestIncrementalLexer(t *testing.T) {
+	text := "a = 1\nb = 2\r\nc = 3\n"
+	requireIncrementalEdits(t, NewBasicLanguage, text, []lexer.Edit{
+		// Only the edited line is lexed again
+		{StartLine: 2, StartColumn: 4, EndLine: 2, EndColumn: 5, Text: "20 + x"},
+		// Joining lines shifts the lines after them
+		{StartLine: 1, StartColumn: 5, EndLine: 2, EndColumn: 0, Text: " "},
+		// Splitting a line
+		{StartLine: 1, StartColumn: 5, EndLine: 1, EndColumn: 6, Text: "\r\n"},
+		// An insert at the end of the text
+		{StartLine: 4, StartColumn: 0, EndLine: 4, EndColumn: 0, Text: "d"},
+	}, []lexer.TokenChange{
+		{Start: 4, End: 8, LineDelta: 0},
+		{Start: 0, End: 10, LineDelta: -1},
+		{Start: 0, End: 9, LineDelta: 1},
+		{Start: 14, End: 14, LineDelta: 0},
+	})
+}
+
+func TestIncrementalBlockComments(t *testing.T) {
+	text := "a = 1\nb = 2\nc = 3\nd = 4\ne = 5"
+	requireIncrementalEdits(t, NewBasicLanguage, text, []lexer.Edit{
+		// Opening a comment changes every line after it
+		{StartLine: 2, StartColumn: 0, EndLine: 2, EndColumn: 0, Text: "/*"},
+		// Closing it changes the lines up to the end of the comment
+		{StartLine: 3, StartColumn: 5, EndLine: 3, EndColumn: 5, Text: "*/"},
+		// Lines in the comment aren't lexed again, nor are those after it
+		{StartLine: 2, StartColumn: 3, EndLine: 2, EndColumn: 4, Text: "two"},
+		// Removing the close reaches the end of the text
+		{StartLine: 3, StartColumn: 5, EndLine: 3, EndColumn: 7, Text: ""},
+	}, []lexer.TokenChange{
+		{Start: 4, End: 20, LineDelta: 0},
+		{Start: 4, End: 4, LineDelta: 0},
+		{Start: 4, End: 4, LineDelta: 0},
+		{Start: 4, End: 12, LineDelta: 0},
+	})
+}
+
+func TestIncrementalDollarQuotes(t *testing.T) {
+	text := "x = $$a\nb\nc$$\ny = 1"
+	requireIncrementalEdits(t, newSQLLanguage, text, []lexer.Edit{
+		// Editing inside the string lexes it again from the line that opened it
+		{StartLine: 2, StartColumn: 0, EndLine: 2, EndColumn: 1, Text: "bee"},
+		// Moving the end of the string lexes the lines that were in it, but not those after
+		{StartLine: 2, StartColumn: 3, EndLine: 3, EndColumn: 3, Text: "$$, 'z'\nc"},
+	}, []lexer.TokenChange{
+		{Start: 0, End: 5, LineDelta: 0},
+		{Start: 0, End: 5, LineDelta: 0},
+	})
+
+	il := lexer.NewIncrementalLexer(newSQLLanguage(), "testfile", text)
+	_, err := il.Edit(lexer.Edit{StartLine: 3, StartColumn: 1, EndLine: 3, EndColumn: 3})
+	require.NoError(t, err)
+	require.NoError(t, il.Err()) // The string is unterminated
+	require.Equal(t, []lexer.TokenIdentifier{lexer.IdentifierType, EqualsSymbolToken, lexer.EndOfLineType, lexer.EOFType}, tokenIDs(il.Tokens()))
+}
+
+func TestIncrementalErrors(t *testing.T) {
+	il := lexer.NewIncrementalLexer(newSQLLanguage(), "testfile", "a\nb")
+	for _, edit := range []lexer.Edit{
+		{StartLine: 0, EndLine: 1},
+		{StartLine: 2, EndLine: 3},
+		{StartLine: 2, EndLine: 1},
+		{StartLine: 1, StartColumn: 2, EndLine: 1, EndColumn: 2},
+		{StartLine: 1, StartColumn: 1, EndLine: 1, EndColumn: 0},
+	} {
+		_, err := il.Edit(edit)
+		require.ErrorContains(t, err, "invalid edit", "%+v", edit)
+	}
+	require.Equal(t, "a\nb", il.Text())
+
+	_, err := il.Edit(lexer.Edit{StartLine: 2, StartColumn: 1, EndLine: 2, EndColumn: 1, Text: ` "c`})
+	require.NoError(t, err)
+	require.ErrorContains(t, il.Err(), "[testfile line: 2] ")
+	require.ErrorContains(t, il.Err(), "unterminated quoted identifier")
+	_, err = il.Edit(lexer.Edit{StartLine: 2, StartColumn: 4, EndLine: 2, EndColumn: 4, Text: `"`})
+	require.NoError(t, err)
+	require.NoError(t, il.Err())
+}
+
+func TestIncrementalLineEndings(t *testing.T) {
+	// A "\n" next to a "\r" ending makes one "\r\n" ending, as it would from scratch
+	requireIncrementalEdits(t, NewBasicLanguage, "1\ra\rb\nc", []lexer.Edit{
+		// Inserting a "\n" at the start of a line after a "\r"
+		{StartLine: 2, StartColumn: 0, EndLine: 2, EndColumn: 0, Text: "\n"},
+		// Deleting the text between a "\r" and a "\n"
+		{StartLine: 3, StartColumn: 0, EndLine: 3, EndColumn: 1, Text: ""},
+	}, []lexer.TokenChange{
+		{Start: 0, End: 6, LineDelta: 0},
+		{Start: 2, End: 6, LineDelta: -1},
+	})
+}
+
+func TestIncrementalWarnings(t *testing.T) {
+	il := lexer.NewIncrementalLexer(NewBasicLanguage(), "testfile", "a = \"‮\"\nb = 1\n", lexer.WithSecurityChecks(lexer.AllSecurityChecks))
+	require.Len(t, il.Warnings(), 1)
+
+	// Warnings move with their line, and aren't repeated when it's lexed again
+	for i := 0; i < 3; i++ {
+		_, err := il.Edit(lexer.Edit{StartLine: 1, StartColumn: 0, EndLine: 1, EndColumn: 0, Text: "\n"})
+		require.NoError(t, err)
+	}
+	require.Len(t, il.Warnings(), 1)
+	require.Equal(t, uint(4), il.Warnings()[0].SourceLine)
+
+	_, err := il.Edit(lexer.Edit{StartLine: 4, StartColumn: 0, EndLine: 4, EndColumn: 8, Text: "a = 2"})
+	require.NoError(t, err)
+	require.Empty(t, il.Warnings())
+}
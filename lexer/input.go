@@ -0,0 +1,242 @@
+package lexer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+)
+
+// ErrInvalidUTF8 is returned, with the column of the first invalid byte, for input that
+// isn't valid UTF-8.
+var ErrInvalidUTF8 = errors.New("invalid UTF-8")
+
+// ErrInvalidUTF16 is returned, with the column at which it would be decoded, for an unpaired
+// surrogate or a trailing odd byte in UTF-16 input.
+var ErrInvalidUTF16 = errors.New("invalid UTF-16")
+
+// ErrLineTooLong is returned for lines longer than the limit set by WithMaxLineLength.
+var ErrLineTooLong = errors.New("line too long")
+
+// ErrTokenTooLong is returned for tokens longer than the limit set by WithMaxTokenLength.
+var ErrTokenTooLong = errors.New("token too long")
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// WithMaxLineLength limits the length of the lines read by Tokenize to n bytes, so that
+// untrusted input can't make the lexer hold an arbitrarily large line in memory. Longer lines
+// fail with ErrLineTooLong. Lines are unlimited by default.
+func WithMaxLineLength(n int) Option {
+	return func(l *Lexer) {
+		l.maxLineLength = n
+	}
+}
+
+// WithMaxTokenLength limits tokens, their Literal or string Value, to n bytes. Longer tokens fail with
+// ErrTokenTooLong, as soon as the built-in tokenizers have read more than n bytes of them, so
+// that untrusted input can't make the lexer build an arbitrarily long token. Tokens are
+// unlimited by default.
+func WithMaxTokenLength(n int) Option {
+	return func(l *Lexer) {
+		l.maxTokenLength = n
+		l.tokenCreator.maxTokenLength = n
+	}
+}
+
+// WithCharset decodes the input of Tokenize from a character set other than UTF-8, such as
+// charset.PETSCIIUpper or charmap.Windows1252. Byte order marks aren't looked for.
+func WithCharset(charset encoding.Encoding) Option {
+	return func(l *Lexer) {
+		l.charset = charset
+	}
+}
+
+// decodeInput returns a reader of the text of r as UTF-8, without any byte order mark.
+// UTF-16 input is recognised by its byte order mark.
+func (l *Lexer) decodeInput(r io.Reader) io.Reader {
+	if l.charset != nil {
+		return transform.NewReader(r, l.charset.NewDecoder())
+	}
+	br := bufio.NewReader(r)
+	bom, _ := br.Peek(len(utf8BOM))
+	switch {
+	case bytes.HasPrefix(bom, utf8BOM):
+		_, _ = br.Discard(len(utf8BOM))
+	case bytes.HasPrefix(bom, utf16LEBOM):
+		_, _ = br.Discard(len(utf16LEBOM))
+		return &utf16Reader{r: br}
+	case bytes.HasPrefix(bom, utf16BEBOM):
+		_, _ = br.Discard(len(utf16BEBOM))
+		return &utf16Reader{r: br, bigEndian: true}
+	}
+	return br
+}
+
+// utf16Reader decodes UTF-16 to UTF-8. Unlike golang.org/x/text's decoder, which substitutes
+// U+FFFD, it fails with ErrInvalidUTF16 at an unpaired surrogate, as invalid UTF-8 fails.
+type utf16Reader struct {
+	r         *bufio.Reader
+	bigEndian bool
+	column    int    // Byte offset in its line of the next rune decoded, for errors
+	decoded   []byte // Text decoded but not yet read
+	err       error  // Error to return once the decoded text has been read
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	if len(u.decoded) == 0 && u.err == nil {
+		u.decode(len(p))
+	}
+	if len(u.decoded) == 0 {
+		return 0, u.err
+	}
+	n := copy(p, u.decoded)
+	u.decoded = u.decoded[n:]
+	return n, nil
+}
+
+// decode decodes at least n bytes of text, unless it reaches the end of the input or an
+// error first.
+func (u *utf16Reader) decode(n int) {
+	u.decoded = u.decoded[:0]
+	for len(u.decoded) < n {
+		r, err := u.readRune()
+		if err != nil {
+			u.err = err
+			return
+		}
+		u.decoded = utf8.AppendRune(u.decoded, r)
+		if r == '\n' || r == '\r' {
+			u.column = 0
+		} else {
+			u.column += utf8.RuneLen(r)
+		}
+	}
+}
+
+func (u *utf16Reader) readRune() (rune, error) {
+	var unit [2]byte
+	if _, err := io.ReadFull(u.r, unit[:]); err == io.ErrUnexpectedEOF {
+		return 0, fmt.Errorf("%w at column %d: odd number of bytes", ErrInvalidUTF16, u.column)
+	} else if err != nil {
+		return 0, err
+	}
+	r := u.rune(unit[:])
+	if !utf16.IsSurrogate(r) {
+		return r, nil
+	}
+	if next, err := u.r.Peek(2); err == nil && r < 0xDC00 { // A high surrogate, which a low one must follow
+		if pair := utf16.DecodeRune(r, u.rune(next)); pair != utf8.RuneError {
+			_, _ = u.r.Discard(2)
+			return pair, nil
+		}
+	}
+	return 0, fmt.Errorf("%w at column %d: unpaired surrogate %U", ErrInvalidUTF16, u.column, r)
+}
+
+// rune returns the UTF-16 code unit starting unit.
+func (u *utf16Reader) rune(unit []byte) rune {
+	if u.bigEndian {
+		return rune(unit[0])<<8 | rune(unit[1])
+	}
+	return rune(unit[1])<<8 | rune(unit[0])
+}
+
+// lineReader reads lines of any length, separating them from their line endings, which may
+// be "\n", "\r\n" or a lone "\r".
+type lineReader struct {
+	r         *bufio.Reader
+	line      []byte
+	maxLength int // Maximum length of a line in bytes, no limit if 0
+}
+
+func newLineReader(r io.Reader, maxLength int) *lineReader {
+	return &lineReader{r: bufio.NewReader(r), maxLength: maxLength}
+}
+
+// readLine returns the next line and its line ending, or io.EOF at the end of the input. The
+// last line of the input may have no line ending and is given "\n", as TokenizeLine is.
+func (lr *lineReader) readLine() (string, string, error) {
+	lr.line = lr.line[:0]
+	for {
+		if lr.r.Buffered() == 0 {
+			if _, err := lr.r.Peek(1); err != nil {
+				if err == io.EOF && len(lr.line) > 0 {
+					return string(lr.line), string(newLine), nil
+				}
+				return "", "", err
+			}
+		}
+		buffered, _ := lr.r.Peek(lr.r.Buffered())
+		i := bytes.IndexAny(buffered, "\r\n")
+		if i < 0 {
+			i = len(buffered)
+		}
+		if lr.maxLength > 0 && len(lr.line)+i > lr.maxLength {
+			return "", "", fmt.Errorf("%w: longer than %d bytes", ErrLineTooLong, lr.maxLength)
+		}
+		lr.line = append(lr.line, buffered[:i]...)
+		if i == len(buffered) {
+			_, _ = lr.r.Discard(i)
+			continue
+		}
+
+		_, _ = lr.r.Discard(i + 1)
+		if buffered[i] == '\n' {
+			return string(lr.line), "\n", nil
+		}
+		if next, err := lr.r.Peek(1); err == nil && next[0] == '\n' {
+			_, _ = lr.r.Discard(1)
+			return string(lr.line), "\r\n", nil
+		}
+		return string(lr.line), "\r", nil
+	}
+}
+
+// checkLength returns ErrTokenTooLong for a token being built whose Literal or Value is
+// already length bytes, if that's longer than the maximum token length.
+func (tf *TokenCreator) checkLength(length int) error {
+	if tf.maxTokenLength > 0 && length > tf.maxTokenLength {
+		return fmt.Errorf("%w: token is longer than %d bytes", ErrTokenTooLong, tf.maxTokenLength)
+	}
+	return nil
+}
+
+// checkTokenLengths returns an error for the first token longer than the maximum token length,
+// for the tokens that aren't checked while they're built, such as those of TokenCreators.
+func (l *Lexer) checkTokenLengths(tokens []Token) error {
+	for _, token := range tokens {
+		length := len(token.Literal)
+		if value, ok := token.Value.(string); ok && len(value) > length {
+			length = len(value)
+		}
+		if length > l.maxTokenLength {
+			return fmt.Errorf("%w: token at column %d is longer than %d bytes", ErrTokenTooLong, token.SourceColumn, l.maxTokenLength)
+		}
+	}
+	return nil
+}
+
+// validateUTF8 returns an error positioned at the first invalid byte of line, if any.
+func validateUTF8(line string) error {
+	if utf8.ValidString(line) {
+		return nil
+	}
+	for i, r := range line {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(line[i:]); size == 1 {
+				return fmt.Errorf("%w at column %d", ErrInvalidUTF8, i)
+			}
+		}
+	}
+	return ErrInvalidUTF8
+}
@@ -0,0 +1,149 @@
+package lexer_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineEndings(t *testing.T) {
+	tokens, err := NewBasicLexer().Tokenize(strings.NewReader("a = 1\r\nb = 2\rc = 3\nd = 4"), "testfile")
+	require.NoError(t, err)
+
+	var endings []string
+	var lines []uint
+	for _, token := range tokens {
+		if token.ID == lexer.EndOfLineType {
+			endings = append(endings, token.Literal)
+			lines = append(lines, token.SourceLine)
+		}
+	}
+	require.Equal(t, []string{"\r\n", "\r", "\n", "\n"}, endings)
+	require.Equal(t, []uint{1, 2, 3, 4}, lines)
+}
+
+func TestCRLFSplitAcrossReads(t *testing.T) {
+	// Reading a byte at a time, every "\r" is at the end of the data read so far
+	tokens, err := NewBasicLexer().Tokenize(&chunkedReader{data: []byte("a = 1\r\nb = 2\r\n"), size: 1}, "testfile")
+	require.NoError(t, err)
+	require.Equal(t, "\r\n", tokens[3].Literal)
+	require.Equal(t, uint(2), tokens[len(tokens)-2].SourceLine)
+	require.Equal(t, "\r\n", tokens[len(tokens)-2].Literal)
+}
+
+func TestByteOrderMarks(t *testing.T) {
+	expected, err := NewBasicLexer().Tokenize(strings.NewReader("a = \"é\"\n"), "testfile")
+	require.NoError(t, err)
+
+	utf8Source := append([]byte{0xEF, 0xBB, 0xBF}, "a = \"é\"\n"...)
+	tokens, err := NewBasicLexer().Tokenize(bytes.NewReader(utf8Source), "testfile")
+	require.NoError(t, err)
+	require.Equal(t, expected, tokens)
+
+	units := utf16.Encode([]rune("a = \"é\"\n"))
+	le := []byte{0xFF, 0xFE}
+	be := []byte{0xFE, 0xFF}
+	for _, u := range units {
+		le = append(le, byte(u), byte(u>>8))
+		be = append(be, byte(u>>8), byte(u))
+	}
+	for _, source := range [][]byte{le, be} {
+		tokens, err := NewBasicLexer().Tokenize(bytes.NewReader(source), "testfile")
+		require.NoError(t, err)
+		require.Equal(t, expected, tokens)
+	}
+}
+
+func TestInvalidUTF16(t *testing.T) {
+	utf16LE := func(units ...uint16) []byte {
+		source := []byte{0xFF, 0xFE}
+		for _, u := range units {
+			source = append(source, byte(u), byte(u>>8))
+		}
+		return source
+	}
+	a, eq, one, lf, quote := uint16('a'), uint16('='), uint16('1'), uint16('\n'), uint16('"')
+
+	for source, message := range map[string]string{
+		string(utf16LE(a, lf, quote, 0xD800, quote, lf)):         "[testfile line: 2] invalid UTF-16 at column 1: unpaired surrogate U+D800",
+		string(utf16LE(a, eq, quote, 0xDC00, 0xD800, quote, lf)): "[testfile line: 1] invalid UTF-16 at column 3: unpaired surrogate U+DC00",
+		string(utf16LE(a, lf, 0xD834, one, lf)):                  "[testfile line: 2] invalid UTF-16 at column 0: unpaired surrogate U+D834",
+		string(utf16LE(a, lf, a, 0xD834)):                        "[testfile line: 2] invalid UTF-16 at column 1: unpaired surrogate U+D834",
+		string(append(utf16LE(a, lf, a), '=')):                   "[testfile line: 2] invalid UTF-16 at column 1: odd number of bytes",
+	} {
+		_, err := NewBasicLexer().Tokenize(strings.NewReader(source), "testfile")
+		require.ErrorIs(t, err, lexer.ErrInvalidUTF16)
+		require.ErrorContains(t, err, message)
+	}
+
+	// A surrogate pair is a single rune
+	tokens, err := NewBasicLexer().Tokenize(bytes.NewReader(utf16LE(quote, 0xD834, 0xDD1E, quote)), "testfile")
+	require.NoError(t, err)
+	require.Equal(t, "𝄞", tokens[0].Value)
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	_, err := NewBasicLexer().Tokenize(strings.NewReader("a = 1\nb = \"\xff\"\n"), "testfile")
+	require.ErrorIs(t, err, lexer.ErrInvalidUTF8)
+	require.ErrorContains(t, err, "[testfile line: 2] invalid UTF-8 at column 5")
+
+	// A U+FFFD written in the source is valid
+	tokens, err := NewBasicLexer().TokenizeLine("a = \"�\"", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, "�", tokens[2].Value)
+}
+
+// chunkedReader returns its data in reads of at most size bytes.
+type chunkedReader struct {
+	data []byte
+	size int
+}
+
+func (r *chunkedReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p[:min(len(p), r.size)], r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestLongLines(t *testing.T) {
+	// Lines aren't limited to bufio.Scanner's 64 KiB
+	long := "a = \"" + strings.Repeat("x", 200_000) + "\"\r" + strings.Repeat("b + ", 50_000) + "1\n"
+	tokens, err := NewBasicLexer().Tokenize(strings.NewReader(long), "testfile")
+	require.NoError(t, err)
+	require.Len(t, tokens[2].Value, 200_000)
+	require.Equal(t, uint(2), tokens[len(tokens)-2].SourceLine)
+	require.Len(t, tokens, 3+1+100_001+1+1)
+}
+
+func TestMaxLineLength(t *testing.T) {
+	l := lexer.NewLexer(NewBasicLanguage(), lexer.WithMaxLineLength(10))
+	_, err := l.Tokenize(strings.NewReader("a = 1\nb = 1 + 2 + 3\n"), "testfile")
+	require.ErrorIs(t, err, lexer.ErrLineTooLong)
+	require.ErrorContains(t, err, "[testfile line: 2]")
+
+	_, err = l.Tokenize(strings.NewReader("a = 123456\n"), "testfile")
+	require.NoError(t, err, "The line ending isn't part of the line's length")
+}
+
+func TestMaxTokenLength(t *testing.T) {
+	l := lexer.NewLexer(NewBasicLanguage(), lexer.WithMaxTokenLength(4))
+	_, err := l.Tokenize(strings.NewReader("a = \"abcd\"\nb = \"abcde\"\n"), "testfile")
+	require.ErrorIs(t, err, lexer.ErrTokenTooLong)
+	require.ErrorContains(t, err, "[testfile line: 2]")
+
+	// Tokens fail as they're built, before the rest of the line or a later line is read
+	_, err = l.TokenizeLine("a = abcdefgh ~", "testfile", 0)
+	require.ErrorIs(t, err, lexer.ErrTokenTooLong)
+	l = lexer.NewLexer(newSQLLanguage(), lexer.WithMaxTokenLength(4))
+	_, err = l.Tokenize(strings.NewReader("x = $$ab\ncdef\n ~ $$"), "testfile")
+	require.ErrorIs(t, err, lexer.ErrTokenTooLong)
+	require.ErrorContains(t, err, "[testfile line: 2]")
+}
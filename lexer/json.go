@@ -0,0 +1,302 @@
+package lexer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// JSONWhitespace are the runes RFC 8259 allows between the tokens of JSON text, for
+// LanguageConfig.Whitespace.
+const JSONWhitespace = " \t\r\n"
+
+// JSONStringTokenizer processes the strings of RFC 8259 JSON, started by '"'. The escapes
+// are \" \\ \/ \b \f \n \r \t and \uXXXX, where a surrogate pair is one rune and a lone
+// surrogate is U+FFFD. Other escapes, unescaped control characters and unterminated strings
+// are errors.
+func JSONStringTokenizer(tf *TokenCreator, initialString string) TokenizerHandler {
+	return (&jsonStringTokenizer{tf: tf}).start(initialString)
+}
+
+// JSON5StringTokenizer processes the strings of JSON5, started by a double or single quote.
+// In addition to the escapes of JSONStringTokenizer there are \' \v \0 and \xXX, and any
+// other rune but a digit escapes itself. Control characters are allowed, but a string must
+// end on the line it starts on.
+func JSON5StringTokenizer(tf *TokenCreator, initialString string) TokenizerHandler {
+	return (&jsonStringTokenizer{tf: tf, json5: true}).start(initialString)
+}
+
+// jsonEscapes maps the runes escaped by a backslash in JSON strings to the runes they stand for.
+var jsonEscapes = map[rune]rune{'"': '"', '\\': '\\', '/': '/', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t'}
+
+type jsonStringTokenizer struct {
+	tf        *TokenCreator
+	json5     bool
+	quote     rune
+	builder   []byte
+	escaped   bool
+	hexEscape rune   // 'u' or 'x' while the digits of a \u or \x escape are read, otherwise 0
+	hex       []byte // Digits of the escape read so far
+	surrogate rune   // High surrogate waiting for its low surrogate, 0 if none
+}
+
+func (s *jsonStringTokenizer) start(initialString string) TokenizerHandler {
+	s.quote, _ = utf8.DecodeRuneInString(initialString)
+	s.builder = s.builder[:0]
+	s.escaped = false
+	s.hexEscape = 0
+	s.surrogate = 0
+	return s.tokenize
+}
+
+func (s *jsonStringTokenizer) tokenize(r rune) ([]Token, completed, error) {
+	if r == newLine {
+		return nil, false, fmt.Errorf("unterminated string")
+	}
+	if s.hexEscape != 0 {
+		if err := s.hexDigit(r); err != nil {
+			return nil, false, err
+		}
+		return nil, false, s.tf.checkLength(len(s.builder))
+	}
+	if s.escaped {
+		s.escaped = false
+		if err := s.escape(r); err != nil {
+			return nil, false, err
+		}
+		return nil, false, s.tf.checkLength(len(s.builder))
+	}
+
+	if r < 0x20 && !s.json5 {
+		return nil, false, fmt.Errorf("control character %U in string", r)
+	}
+	// A pending high surrogate may be followed by the \u escape of its low surrogate
+	if r != '\\' {
+		s.endSurrogate()
+	}
+	switch r {
+	case '\\':
+		s.escaped = true
+	case s.quote:
+		return s.tf.emit(newToken(LiteralCategory, StringLiteral, "", string(s.builder))), true, nil
+	default:
+		s.builder = utf8.AppendRune(s.builder, r)
+	}
+	return nil, false, s.tf.checkLength(len(s.builder))
+}
+
+// escape adds the rune escaped by a backslash followed by r to the string.
+func (s *jsonStringTokenizer) escape(r rune) error {
+	if r == 'u' {
+		s.hex, s.hexEscape = s.hex[:0], r
+		return nil
+	}
+	s.endSurrogate()
+	if r == 'x' && s.json5 {
+		s.hex, s.hexEscape = s.hex[:0], r
+		return nil
+	}
+
+	escaped, found := jsonEscapes[r]
+	switch {
+	case found:
+	case !s.json5:
+		return fmt.Errorf("invalid escape \\%c in string", r)
+	case r == 'v':
+		escaped = '\v'
+	case r == '0':
+		escaped = 0
+	case '1' <= r && r <= '9':
+		return fmt.Errorf("invalid escape \\%c in string", r)
+	default:
+		escaped = r
+	}
+	s.builder = utf8.AppendRune(s.builder, escaped)
+	return nil
+}
+
+// hexDigit reads a digit of a \u or \x escape, adding its rune once all the digits are read.
+func (s *jsonStringTokenizer) hexDigit(r rune) error {
+	if !isHexDigit(r) {
+		return fmt.Errorf("invalid escape \\%c%s%c in string", s.hexEscape, s.hex, r)
+	}
+	s.hex = append(s.hex, byte(r))
+	if (s.hexEscape == 'u' && len(s.hex) < 4) || (s.hexEscape == 'x' && len(s.hex) < 2) {
+		return nil
+	}
+	s.hexEscape = 0
+	value, _ := strconv.ParseUint(string(s.hex), 16, 32)
+	unit := rune(value)
+
+	switch {
+	case s.surrogate != 0 && utf16.IsSurrogate(unit) && unit >= 0xDC00:
+		s.builder = utf8.AppendRune(s.builder, utf16.DecodeRune(s.surrogate, unit))
+		s.surrogate = 0
+		return nil
+	case len(s.hex) == 4 && utf16.IsSurrogate(unit) && unit < 0xDC00:
+		s.endSurrogate()
+		s.surrogate = unit
+		return nil
+	}
+	s.endSurrogate()
+	if utf16.IsSurrogate(unit) {
+		unit = utf8.RuneError
+	}
+	s.builder = utf8.AppendRune(s.builder, unit)
+	return nil
+}
+
+// endSurrogate adds U+FFFD for a high surrogate that isn't followed by a low surrogate.
+func (s *jsonStringTokenizer) endSurrogate() {
+	if s.surrogate != 0 {
+		s.builder = utf8.AppendRune(s.builder, utf8.RuneError)
+		s.surrogate = 0
+	}
+}
+
+// JSONNumberTokenizer processes the numbers of RFC 8259 JSON, started by '-' or a digit,
+// e.g. "-12", "0.5" or "6.02e23". Numbers without a fraction or exponent that fit in an int64
+// are IntegerLiterals, others are NumberLiterals with a float64 Value. Leading zeros, a '+'
+// sign and a decimal point without digits either side are errors.
+func JSONNumberTokenizer(tf *TokenCreator, initialString string) TokenizerHandler {
+	return (&jsonNumberTokenizer{tf: tf}).start(initialString)
+}
+
+// JSON5NumberTokenizer processes the numbers of JSON5, started by '+', '-', '.' or a digit.
+// In addition to the numbers of JSONNumberTokenizer there are hexadecimal numbers such as
+// "0xFF", which are HexLiterals with an int64 Value, Infinity and NaN, decimal points without
+// digits either side, e.g. ".5" and "5.", and a '+' sign.
+func JSON5NumberTokenizer(tf *TokenCreator, initialString string) TokenizerHandler {
+	return (&jsonNumberTokenizer{tf: tf, json5: true}).start(initialString)
+}
+
+type jsonNumberTokenizer struct {
+	tf      *TokenCreator
+	json5   bool
+	literal []byte
+}
+
+func (n *jsonNumberTokenizer) start(initialString string) TokenizerHandler {
+	n.literal = append(n.literal[:0], initialString...)
+	return n.tokenize
+}
+
+func (n *jsonNumberTokenizer) tokenize(r rune) ([]Token, completed, error) {
+	if n.continues(r) {
+		n.literal = utf8.AppendRune(n.literal, r)
+		return nil, false, n.tf.checkLength(len(n.literal))
+	}
+
+	n.tf.SetOverFlow(r)
+	literal := string(n.literal)
+	t, ok := jsonNumber(literal, n.json5)
+	if !ok {
+		return nil, false, fmt.Errorf("invalid number %s", literal)
+	}
+	return n.tf.emit(t), true, nil
+}
+
+// continues reports whether r is part of the number being read: an ASCII letter, digit or
+// '.', so that a number run into an identifier is an error, or a sign after the exponent's 'e'.
+func (n *jsonNumberTokenizer) continues(r rune) bool {
+	if r == '+' || r == '-' {
+		last := n.literal[len(n.literal)-1]
+		return (last == 'e' || last == 'E') && !isJSONHex(string(n.literal))
+	}
+	return r == '.' || ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
+
+// jsonNumber returns the token of a JSON or JSON5 number, reporting false if it isn't valid.
+func jsonNumber(literal string, json5 bool) (Token, bool) {
+	unsigned := literal
+	if json5 {
+		unsigned = strings.TrimLeft(literal, "+-")
+		if len(literal)-len(unsigned) > 1 {
+			return Token{}, false
+		}
+	} else {
+		unsigned = strings.TrimPrefix(literal, "-")
+	}
+	negative := strings.HasPrefix(literal, "-")
+
+	switch {
+	case json5 && unsigned == "Infinity":
+		sign := 1
+		if negative {
+			sign = -1
+		}
+		return newToken(LiteralCategory, NumberLiteral, literal, math.Inf(sign)), true
+	case json5 && unsigned == "NaN":
+		return newToken(LiteralCategory, NumberLiteral, literal, math.NaN()), true
+	case json5 && isJSONHex(unsigned):
+		value, err := strconv.ParseInt(unsigned[2:], 16, 64)
+		if err != nil || len(unsigned) == 2 {
+			return Token{}, false
+		}
+		if negative {
+			value = -value
+		}
+		return newToken(LiteralCategory, HexLiteral, literal, value), true
+	case !isJSONDecimal(unsigned, json5):
+		return Token{}, false
+	}
+
+	if !strings.ContainsAny(unsigned, ".eE") {
+		if value, err := strconv.ParseInt(literal, 10, 64); err == nil {
+			return newToken(LiteralCategory, IntegerLiteral, literal, value), true
+		}
+	}
+	value, _ := strconv.ParseFloat(literal, 64) // Out of range numbers are ±Inf or 0
+	return newToken(LiteralCategory, NumberLiteral, literal, value), true
+}
+
+// isJSONHex reports whether s starts like a hexadecimal number, "0x" or "0X".
+func isJSONHex(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	return len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X')
+}
+
+// isJSONDecimal reports whether s is an unsigned decimal number: an integer part without
+// leading zeros, an optional fraction and an optional exponent. JSON5 allows either the
+// integer part or the fraction's digits to be empty.
+func isJSONDecimal(s string, json5 bool) bool {
+	i := 0
+	digits := func() int {
+		start := i
+		for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+			i++
+		}
+		return i - start
+	}
+
+	integer := digits()
+	if integer > 1 && s[0] == '0' {
+		return false
+	}
+	fraction := -1 // No fraction
+	if i < len(s) && s[i] == '.' {
+		i++
+		fraction = digits()
+	}
+	if (integer == 0 && !(json5 && fraction > 0)) || (fraction == 0 && !(json5 && integer > 0)) {
+		return false
+	}
+
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return i == len(s)
+}
+
+func isHexDigit(r rune) bool {
+	return '0' <= r && r <= '9' || 'a' <= r && r <= 'f' || 'A' <= r && r <= 'F'
+}
@@ -0,0 +1,160 @@
+package lexer_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+// newJSONLexer returns a lexer of JSON strings and numbers, or of JSON5's if json5.
+func newJSONLexer(json5 bool) *lexer.Lexer {
+	strings, numbers := lexer.JSONStringTokenizer, lexer.JSONNumberTokenizer
+	starts := "-0123456789"
+	if json5 {
+		strings, numbers = lexer.JSON5StringTokenizer, lexer.JSON5NumberTokenizer
+		starts = "+-.0123456789"
+	}
+	b := lexer.NewLanguage("json").
+		Symbol(',', CommaToken).
+		Prefix(`"`, strings).
+		Prefix("'", strings).
+		Whitespace(lexer.JSONWhitespace)
+	for _, r := range starts {
+		b.Prefix(string(r), numbers)
+	}
+	return lexer.NewLexer(b.MustBuild())
+}
+
+func TestJSONStrings(t *testing.T) {
+	tests := []struct {
+		source string
+		value  string
+	}{
+		{`"plain"`, "plain"},
+		{`"\"\\\/\b\f\n\r\t"`, "\"\\/\b\f\n\r\t"},
+		{`"é€"`, "é€"},
+		{`"𝄞"`, "𝄞"},
+		{`"\uD834\uDD1E"`, "𝄞"},
+		{`"\uDBFF\uDFFE"`, "\U0010FFFE"},
+		{`"\ud83d\ude00!"`, "😀!"},
+		{`"\uD800"`, "�"},
+		{`"\uD800\uD800\uDC00"`, "�𐀀"},
+		{`"\uD800\n"`, "�\n"},
+		{`"\uD800\u0041"`, "�A"},
+		{`"\uD800x"`, "�x"},
+		{`"\uDC00\uD800A"`, "��A"},
+		{`"\uD800𝄞"`, "�𝄞"},
+		{"\" \x7f\"", " \x7f"},
+	}
+	for _, test := range tests {
+		tokens, err := newJSONLexer(false).TokenizeLine(test.source, "test", 1)
+		require.NoError(t, err, test.source)
+		require.Equal(t, lexer.StringLiteral, tokens[0].ID, test.source)
+		require.Equal(t, test.value, tokens[0].Value, test.source)
+	}
+
+	for source, message := range map[string]string{
+		`"a`:       "unterminated string",
+		"\"a\tb\"": "control character U+0009 in string",
+		`"\a"`:     `invalid escape \a in string`,
+		`"\u12G4"`: `invalid escape \u12G in string`,
+	} {
+		_, err := newJSONLexer(false).TokenizeLine(source, "test", 1)
+		require.ErrorContains(t, err, message, source)
+	}
+}
+
+func TestJSON5Strings(t *testing.T) {
+	tests := []struct {
+		source string
+		value  string
+	}{
+		{`'it\'s'`, "it's"},
+		{`"say \"hi\""`, `say "hi"`},
+		{`'\x41\v\0\q'`, "A\v\x00q"},
+		{"'a\tb'", "a\tb"},
+		{`'\uD834\uDD1E'`, "𝄞"},
+		{`'\uD834\x41'`, "�A"},
+	}
+	for _, test := range tests {
+		tokens, err := newJSONLexer(true).TokenizeLine(test.source, "test", 1)
+		require.NoError(t, err, test.source)
+		require.Equal(t, test.value, tokens[0].Value, test.source)
+	}
+
+	for _, source := range []string{`'\1'`, `'\x4'`, `'a`} {
+		_, err := newJSONLexer(true).TokenizeLine(source, "test", 1)
+		require.Error(t, err, source)
+	}
+}
+
+func TestJSONNumbers(t *testing.T) {
+	tests := []struct {
+		source string
+		id     lexer.TokenIdentifier
+		value  any
+	}{
+		{"0", lexer.IntegerLiteral, int64(0)},
+		{"-12", lexer.IntegerLiteral, int64(-12)},
+		{"0.5", lexer.NumberLiteral, 0.5},
+		{"-1.5e3", lexer.NumberLiteral, -1500.0},
+		{"1E-2", lexer.NumberLiteral, 0.01},
+		{"2e+1", lexer.NumberLiteral, 20.0},
+		{"9223372036854775808", lexer.NumberLiteral, 9223372036854775808.0},
+	}
+	for _, test := range tests {
+		tokens, err := newJSONLexer(false).TokenizeLine(test.source+",", "test", 1)
+		require.NoError(t, err, test.source)
+		require.Equal(t, test.id, tokens[0].ID, test.source)
+		require.Equal(t, test.source, tokens[0].Literal)
+		require.Equal(t, test.value, tokens[0].Value, test.source)
+		require.Equal(t, lexer.LiteralCategory, tokens[0].Category)
+	}
+
+	for _, source := range []string{"-", "01", "-01", "1.", "-.5", "-+1", "1e", "1e+", "1.2.3", "1x", "0x1F", "--1", "- 1"} {
+		_, err := newJSONLexer(false).TokenizeLine(source, "test", 1)
+		require.ErrorContains(t, err, "invalid number", source)
+	}
+}
+
+func TestJSON5Numbers(t *testing.T) {
+	tests := []struct {
+		source string
+		id     lexer.TokenIdentifier
+		value  any
+	}{
+		{"+1", lexer.IntegerLiteral, int64(1)},
+		{".5", lexer.NumberLiteral, 0.5},
+		{"5.", lexer.NumberLiteral, 5.0},
+		{"-.5e1", lexer.NumberLiteral, -5.0},
+		{"0xFF", lexer.HexLiteral, int64(255)},
+		{"-0X10", lexer.HexLiteral, int64(-16)},
+		{"0x1e", lexer.HexLiteral, int64(30)},
+		{"+Infinity", lexer.NumberLiteral, math.Inf(1)},
+	}
+	for _, test := range tests {
+		tokens, err := newJSONLexer(true).TokenizeLine(test.source+",", "test", 1)
+		require.NoError(t, err, test.source)
+		require.Equal(t, test.id, tokens[0].ID, test.source)
+		require.Equal(t, test.value, tokens[0].Value, test.source)
+		require.Equal(t, CommaToken, tokens[1].ID, test.source)
+	}
+
+	for _, source := range []string{".", "+-1", "0x", "0xG", "01", "-Infinite", "1e"} {
+		_, err := newJSONLexer(true).TokenizeLine(source, "test", 1)
+		require.ErrorContains(t, err, "invalid number", source)
+	}
+}
+
+func TestJSONWhitespace(t *testing.T) {
+	tokens, err := newJSONLexer(false).TokenizeLine("\t1 ,\r2", "test", 1)
+	require.NoError(t, err)
+	require.Len(t, tokens, 4)
+
+	for _, source := range []string{"\f1", "\v1", "\u00a01"} {
+		_, err := newJSONLexer(false).TokenizeLine(source, "test", 1)
+		require.ErrorContains(t, err, "unknown character", source)
+	}
+}
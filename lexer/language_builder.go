@@ -0,0 +1,346 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// LanguageBuilder builds a LanguageConfig one entry at a time, e.g.
+//
+//	config, err := lexer.NewLanguage("basic").
+//		Keyword("if", IfToken).
+//		Operator("<=", LessThanOrEqualToken).
+//		Symbol('+', AddToken).
+//		LineComment("//").
+//		BlockComment("/*", "*/").
+//		HexPrefix("$").
+//		Build()
+//
+// Symbols used by operators and comment delimiters are registered automatically, with token
+// identifiers allocated above LastStdLiteral and any identifier given explicitly.
+type LanguageBuilder struct {
+	config   LanguageConfig
+	implied  []string // Operators and comment openers whose runes are registered as symbols at Build
+	problems []error
+}
+
+// NewLanguage returns a builder for a language with the given name.
+func NewLanguage(name string) *LanguageBuilder {
+	return &LanguageBuilder{config: LanguageConfig{
+		Name:             name,
+		Keywords:         make(map[string]TokenIdentifier),
+		Operators:        make(map[string]TokenIdentifier),
+		Symbols:          make(map[rune]TokenIdentifier),
+		Comments:         make(map[string]string),
+		PrefixTokenizers: make(map[string]TokenizerFunc),
+	}}
+}
+
+// Keyword adds a keyword.
+func (b *LanguageBuilder) Keyword(keyword string, id TokenIdentifier) *LanguageBuilder {
+	if existing, found := b.config.Keywords[keyword]; found && existing != id {
+		b.problems = append(b.problems, fmt.Errorf("keyword %q is already token %d", keyword, existing))
+	}
+	b.config.Keywords[keyword] = id
+	return b
+}
+
+// Operator adds a multi-character operator, registering any of its runes that aren't yet symbols.
+func (b *LanguageBuilder) Operator(operator string, id TokenIdentifier) *LanguageBuilder {
+	if existing, found := b.config.Operators[operator]; found && existing != id {
+		b.problems = append(b.problems, fmt.Errorf("operator %q is already token %d", operator, existing))
+	}
+	b.config.Operators[operator] = id
+	b.implied = append(b.implied, operator)
+	return b
+}
+
+// Symbol adds a single rune symbol.
+func (b *LanguageBuilder) Symbol(r rune, id TokenIdentifier) *LanguageBuilder {
+	if existing, found := b.config.Symbols[r]; found && existing != id {
+		b.problems = append(b.problems, fmt.Errorf("symbol %s is already token %d", strconv.QuoteRune(r), existing))
+	}
+	b.config.Symbols[r] = id
+	return b
+}
+
+// LineComment adds a comment that runs from open to the end of the line, e.g. "//" or "rem".
+func (b *LanguageBuilder) LineComment(open string) *LanguageBuilder {
+	return b.BlockComment(open, "\n")
+}
+
+// BlockComment adds a comment that runs from open to close, e.g. "/*" and "*/".
+// The runes of multi-rune openers that aren't identifiers are registered as symbols.
+func (b *LanguageBuilder) BlockComment(open, close string) *LanguageBuilder {
+	if existing, found := b.config.Comments[open]; found && existing != close {
+		b.problems = append(b.problems, fmt.Errorf("comment %q already closes with %q", open, existing))
+	}
+	b.config.Comments[open] = close
+	if len(open) > 1 {
+		b.implied = append(b.implied, open)
+	}
+	return b
+}
+
+// Prefix adds a tokenizer started by prefix.
+func (b *LanguageBuilder) Prefix(prefix string, tokenizer TokenizerFunc) *LanguageBuilder {
+	b.config.PrefixTokenizers[prefix] = tokenizer
+	return b
+}
+
+// HexPrefix adds a hexadecimal literal prefix, e.g. "$" or "0x".
+func (b *LanguageBuilder) HexPrefix(prefix string) *LanguageBuilder {
+	return b.Prefix(prefix, HexTokenizer)
+}
+
+// BinaryPrefix adds a binary literal prefix, e.g. "%" or "0b".
+func (b *LanguageBuilder) BinaryPrefix(prefix string) *LanguageBuilder {
+	return b.Prefix(prefix, BinaryTokenizer)
+}
+
+// IdentifierRunes sets the extra runes that are valid inside an identifier, e.g. "_".
+func (b *LanguageBuilder) IdentifierRunes(runes string) *LanguageBuilder {
+	b.config.ExtendedIdentifierRunes = runes
+	return b
+}
+
+// IdentifierTermination sets the runes that end an identifier and are included in it, e.g. ":".
+func (b *LanguageBuilder) IdentifierTermination(runes string) *LanguageBuilder {
+	b.config.IdentifierTermination = runes
+	return b
+}
+
+// StringDelimiters sets the runes that open and close string literals.
+func (b *LanguageBuilder) StringDelimiters(runes string) *LanguageBuilder {
+	b.config.StringDelimiters = runes
+	return b
+}
+
+// Whitespace sets the runes that separate tokens, e.g. JSONWhitespace, in place of any
+// Unicode white space.
+func (b *LanguageBuilder) Whitespace(runes string) *LanguageBuilder {
+	b.config.Whitespace = runes
+	return b
+}
+
+// QuotedIdentifier adds delimiters of identifiers that may contain any rune, such as SQL's
+// "order" or [order], e.g. QuotedIdentifier('[', ']'). A doubled closing delimiter stands for
+// itself.
+func (b *LanguageBuilder) QuotedIdentifier(open, close rune) *LanguageBuilder {
+	if b.config.QuotedIdentifiers == nil {
+		b.config.QuotedIdentifiers = make(map[rune]rune)
+	}
+	b.config.QuotedIdentifiers[open] = close
+	return b
+}
+
+// DoubledQuoteEscapes makes a doubled delimiter the only escape in strings, e.g. "say ""hi""".
+func (b *LanguageBuilder) DoubledQuoteEscapes() *LanguageBuilder {
+	b.config.DoubledQuoteEscapes = true
+	return b
+}
+
+// DollarQuotedStrings adds PostgreSQL's dollar-quoted strings, e.g. $$it's$$ or
+// $body$...$body$, which may span lines.
+func (b *LanguageBuilder) DollarQuotedStrings() *LanguageBuilder {
+	b.config.DollarQuotedStrings = true
+	return b
+}
+
+// Parameters sets the runes starting query parameters, e.g. ":$?". See ParameterType.
+func (b *LanguageBuilder) Parameters(runes string) *LanguageBuilder {
+	b.config.Parameters = runes
+	return b
+}
+
+// KeywordCase sets how keywords are matched, e.g. CaseInsensitive.
+func (b *LanguageBuilder) KeywordCase(sensitivity CaseSensitivity) *LanguageBuilder {
+	b.config.KeywordCase = sensitivity
+	return b
+}
+
+// FoldIdentifiers case folds identifiers before they're passed to the token creators.
+func (b *LanguageBuilder) FoldIdentifiers() *LanguageBuilder {
+	b.config.FoldIdentifiers = true
+	return b
+}
+
+// IdentifierFallback makes identifiers that no keyword or token creator matches IdentifierType tokens.
+func (b *LanguageBuilder) IdentifierFallback() *LanguageBuilder {
+	b.config.IdentifierFallback = true
+	return b
+}
+
+// Identifiers sets the predicate deciding which runes are valid in identifiers, replacing the
+// default rules, e.g. to allow sigils such as "$var" or "@attr".
+func (b *LanguageBuilder) Identifiers(valid func(r rune, pos int) bool) *LanguageBuilder {
+	b.config.Identifiers = valid
+	return b
+}
+
+// IdentifierProfile sets the rules deciding which runes are valid in identifiers, e.g.
+// UAX31Identifiers.
+func (b *LanguageBuilder) IdentifierProfile(profile IdentifierProfile) *LanguageBuilder {
+	b.config.IdentifierProfile = profile
+	return b
+}
+
+// NormalizeIdentifiers converts identifiers to Unicode NFC before they're matched.
+func (b *LanguageBuilder) NormalizeIdentifiers() *LanguageBuilder {
+	b.config.NormalizeIdentifiers = true
+	return b
+}
+
+// KeepComments makes comments CommentType tokens, whose Literal is the text between the
+// delimiters and whose Value is the opener. An identifier-shaped opener that's also a
+// keyword, e.g. BASIC's REM, is a keyword token followed by the comment.
+func (b *LanguageBuilder) KeepComments() *LanguageBuilder {
+	b.config.KeepComments = true
+	return b
+}
+
+// LineNumberLabels makes the integer starting a line a LineNumberType token. If ascending,
+// Tokenize fails with ErrLineNumberOrder unless the line numbers ascend.
+func (b *LanguageBuilder) LineNumberLabels(ascending bool) *LanguageBuilder {
+	b.config.LineNumberLabels = true
+	b.config.AscendingLineNumbers = ascending
+	return b
+}
+
+// Mnemonic adds an assembler instruction mnemonic, e.g. "lda".
+func (b *LanguageBuilder) Mnemonic(mnemonic string, id TokenIdentifier) *LanguageBuilder {
+	asm := b.assembler()
+	if asm.Mnemonics == nil {
+		asm.Mnemonics = make(map[string]TokenIdentifier)
+	}
+	asm.Mnemonics[mnemonic] = id
+	return b
+}
+
+// Directive adds an assembler directive with its prefix, e.g. ".byte" or "!org".
+func (b *LanguageBuilder) Directive(directive string, id TokenIdentifier) *LanguageBuilder {
+	asm := b.assembler()
+	if asm.Directives == nil {
+		asm.Directives = make(map[string]TokenIdentifier)
+	}
+	asm.Directives[directive] = id
+	return b
+}
+
+// Register adds an assembler register, e.g. "x" of "lda $10,x".
+func (b *LanguageBuilder) Register(register string, id TokenIdentifier) *LanguageBuilder {
+	asm := b.assembler()
+	if asm.Registers == nil {
+		asm.Registers = make(map[string]TokenIdentifier)
+	}
+	asm.Registers[register] = id
+	return b
+}
+
+// LocalLabels makes identifiers starting with any of the prefixes local labels, e.g. "@".
+func (b *LanguageBuilder) LocalLabels(prefixes string, id TokenIdentifier) *LanguageBuilder {
+	asm := b.assembler()
+	asm.LocalLabelPrefixes, asm.LocalLabel = prefixes, id
+	return b
+}
+
+// AnonymousLabels sets the syntax of anonymous labels, e.g. ACMEAnonymousLabels.
+func (b *LanguageBuilder) AnonymousLabels(style AnonymousLabelStyle, id TokenIdentifier) *LanguageBuilder {
+	asm := b.assembler()
+	asm.AnonymousLabels, asm.AnonymousLabel = style, id
+	return b
+}
+
+// Immediate makes '#' the assembler's immediate prefix.
+func (b *LanguageBuilder) Immediate(id TokenIdentifier) *LanguageBuilder {
+	b.assembler().Immediate = id
+	return b
+}
+
+// assembler returns the language's assembler profile, adding one if it has none.
+func (b *LanguageBuilder) assembler() *AssemblerProfile {
+	if b.config.Assembler == nil {
+		b.config.Assembler = &AssemblerProfile{}
+	}
+	return b.config.Assembler
+}
+
+// TokenCreator adds a token creator, called in order for identifiers that aren't keywords.
+func (b *LanguageBuilder) TokenCreator(creator func(identifier string) Token) *LanguageBuilder {
+	b.config.TokenCreators = append(b.config.TokenCreators, creator)
+	return b
+}
+
+// TokenName names a token identifier, e.g. "LessThanOrEqual", for LanguageConfig.TokenName.
+func (b *LanguageBuilder) TokenName(id TokenIdentifier, name string) *LanguageBuilder {
+	if b.config.TokenNames == nil {
+		b.config.TokenNames = make(map[TokenIdentifier]string)
+	}
+	b.config.TokenNames[id] = name
+	return b
+}
+
+// Build allocates identifiers for the implied symbols and returns the validated configuration.
+// The builder shouldn't be used afterwards.
+func (b *LanguageBuilder) Build() (*LanguageConfig, error) {
+	next := b.nextID()
+	for _, s := range b.implied {
+		if b.config.isIdentifierShaped(s) {
+			continue
+		}
+		for _, r := range s {
+			if _, found := b.config.Symbols[r]; !found {
+				b.config.Symbols[r] = next
+				next++
+			}
+		}
+	}
+
+	config := NewLexerLanguage(b.config)
+	if err := errors.Join(append(b.problems, config.Validate())...); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// MustBuild is like Build but panics if the configuration isn't valid.
+func (b *LanguageBuilder) MustBuild() *LanguageConfig {
+	config, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return config
+}
+
+// nextID returns the first identifier above LastStdLiteral and every explicit identifier.
+func (b *LanguageBuilder) nextID() TokenIdentifier {
+	next := LastStdLiteral
+	for _, ids := range []map[string]TokenIdentifier{b.config.Keywords, b.config.Operators} {
+		for _, id := range ids {
+			if id >= next {
+				next = id + 1
+			}
+		}
+	}
+	for _, id := range b.config.Symbols {
+		if id >= next {
+			next = id + 1
+		}
+	}
+	if asm := b.config.Assembler; asm != nil {
+		for _, ids := range []map[string]TokenIdentifier{asm.Mnemonics, asm.Directives, asm.Registers} {
+			for _, id := range ids {
+				if id >= next {
+					next = id + 1
+				}
+			}
+		}
+		for _, id := range []TokenIdentifier{asm.LocalLabel, asm.AnonymousLabel, asm.Immediate} {
+			if id >= next {
+				next = id + 1
+			}
+		}
+	}
+	return next
+}
@@ -0,0 +1,67 @@
+package lexer_test
+
+import (
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanguageBuilder(t *testing.T) {
+	config, err := lexer.NewLanguage("basic").
+		Keyword("if", IfStatementToken).
+		Keyword("print", PrintStatementToken).
+		Operator("<=", LessThanOrEqualToken).
+		Symbol('+', AddSymbolToken).
+		Symbol('=', EqualsSymbolToken).
+		LineComment("//").
+		LineComment("rem").
+		BlockComment("/*", "*/").
+		HexPrefix("$").
+		BinaryPrefix("%").
+		IdentifierRunes("_").
+		TokenCreator(IntegerVariableTokenCreator).
+		Build()
+	require.NoError(t, err)
+	require.Equal(t, "basic", config.Name)
+
+	// '<' from the operator, then '/' and '*' from the block comment, above the highest explicit identifier
+	require.Equal(t, LessThanOrEqualToken+1, config.Symbols['<'])
+	require.Equal(t, LessThanOrEqualToken+2, config.Symbols['/'])
+	require.Equal(t, LessThanOrEqualToken+3, config.Symbols['*'])
+	require.Equal(t, EqualsSymbolToken, config.Symbols['='])
+	require.NotContains(t, config.Symbols, 'r')
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine("if a_1 <= $ff + %10 print /* c */ // end", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{
+		IfStatementToken, IntegerVariableToken, LessThanOrEqualToken, lexer.HexLiteral, AddSymbolToken,
+		lexer.IntegerLiteral, PrintStatementToken, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+
+	tokens, err = lexer.NewLexer(config).TokenizeLine("rem a comment", "testfile", 0)
+	require.NoError(t, err)
+	require.Empty(t, tokens)
+}
+
+func TestLanguageBuilderAllocatesAboveStandardTokens(t *testing.T) {
+	config := lexer.NewLanguage("comments").BlockComment("(*", "*)").LineComment("#").MustBuild()
+	require.Equal(t, lexer.LastStdLiteral, config.Symbols['('])
+	require.Equal(t, lexer.LastStdLiteral+1, config.Symbols['*'])
+	require.NotContains(t, config.Symbols, '#')
+}
+
+func TestLanguageBuilderErrors(t *testing.T) {
+	_, err := lexer.NewLanguage("broken").
+		Keyword("if", IfStatementToken).
+		Keyword("if", LetStatementToken).
+		Keyword("then", LetStatementToken).
+		Symbol('$', DollarToken).
+		HexPrefix("$").
+		Build()
+	require.ErrorIs(t, err, lexer.ErrConflict)
+	require.ErrorIs(t, err, lexer.ErrDuplicateTokenID)
+	require.ErrorContains(t, err, `keyword "if" is already token 139`)
+
+	require.Panics(t, func() { lexer.NewLanguage("broken").Symbol(' ', AddSymbolToken).MustBuild() })
+}
@@ -1,11 +1,21 @@
 package lexer
 
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultStringDelimiters are the runes that open and close string literals when a
+// LanguageConfig doesn't specify its own.
+const DefaultStringDelimiters = "\"'`"
+
 type completed bool // Used to signal that the current tokenizer is completed
 type TokenizerHandler func(r rune) ([]Token, completed, error)
 type TokenizerFunc func(tf *TokenCreator, initialString string) TokenizerHandler
 
 // LanguageConfig is the struct containing the configurations for the lexer.
 type LanguageConfig struct {
+	Name                    string // Name of the language, e.g. "basic"
 	Keywords                map[string]TokenIdentifier
 	Operators               map[string]TokenIdentifier      // Multi-character operator tokens e.g. "<=", "=="
 	PrefixTokenizers        map[string]TokenizerFunc        // Language-specific tokenizers keyed by their trigger string
@@ -13,7 +23,26 @@ type LanguageConfig struct {
 	Comments                map[string]string               // Comment delimiters: open -> close, e.g. "//" -> "\n"
 	ExtendedIdentifierRunes string                          // Extra runes that are valid inside an identifier name
 	IdentifierTermination   string                          // Runes that end an identifier and are included in it, e.g. ":" for labels
+	StringDelimiters        string                          // Runes that open and close a string literal, DefaultStringDelimiters if empty
+	Whitespace              string                          // Runes that separate tokens besides line endings, any Unicode white space if empty
+	QuotedIdentifiers       map[rune]rune                   // Opening to closing delimiters of quoted identifiers, e.g. '[' -> ']', lexed as IdentifierType
+	DoubledQuoteEscapes     bool                            // Strings escape their delimiter by doubling it, e.g. 'it''s', rather than with backslashes
+	DollarQuotedStrings     bool                            // PostgreSQL's $tag$...$tag$ strings, which may span lines
+	Parameters              string                          // Runes starting ParameterType tokens, e.g. ":$?" for ":name", "$1" and "?"
 	TokenCreators           []func(identifier string) Token // Custom token creators called when no keyword matches
+	TokenNames              map[TokenIdentifier]string      // Names of token identifiers used when formatting tokens, e.g. "LessThanOrEqual"
+	KeywordCase             CaseSensitivity                 // How keywords, and identifier-shaped comment openers, are matched
+	FoldIdentifiers         bool                            // Case fold identifiers before passing them to TokenCreators, see FoldCase
+	IdentifierFallback      bool                            // Emit IdentifierType for identifiers no keyword or TokenCreator matches
+	Identifiers             func(r rune, pos int) bool      // Reports whether r is valid at byte position pos of an identifier, replacing the default rules
+	IdentifierProfile       IdentifierProfile               // Rules deciding which runes are valid in identifiers, e.g. UAX31Identifiers
+	NormalizeIdentifiers    bool                            // Convert identifiers to Unicode NFC before matching keywords and calling TokenCreators
+	KeepComments            bool                            // Emit comments as CommentType tokens rather than dropping them
+	LineNumberLabels        bool                            // Make the integer starting a line a LineNumberType token, e.g. BASIC's "10 PRINT"
+	AscendingLineNumbers    bool                            // With LineNumberLabels, fail Tokenize with ErrLineNumberOrder unless line numbers ascend
+	Assembler               *AssemblerProfile               // Mnemonics, directives, labels and registers of an assembly language, nil for other languages
+
+	compiled *compiledLanguage // Lookup tables built by Compile, nil until compiled
 }
 
 // NewLexerLanguage creates a new LanguageConfig from the provided configuration.
@@ -25,23 +54,59 @@ func NewLexerLanguage(config LanguageConfig) *LanguageConfig {
 // using custom token creators and returns the token if found.
 func (ll *LanguageConfig) tokenFromIdentifier(identifier string) Token {
 	if tokenID, ok := ll.Keywords[identifier]; ok {
-		return NewToken(tokenID, identifier, nil)
+		return newToken(KeywordCategory, tokenID, identifier, nil)
 	}
+	return ll.tokenFromCreators(identifier)
+}
 
+// tokenFromCreators returns the token made by the first TokenCreator that accepts identifier.
+func (ll *LanguageConfig) tokenFromCreators(identifier string) Token {
 	for _, c := range ll.TokenCreators {
 		if t := c(identifier); t.ID != NullType {
+			if t.Category == NoCategory {
+				t.Category = IdentifierCategory
+			}
 			return t
 		}
 	}
+	if ll.IdentifierFallback {
+		return newToken(IdentifierCategory, IdentifierType, identifier, nil)
+	}
 	return Token{}
 }
 
+// isWhitespace reports whether r separates tokens. The newline ending each line always does.
+func (ll *LanguageConfig) isWhitespace(r rune) bool {
+	if ll.Whitespace == "" {
+		return unicode.IsSpace(r)
+	}
+	return r == newLine || strings.ContainsRune(ll.Whitespace, r)
+}
+
+// isStringDelimiter reports whether r opens a string literal.
+func (ll *LanguageConfig) isStringDelimiter(r rune) bool {
+	if ll.StringDelimiters == "" {
+		return strings.ContainsRune(DefaultStringDelimiters, r)
+	}
+	return strings.ContainsRune(ll.StringDelimiters, r)
+}
+
 func (ll *LanguageConfig) IsCustomTokenizer(parsedString string) bool {
+	if ll.compiled != nil {
+		node := ll.compiled.root.find(parsedString)
+		return node != nil && node.tokenizer != nil
+	}
 	_, found := ll.PrefixTokenizers[parsedString]
 	return found
 }
 
 func (ll *LanguageConfig) Tokenizer(parsedString string) TokenizerFunc {
+	if ll.compiled != nil {
+		if node := ll.compiled.root.find(parsedString); node != nil {
+			return node.tokenizer
+		}
+		return nil
+	}
 	tokenizer := ll.PrefixTokenizers[parsedString]
 	return tokenizer
 }
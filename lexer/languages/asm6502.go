@@ -0,0 +1,261 @@
+package languages
+
+import "github.com/jrsteele09/go-lexer/lexer"
+
+// Tokens of 6502 assembly language.
+const (
+	// Mnemonics
+	AsmAdc lexer.TokenIdentifier = iota + lexer.LastStdLiteral
+	AsmAnd
+	AsmAsl
+	AsmBcc
+	AsmBcs
+	AsmBeq
+	AsmBit
+	AsmBmi
+	AsmBne
+	AsmBpl
+	AsmBrk
+	AsmBvc
+	AsmBvs
+	AsmClc
+	AsmCld
+	AsmCli
+	AsmClv
+	AsmCmp
+	AsmCpx
+	AsmCpy
+	AsmDec
+	AsmDex
+	AsmDey
+	AsmEor
+	AsmInc
+	AsmInx
+	AsmIny
+	AsmJmp
+	AsmJsr
+	AsmLda
+	AsmLdx
+	AsmLdy
+	AsmLsr
+	AsmNop
+	AsmOra
+	AsmPha
+	AsmPhp
+	AsmPla
+	AsmPlp
+	AsmRol
+	AsmRor
+	AsmRti
+	AsmRts
+	AsmSbc
+	AsmSec
+	AsmSed
+	AsmSei
+	AsmSta
+	AsmStx
+	AsmSty
+	AsmTax
+	AsmTay
+	AsmTsx
+	AsmTxa
+	AsmTxs
+	AsmTya
+
+	// Directives
+	AsmAddr      // .addr
+	AsmAlign     // .align
+	AsmAsciiz    // .asciiz
+	AsmByte      // .byte
+	AsmDbyt      // .dbyt
+	AsmDefine    // .define
+	AsmElse      // .else
+	AsmElseif    // .elseif
+	AsmEndif     // .endif
+	AsmEndmacro  // .endmacro
+	AsmEndproc   // .endproc
+	AsmEndscope  // .endscope
+	AsmEndstruct // .endstruct
+	AsmExport    // .export
+	AsmExportzp  // .exportzp
+	AsmGlobal    // .global
+	AsmIf        // .if
+	AsmIfdef     // .ifdef
+	AsmIfndef    // .ifndef
+	AsmImport    // .import
+	AsmImportzp  // .importzp
+	AsmIncbin    // .incbin
+	AsmInclude   // .include
+	AsmMacro     // .macro
+	AsmOrg       // .org
+	AsmProc      // .proc
+	AsmRes       // .res
+	AsmScope     // .scope
+	AsmSegment   // .segment
+	AsmStruct    // .struct
+	AsmWord      // .word
+	AsmZeropage  // .zeropage
+
+	AsmA // Accumulator register
+	AsmX // X register
+	AsmY // Y register
+
+	AsmLocalLabel     // @loop
+	AsmAnonymousLabel // :, :+ or :--
+	AsmImmediate      // #
+
+	AsmShiftLeft      // <<
+	AsmShiftRight     // >>
+	AsmLessOrEqual    // <=
+	AsmGreaterOrEqual // >=
+	AsmNotEqual       // <>
+
+	AsmComma      // ,
+	AsmColon      // :
+	AsmLeftParen  // (
+	AsmRightParen // )
+	AsmPlus       // +
+	AsmMinus      // -
+	AsmStar       // *
+	AsmSlash      // /
+	AsmLess       // <, also the low byte of an address
+	AsmGreater    // >, also the high byte of an address
+	AsmEquals     // =
+	AsmAmpersand  // &
+	AsmPipe       // |
+	AsmCaret      // ^
+	AsmTilde      // ~
+)
+
+// Asm6502 returns the configuration of 6502 assembly language in the syntax of ca65.
+// Mnemonics, directives and the registers a, x and y are case-insensitive, labels defined
+// as "name:" are IdentifierType tokens including the ':', local labels start with '@', and
+// anonymous labels follow ca65's ":" and ":+" syntax. Hexadecimal literals start with '$',
+// binary literals with '%', and comments with ';'.
+func Asm6502() *lexer.LanguageConfig {
+	return lexer.NewLanguage("6502").
+		Mnemonic("adc", AsmAdc).
+		Mnemonic("and", AsmAnd).
+		Mnemonic("asl", AsmAsl).
+		Mnemonic("bcc", AsmBcc).
+		Mnemonic("bcs", AsmBcs).
+		Mnemonic("beq", AsmBeq).
+		Mnemonic("bit", AsmBit).
+		Mnemonic("bmi", AsmBmi).
+		Mnemonic("bne", AsmBne).
+		Mnemonic("bpl", AsmBpl).
+		Mnemonic("brk", AsmBrk).
+		Mnemonic("bvc", AsmBvc).
+		Mnemonic("bvs", AsmBvs).
+		Mnemonic("clc", AsmClc).
+		Mnemonic("cld", AsmCld).
+		Mnemonic("cli", AsmCli).
+		Mnemonic("clv", AsmClv).
+		Mnemonic("cmp", AsmCmp).
+		Mnemonic("cpx", AsmCpx).
+		Mnemonic("cpy", AsmCpy).
+		Mnemonic("dec", AsmDec).
+		Mnemonic("dex", AsmDex).
+		Mnemonic("dey", AsmDey).
+		Mnemonic("eor", AsmEor).
+		Mnemonic("inc", AsmInc).
+		Mnemonic("inx", AsmInx).
+		Mnemonic("iny", AsmIny).
+		Mnemonic("jmp", AsmJmp).
+		Mnemonic("jsr", AsmJsr).
+		Mnemonic("lda", AsmLda).
+		Mnemonic("ldx", AsmLdx).
+		Mnemonic("ldy", AsmLdy).
+		Mnemonic("lsr", AsmLsr).
+		Mnemonic("nop", AsmNop).
+		Mnemonic("ora", AsmOra).
+		Mnemonic("pha", AsmPha).
+		Mnemonic("php", AsmPhp).
+		Mnemonic("pla", AsmPla).
+		Mnemonic("plp", AsmPlp).
+		Mnemonic("rol", AsmRol).
+		Mnemonic("ror", AsmRor).
+		Mnemonic("rti", AsmRti).
+		Mnemonic("rts", AsmRts).
+		Mnemonic("sbc", AsmSbc).
+		Mnemonic("sec", AsmSec).
+		Mnemonic("sed", AsmSed).
+		Mnemonic("sei", AsmSei).
+		Mnemonic("sta", AsmSta).
+		Mnemonic("stx", AsmStx).
+		Mnemonic("sty", AsmSty).
+		Mnemonic("tax", AsmTax).
+		Mnemonic("tay", AsmTay).
+		Mnemonic("tsx", AsmTsx).
+		Mnemonic("txa", AsmTxa).
+		Mnemonic("txs", AsmTxs).
+		Mnemonic("tya", AsmTya).
+		Directive(".addr", AsmAddr).
+		Directive(".align", AsmAlign).
+		Directive(".asciiz", AsmAsciiz).
+		Directive(".byte", AsmByte).
+		Directive(".dbyt", AsmDbyt).
+		Directive(".define", AsmDefine).
+		Directive(".else", AsmElse).
+		Directive(".elseif", AsmElseif).
+		Directive(".endif", AsmEndif).
+		Directive(".endmacro", AsmEndmacro).
+		Directive(".endproc", AsmEndproc).
+		Directive(".endscope", AsmEndscope).
+		Directive(".endstruct", AsmEndstruct).
+		Directive(".export", AsmExport).
+		Directive(".exportzp", AsmExportzp).
+		Directive(".global", AsmGlobal).
+		Directive(".if", AsmIf).
+		Directive(".ifdef", AsmIfdef).
+		Directive(".ifndef", AsmIfndef).
+		Directive(".import", AsmImport).
+		Directive(".importzp", AsmImportzp).
+		Directive(".incbin", AsmIncbin).
+		Directive(".include", AsmInclude).
+		Directive(".macro", AsmMacro).
+		Directive(".org", AsmOrg).
+		Directive(".proc", AsmProc).
+		Directive(".res", AsmRes).
+		Directive(".scope", AsmScope).
+		Directive(".segment", AsmSegment).
+		Directive(".struct", AsmStruct).
+		Directive(".word", AsmWord).
+		Directive(".zeropage", AsmZeropage).
+		Register("a", AsmA).
+		Register("x", AsmX).
+		Register("y", AsmY).
+		LocalLabels("@", AsmLocalLabel).
+		AnonymousLabels(lexer.CA65AnonymousLabels, AsmAnonymousLabel).
+		Immediate(AsmImmediate).
+		Operator("<<", AsmShiftLeft).
+		Operator(">>", AsmShiftRight).
+		Operator("<=", AsmLessOrEqual).
+		Operator(">=", AsmGreaterOrEqual).
+		Operator("<>", AsmNotEqual).
+		Symbol(',', AsmComma).
+		Symbol(':', AsmColon).
+		Symbol('(', AsmLeftParen).
+		Symbol(')', AsmRightParen).
+		Symbol('+', AsmPlus).
+		Symbol('-', AsmMinus).
+		Symbol('*', AsmStar).
+		Symbol('/', AsmSlash).
+		Symbol('<', AsmLess).
+		Symbol('>', AsmGreater).
+		Symbol('=', AsmEquals).
+		Symbol('&', AsmAmpersand).
+		Symbol('|', AsmPipe).
+		Symbol('^', AsmCaret).
+		Symbol('~', AsmTilde).
+		LineComment(";").
+		HexPrefix("$").
+		BinaryPrefix("%").
+		KeywordCase(lexer.CaseInsensitive).
+		IdentifierProfile(lexer.ASCIIIdentifiers).
+		IdentifierRunes("_").
+		IdentifierTermination(":").
+		StringDelimiters(`"'`).
+		IdentifierFallback().
+		MustBuild()
+}
@@ -0,0 +1,197 @@
+package languages
+
+import "github.com/jrsteele09/go-lexer/lexer"
+
+// Tokens of Commodore BASIC V2. Keywords and the operators BASIC crunches have the identifier
+// of their token byte, e.g. 0x99 for PRINT, as in prg.CommodoreBasicV2.
+const (
+	BasicEnd     lexer.TokenIdentifier = 0x80
+	BasicFor     lexer.TokenIdentifier = 0x81
+	BasicNext    lexer.TokenIdentifier = 0x82
+	BasicData    lexer.TokenIdentifier = 0x83
+	BasicInput   lexer.TokenIdentifier = 0x85
+	BasicDim     lexer.TokenIdentifier = 0x86
+	BasicRead    lexer.TokenIdentifier = 0x87
+	BasicLet     lexer.TokenIdentifier = 0x88
+	BasicGoto    lexer.TokenIdentifier = 0x89
+	BasicRun     lexer.TokenIdentifier = 0x8A
+	BasicIf      lexer.TokenIdentifier = 0x8B
+	BasicRestore lexer.TokenIdentifier = 0x8C
+	BasicGosub   lexer.TokenIdentifier = 0x8D
+	BasicReturn  lexer.TokenIdentifier = 0x8E
+	BasicRem     lexer.TokenIdentifier = 0x8F
+	BasicStop    lexer.TokenIdentifier = 0x90
+	BasicOn      lexer.TokenIdentifier = 0x91
+	BasicWait    lexer.TokenIdentifier = 0x92
+	BasicLoad    lexer.TokenIdentifier = 0x93
+	BasicSave    lexer.TokenIdentifier = 0x94
+	BasicVerify  lexer.TokenIdentifier = 0x95
+	BasicDef     lexer.TokenIdentifier = 0x96
+	BasicPoke    lexer.TokenIdentifier = 0x97
+	BasicPrint   lexer.TokenIdentifier = 0x99
+	BasicCont    lexer.TokenIdentifier = 0x9A
+	BasicList    lexer.TokenIdentifier = 0x9B
+	BasicClr     lexer.TokenIdentifier = 0x9C
+	BasicCmd     lexer.TokenIdentifier = 0x9D
+	BasicSys     lexer.TokenIdentifier = 0x9E
+	BasicOpen    lexer.TokenIdentifier = 0x9F
+	BasicClose   lexer.TokenIdentifier = 0xA0
+	BasicGet     lexer.TokenIdentifier = 0xA1
+	BasicNew     lexer.TokenIdentifier = 0xA2
+	BasicTab     lexer.TokenIdentifier = 0xA3
+	BasicTo      lexer.TokenIdentifier = 0xA4
+	BasicFn      lexer.TokenIdentifier = 0xA5
+	BasicSpc     lexer.TokenIdentifier = 0xA6
+	BasicThen    lexer.TokenIdentifier = 0xA7
+	BasicNot     lexer.TokenIdentifier = 0xA8
+	BasicStep    lexer.TokenIdentifier = 0xA9
+	BasicPlus    lexer.TokenIdentifier = 0xAA // +
+	BasicMinus   lexer.TokenIdentifier = 0xAB // -
+	BasicStar    lexer.TokenIdentifier = 0xAC // *
+	BasicSlash   lexer.TokenIdentifier = 0xAD // /
+	BasicPower   lexer.TokenIdentifier = 0xAE // ^
+	BasicAnd     lexer.TokenIdentifier = 0xAF
+	BasicOr      lexer.TokenIdentifier = 0xB0
+	BasicGreater lexer.TokenIdentifier = 0xB1 // >
+	BasicEquals  lexer.TokenIdentifier = 0xB2 // =
+	BasicLess    lexer.TokenIdentifier = 0xB3 // <
+	BasicSgn     lexer.TokenIdentifier = 0xB4
+	BasicInt     lexer.TokenIdentifier = 0xB5
+	BasicAbs     lexer.TokenIdentifier = 0xB6
+	BasicUsr     lexer.TokenIdentifier = 0xB7
+	BasicFre     lexer.TokenIdentifier = 0xB8
+	BasicPos     lexer.TokenIdentifier = 0xB9
+	BasicSqr     lexer.TokenIdentifier = 0xBA
+	BasicRnd     lexer.TokenIdentifier = 0xBB
+	BasicLog     lexer.TokenIdentifier = 0xBC
+	BasicExp     lexer.TokenIdentifier = 0xBD
+	BasicCos     lexer.TokenIdentifier = 0xBE
+	BasicSin     lexer.TokenIdentifier = 0xBF
+	BasicTan     lexer.TokenIdentifier = 0xC0
+	BasicAtn     lexer.TokenIdentifier = 0xC1
+	BasicPeek    lexer.TokenIdentifier = 0xC2
+	BasicLen     lexer.TokenIdentifier = 0xC3
+	BasicStr     lexer.TokenIdentifier = 0xC4 // STR$
+	BasicVal     lexer.TokenIdentifier = 0xC5
+	BasicAsc     lexer.TokenIdentifier = 0xC6
+	BasicChr     lexer.TokenIdentifier = 0xC7 // CHR$
+	BasicLeft    lexer.TokenIdentifier = 0xC8 // LEFT$
+	BasicRight   lexer.TokenIdentifier = 0xC9 // RIGHT$
+	BasicMid     lexer.TokenIdentifier = 0xCA // MID$
+	BasicGo      lexer.TokenIdentifier = 0xCB
+)
+
+// Tokens of Commodore BASIC V2 that are stored as text.
+const (
+	BasicLessOrEqual    lexer.TokenIdentifier = iota + 0x100 // <=
+	BasicGreaterOrEqual                                      // >=
+	BasicNotEqual                                            // <>
+	BasicLeftParen                                           // (
+	BasicRightParen                                          // )
+	BasicComma                                               // ,
+	BasicSemicolon                                           // ;
+	BasicColon                                               // :
+	BasicHash                                                // #
+)
+
+// Basic returns the configuration of Commodore BASIC V2, whose programs prg.Encode crunches
+// with prg.CommodoreBasicV2. Keywords are case-insensitive, the number starting a line is a
+// LineNumberType token, REM is a keyword followed by a CommentType token of the rest of the
+// line, and variables are IdentifierType tokens that may end in '$' or '%'. TAB( and SPC(
+// are lexed as the keywords tab and spc followed by '(', and PRINT# and INPUT# as print and
+// input followed by '#'.
+func Basic() *lexer.LanguageConfig {
+	return lexer.NewLanguage("basic").
+		Keyword("end", BasicEnd).
+		Keyword("for", BasicFor).
+		Keyword("next", BasicNext).
+		Keyword("data", BasicData).
+		Keyword("input", BasicInput).
+		Keyword("dim", BasicDim).
+		Keyword("read", BasicRead).
+		Keyword("let", BasicLet).
+		Keyword("goto", BasicGoto).
+		Keyword("run", BasicRun).
+		Keyword("if", BasicIf).
+		Keyword("restore", BasicRestore).
+		Keyword("gosub", BasicGosub).
+		Keyword("return", BasicReturn).
+		Keyword("rem", BasicRem).
+		Keyword("stop", BasicStop).
+		Keyword("on", BasicOn).
+		Keyword("wait", BasicWait).
+		Keyword("load", BasicLoad).
+		Keyword("save", BasicSave).
+		Keyword("verify", BasicVerify).
+		Keyword("def", BasicDef).
+		Keyword("poke", BasicPoke).
+		Keyword("print", BasicPrint).
+		Keyword("cont", BasicCont).
+		Keyword("list", BasicList).
+		Keyword("clr", BasicClr).
+		Keyword("cmd", BasicCmd).
+		Keyword("sys", BasicSys).
+		Keyword("open", BasicOpen).
+		Keyword("close", BasicClose).
+		Keyword("get", BasicGet).
+		Keyword("new", BasicNew).
+		Keyword("tab", BasicTab).
+		Keyword("to", BasicTo).
+		Keyword("fn", BasicFn).
+		Keyword("spc", BasicSpc).
+		Keyword("then", BasicThen).
+		Keyword("not", BasicNot).
+		Keyword("step", BasicStep).
+		Keyword("and", BasicAnd).
+		Keyword("or", BasicOr).
+		Keyword("sgn", BasicSgn).
+		Keyword("int", BasicInt).
+		Keyword("abs", BasicAbs).
+		Keyword("usr", BasicUsr).
+		Keyword("fre", BasicFre).
+		Keyword("pos", BasicPos).
+		Keyword("sqr", BasicSqr).
+		Keyword("rnd", BasicRnd).
+		Keyword("log", BasicLog).
+		Keyword("exp", BasicExp).
+		Keyword("cos", BasicCos).
+		Keyword("sin", BasicSin).
+		Keyword("tan", BasicTan).
+		Keyword("atn", BasicAtn).
+		Keyword("peek", BasicPeek).
+		Keyword("len", BasicLen).
+		Keyword("str$", BasicStr).
+		Keyword("val", BasicVal).
+		Keyword("asc", BasicAsc).
+		Keyword("chr$", BasicChr).
+		Keyword("left$", BasicLeft).
+		Keyword("right$", BasicRight).
+		Keyword("mid$", BasicMid).
+		Keyword("go", BasicGo).
+		Operator("<=", BasicLessOrEqual).
+		Operator(">=", BasicGreaterOrEqual).
+		Operator("<>", BasicNotEqual).
+		Symbol('+', BasicPlus).
+		Symbol('-', BasicMinus).
+		Symbol('*', BasicStar).
+		Symbol('/', BasicSlash).
+		Symbol('^', BasicPower).
+		Symbol('>', BasicGreater).
+		Symbol('=', BasicEquals).
+		Symbol('<', BasicLess).
+		Symbol('(', BasicLeftParen).
+		Symbol(')', BasicRightParen).
+		Symbol(',', BasicComma).
+		Symbol(';', BasicSemicolon).
+		Symbol(':', BasicColon).
+		Symbol('#', BasicHash).
+		LineComment("rem").
+		KeepComments().
+		KeywordCase(lexer.CaseInsensitive).
+		IdentifierProfile(lexer.ASCIIIdentifiers).
+		IdentifierTermination("$%").
+		StringDelimiters(`"`).
+		LineNumberLabels(false).
+		IdentifierFallback().
+		MustBuild()
+}
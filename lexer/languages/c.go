@@ -0,0 +1,192 @@
+package languages
+
+import "github.com/jrsteele09/go-lexer/lexer"
+
+// Tokens of C.
+const (
+	CAuto lexer.TokenIdentifier = iota + lexer.LastStdLiteral
+	CBool
+	CBreak
+	CCase
+	CChar
+	CConst
+	CContinue
+	CDefault
+	CDo
+	CDouble
+	CElse
+	CEnum
+	CExtern
+	CFloat
+	CFor
+	CGoto
+	CIf
+	CInline
+	CInt
+	CLong
+	CRegister
+	CRestrict
+	CReturn
+	CShort
+	CSigned
+	CSizeof
+	CStatic
+	CStruct
+	CSwitch
+	CTypedef
+	CUnion
+	CUnsigned
+	CVoid
+	CVolatile
+	CWhile
+
+	CArrow            // ->
+	CIncrement        // ++
+	CDecrement        // --
+	CShiftLeft        // <<
+	CShiftRight       // >>
+	CLessOrEqual      // <=
+	CGreaterOrEqual   // >=
+	CEqual            // ==
+	CNotEqual         // !=
+	CLogicalAnd       // &&
+	CLogicalOr        // ||
+	CAddAssign        // +=
+	CSubtractAssign   // -=
+	CMultiplyAssign   // *=
+	CDivideAssign     // /=
+	CModuloAssign     // %=
+	CAndAssign        // &=
+	COrAssign         // |=
+	CXorAssign        // ^=
+	CShiftLeftAssign  // <<=
+	CShiftRightAssign // >>=
+	CEllipsis         // ...
+	CTokenPaste       // ##
+
+	CPlus         // +
+	CMinus        // -
+	CStar         // *
+	CSlash        // /
+	CPercent      // %
+	CAssign       // =
+	CLess         // <
+	CGreater      // >
+	CNot          // !
+	CAmpersand    // &
+	CPipe         // |
+	CCaret        // ^
+	CTilde        // ~
+	CQuestion     // ?
+	CColon        // :
+	CSemicolon    // ;
+	CComma        // ,
+	CDot          // .
+	CLeftParen    // (
+	CRightParen   // )
+	CLeftBracket  // [
+	CRightBracket // ]
+	CLeftBrace    // {
+	CRightBrace   // }
+	CHash         // #
+)
+
+// C returns the configuration of C11. Character constants such as 'a' are lexed as
+// StringLiterals, hexadecimal and binary literals start with "0x" and "0b", and identifiers
+// that aren't keywords are IdentifierType tokens. Preprocessor lines are lexed like any
+// other, with '#' and "##" as tokens.
+func C() *lexer.LanguageConfig {
+	return lexer.NewLanguage("c").
+		Keyword("auto", CAuto).
+		Keyword("_Bool", CBool).
+		Keyword("break", CBreak).
+		Keyword("case", CCase).
+		Keyword("char", CChar).
+		Keyword("const", CConst).
+		Keyword("continue", CContinue).
+		Keyword("default", CDefault).
+		Keyword("do", CDo).
+		Keyword("double", CDouble).
+		Keyword("else", CElse).
+		Keyword("enum", CEnum).
+		Keyword("extern", CExtern).
+		Keyword("float", CFloat).
+		Keyword("for", CFor).
+		Keyword("goto", CGoto).
+		Keyword("if", CIf).
+		Keyword("inline", CInline).
+		Keyword("int", CInt).
+		Keyword("long", CLong).
+		Keyword("register", CRegister).
+		Keyword("restrict", CRestrict).
+		Keyword("return", CReturn).
+		Keyword("short", CShort).
+		Keyword("signed", CSigned).
+		Keyword("sizeof", CSizeof).
+		Keyword("static", CStatic).
+		Keyword("struct", CStruct).
+		Keyword("switch", CSwitch).
+		Keyword("typedef", CTypedef).
+		Keyword("union", CUnion).
+		Keyword("unsigned", CUnsigned).
+		Keyword("void", CVoid).
+		Keyword("volatile", CVolatile).
+		Keyword("while", CWhile).
+		Operator("->", CArrow).
+		Operator("++", CIncrement).
+		Operator("--", CDecrement).
+		Operator("<<", CShiftLeft).
+		Operator(">>", CShiftRight).
+		Operator("<=", CLessOrEqual).
+		Operator(">=", CGreaterOrEqual).
+		Operator("==", CEqual).
+		Operator("!=", CNotEqual).
+		Operator("&&", CLogicalAnd).
+		Operator("||", CLogicalOr).
+		Operator("+=", CAddAssign).
+		Operator("-=", CSubtractAssign).
+		Operator("*=", CMultiplyAssign).
+		Operator("/=", CDivideAssign).
+		Operator("%=", CModuloAssign).
+		Operator("&=", CAndAssign).
+		Operator("|=", COrAssign).
+		Operator("^=", CXorAssign).
+		Operator("<<=", CShiftLeftAssign).
+		Operator(">>=", CShiftRightAssign).
+		Operator("...", CEllipsis).
+		Operator("##", CTokenPaste).
+		Symbol('+', CPlus).
+		Symbol('-', CMinus).
+		Symbol('*', CStar).
+		Symbol('/', CSlash).
+		Symbol('%', CPercent).
+		Symbol('=', CAssign).
+		Symbol('<', CLess).
+		Symbol('>', CGreater).
+		Symbol('!', CNot).
+		Symbol('&', CAmpersand).
+		Symbol('|', CPipe).
+		Symbol('^', CCaret).
+		Symbol('~', CTilde).
+		Symbol('?', CQuestion).
+		Symbol(':', CColon).
+		Symbol(';', CSemicolon).
+		Symbol(',', CComma).
+		Symbol('.', CDot).
+		Symbol('(', CLeftParen).
+		Symbol(')', CRightParen).
+		Symbol('[', CLeftBracket).
+		Symbol(']', CRightBracket).
+		Symbol('{', CLeftBrace).
+		Symbol('}', CRightBrace).
+		Symbol('#', CHash).
+		LineComment("//").
+		BlockComment("/*", "*/").
+		HexPrefix("0x").
+		BinaryPrefix("0b").
+		IdentifierProfile(lexer.ASCIIIdentifiers).
+		IdentifierRunes("_").
+		StringDelimiters(`"'`).
+		IdentifierFallback().
+		MustBuild()
+}
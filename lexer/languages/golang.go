@@ -0,0 +1,173 @@
+package languages
+
+import "github.com/jrsteele09/go-lexer/lexer"
+
+// Tokens of Go.
+const (
+	GoBreak lexer.TokenIdentifier = iota + lexer.LastStdLiteral
+	GoCase
+	GoChan
+	GoConst
+	GoContinue
+	GoDefault
+	GoDefer
+	GoElse
+	GoFallthrough
+	GoFor
+	GoFunc
+	GoGo
+	GoGoto
+	GoIf
+	GoImport
+	GoInterface
+	GoMap
+	GoPackage
+	GoRange
+	GoReturn
+	GoSelect
+	GoStruct
+	GoSwitch
+	GoType
+	GoVar
+
+	GoDeclare          // :=
+	GoArrow            // <-
+	GoIncrement        // ++
+	GoDecrement        // --
+	GoShiftLeft        // <<
+	GoShiftRight       // >>
+	GoAndNot           // &^
+	GoLessOrEqual      // <=
+	GoGreaterOrEqual   // >=
+	GoEqual            // ==
+	GoNotEqual         // !=
+	GoLogicalAnd       // &&
+	GoLogicalOr        // ||
+	GoAddAssign        // +=
+	GoSubtractAssign   // -=
+	GoMultiplyAssign   // *=
+	GoDivideAssign     // /=
+	GoModuloAssign     // %=
+	GoAndAssign        // &=
+	GoOrAssign         // |=
+	GoXorAssign        // ^=
+	GoShiftLeftAssign  // <<=
+	GoShiftRightAssign // >>=
+	GoAndNotAssign     // &^=
+	GoEllipsis         // ...
+
+	GoPlus         // +
+	GoMinus        // -
+	GoStar         // *
+	GoSlash        // /
+	GoPercent      // %
+	GoAssign       // =
+	GoLess         // <
+	GoGreater      // >
+	GoNot          // !
+	GoAmpersand    // &
+	GoPipe         // |
+	GoCaret        // ^
+	GoTilde        // ~
+	GoColon        // :
+	GoSemicolon    // ;
+	GoComma        // ,
+	GoDot          // .
+	GoLeftParen    // (
+	GoRightParen   // )
+	GoLeftBracket  // [
+	GoRightBracket // ]
+	GoLeftBrace    // {
+	GoRightBrace   // }
+)
+
+// Go returns the configuration of Go. Rune literals such as 'a' and raw strings are lexed as
+// StringLiterals, hexadecimal and binary literals start with "0x" and "0b", identifiers
+// follow Unicode's UAX #31 with '_', and identifiers that aren't keywords, including the
+// predeclared ones such as int and nil, are IdentifierType tokens. Raw strings must end on
+// the line they start on.
+func Go() *lexer.LanguageConfig {
+	return lexer.NewLanguage("go").
+		Keyword("break", GoBreak).
+		Keyword("case", GoCase).
+		Keyword("chan", GoChan).
+		Keyword("const", GoConst).
+		Keyword("continue", GoContinue).
+		Keyword("default", GoDefault).
+		Keyword("defer", GoDefer).
+		Keyword("else", GoElse).
+		Keyword("fallthrough", GoFallthrough).
+		Keyword("for", GoFor).
+		Keyword("func", GoFunc).
+		Keyword("go", GoGo).
+		Keyword("goto", GoGoto).
+		Keyword("if", GoIf).
+		Keyword("import", GoImport).
+		Keyword("interface", GoInterface).
+		Keyword("map", GoMap).
+		Keyword("package", GoPackage).
+		Keyword("range", GoRange).
+		Keyword("return", GoReturn).
+		Keyword("select", GoSelect).
+		Keyword("struct", GoStruct).
+		Keyword("switch", GoSwitch).
+		Keyword("type", GoType).
+		Keyword("var", GoVar).
+		Operator(":=", GoDeclare).
+		Operator("<-", GoArrow).
+		Operator("++", GoIncrement).
+		Operator("--", GoDecrement).
+		Operator("<<", GoShiftLeft).
+		Operator(">>", GoShiftRight).
+		Operator("&^", GoAndNot).
+		Operator("<=", GoLessOrEqual).
+		Operator(">=", GoGreaterOrEqual).
+		Operator("==", GoEqual).
+		Operator("!=", GoNotEqual).
+		Operator("&&", GoLogicalAnd).
+		Operator("||", GoLogicalOr).
+		Operator("+=", GoAddAssign).
+		Operator("-=", GoSubtractAssign).
+		Operator("*=", GoMultiplyAssign).
+		Operator("/=", GoDivideAssign).
+		Operator("%=", GoModuloAssign).
+		Operator("&=", GoAndAssign).
+		Operator("|=", GoOrAssign).
+		Operator("^=", GoXorAssign).
+		Operator("<<=", GoShiftLeftAssign).
+		Operator(">>=", GoShiftRightAssign).
+		Operator("&^=", GoAndNotAssign).
+		Operator("...", GoEllipsis).
+		Symbol('+', GoPlus).
+		Symbol('-', GoMinus).
+		Symbol('*', GoStar).
+		Symbol('/', GoSlash).
+		Symbol('%', GoPercent).
+		Symbol('=', GoAssign).
+		Symbol('<', GoLess).
+		Symbol('>', GoGreater).
+		Symbol('!', GoNot).
+		Symbol('&', GoAmpersand).
+		Symbol('|', GoPipe).
+		Symbol('^', GoCaret).
+		Symbol('~', GoTilde).
+		Symbol(':', GoColon).
+		Symbol(';', GoSemicolon).
+		Symbol(',', GoComma).
+		Symbol('.', GoDot).
+		Symbol('(', GoLeftParen).
+		Symbol(')', GoRightParen).
+		Symbol('[', GoLeftBracket).
+		Symbol(']', GoRightBracket).
+		Symbol('{', GoLeftBrace).
+		Symbol('}', GoRightBrace).
+		LineComment("//").
+		BlockComment("/*", "*/").
+		HexPrefix("0x").
+		BinaryPrefix("0b").
+		IdentifierProfile(lexer.UAX31Identifiers).
+		IdentifierRunes("_").
+		StringDelimiters("\"'`").
+		IdentifierFallback().
+		MustBuild()
+}
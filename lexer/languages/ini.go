@@ -0,0 +1,38 @@
+package languages
+
+import "github.com/jrsteele09/go-lexer/lexer"
+
+// Tokens of INI files.
+const (
+	INILeftBracket  lexer.TokenIdentifier = iota + lexer.LastStdLiteral // [
+	INIRightBracket                                                     // ]
+	INIEquals                                                           // =
+	INIColon                                                            // :
+	INIComma                                                            // ,
+	INIDot                                                              // .
+	INIMinus                                                            // -
+	INISlash                                                            // /
+)
+
+// INI returns the configuration of INI files, with "[section]" headers, "key = value" and
+// "key: value" entries, and comments starting with ';' or '#'. Keys and unquoted values are
+// lexed as identifiers, numbers and symbols, e.g. "log.level" is the identifiers log and
+// level either side of an INIDot, and quoted values are StringLiterals.
+func INI() *lexer.LanguageConfig {
+	return lexer.NewLanguage("ini").
+		Symbol('[', INILeftBracket).
+		Symbol(']', INIRightBracket).
+		Symbol('=', INIEquals).
+		Symbol(':', INIColon).
+		Symbol(',', INIComma).
+		Symbol('.', INIDot).
+		Symbol('-', INIMinus).
+		Symbol('/', INISlash).
+		LineComment(";").
+		LineComment("#").
+		IdentifierProfile(lexer.ASCIIIdentifiers).
+		IdentifierRunes("_").
+		StringDelimiters(`"'`).
+		IdentifierFallback().
+		MustBuild()
+}
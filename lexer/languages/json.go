@@ -0,0 +1,91 @@
+package languages
+
+import (
+	"math"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+)
+
+// Tokens of JSON and JSON5.
+const (
+	JSONTrue lexer.TokenIdentifier = iota + lexer.LastStdLiteral
+	JSONFalse
+	JSONNull
+
+	JSONLeftBrace    // {
+	JSONRightBrace   // }
+	JSONLeftBracket  // [
+	JSONRightBracket // ]
+	JSONColon        // :
+	JSONComma        // ,
+)
+
+// JSON returns the configuration of strict RFC 8259 JSON, see lexer.JSONStringTokenizer and
+// lexer.JSONNumberTokenizer. Strings are double quoted, numbers include their sign, e.g.
+// "-2.5e3", and only space, tab and line endings separate tokens. Any identifier other than
+// true, false and null is an error. The lexer doesn't check the structure of the text, so
+// "[1,]" is lexed without error.
+func JSON() *lexer.LanguageConfig {
+	b := lexer.NewLanguage("json").
+		Keyword("true", JSONTrue).
+		Keyword("false", JSONFalse).
+		Keyword("null", JSONNull).
+		Symbol('{', JSONLeftBrace).
+		Symbol('}', JSONRightBrace).
+		Symbol('[', JSONLeftBracket).
+		Symbol(']', JSONRightBracket).
+		Symbol(':', JSONColon).
+		Symbol(',', JSONComma).
+		Prefix(`"`, lexer.JSONStringTokenizer).
+		IdentifierProfile(lexer.ASCIIIdentifiers).
+		StringDelimiters(`"`).
+		Whitespace(lexer.JSONWhitespace)
+	for _, r := range "-0123456789" {
+		b.Prefix(string(r), lexer.JSONNumberTokenizer)
+	}
+	return b.MustBuild()
+}
+
+// JSON5 returns the configuration of JSON5, which adds to JSON comments, single quoted
+// strings, hexadecimal numbers, Infinity and NaN, and unquoted object keys, which are
+// IdentifierType tokens. See lexer.JSON5StringTokenizer and lexer.JSON5NumberTokenizer.
+// Trailing commas need nothing of the lexer, but a parser should allow them.
+func JSON5() *lexer.LanguageConfig {
+	b := lexer.NewLanguage("json5").
+		Keyword("true", JSONTrue).
+		Keyword("false", JSONFalse).
+		Keyword("null", JSONNull).
+		Symbol('{', JSONLeftBrace).
+		Symbol('}', JSONRightBrace).
+		Symbol('[', JSONLeftBracket).
+		Symbol(']', JSONRightBracket).
+		Symbol(':', JSONColon).
+		Symbol(',', JSONComma).
+		LineComment("//").
+		BlockComment("/*", "*/").
+		Prefix(`"`, lexer.JSON5StringTokenizer).
+		Prefix("'", lexer.JSON5StringTokenizer).
+		TokenCreator(json5Number).
+		IdentifierProfile(lexer.UAX31Identifiers).
+		IdentifierRunes("_$").
+		StringDelimiters(`"'`).
+		IdentifierFallback()
+	for _, r := range "+-.0123456789" {
+		b.Prefix(string(r), lexer.JSON5NumberTokenizer)
+	}
+	return b.MustBuild()
+}
+
+// json5Number returns the NumberLiteral of an unsigned Infinity or NaN.
+func json5Number(identifier string) lexer.Token {
+	var value float64
+	switch identifier {
+	case "Infinity":
+		value = math.Inf(1)
+	case "NaN":
+		value = math.NaN()
+	default:
+		return lexer.Token{}
+	}
+	return lexer.Token{Category: lexer.LiteralCategory, ID: lexer.NumberLiteral, Literal: identifier, Value: value}
+}
@@ -0,0 +1,245 @@
+package languages_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/jrsteele09/go-lexer/lexer/languages"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON(t *testing.T) {
+	config := languages.JSON()
+	tokens := tokenize(t, config, `{"a": [1, -2.5e3, true, false, null, "é\n"]}`)
+	require.Equal(t, []lexer.TokenIdentifier{
+		languages.JSONLeftBrace, lexer.StringLiteral, languages.JSONColon, languages.JSONLeftBracket,
+		lexer.IntegerLiteral, languages.JSONComma, lexer.NumberLiteral, languages.JSONComma, languages.JSONTrue,
+		languages.JSONComma, languages.JSONFalse, languages.JSONComma, languages.JSONNull, languages.JSONComma,
+		lexer.StringLiteral, languages.JSONRightBracket, languages.JSONRightBrace, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+	require.Equal(t, "a", tokens[1].Value)
+	require.Equal(t, int64(1), tokens[4].Value)
+	require.Equal(t, -2500.0, tokens[6].Value)
+	require.Equal(t, "é\n", tokens[14].Value)
+
+	for _, source := range []string{`{"a": undefined}`, `['a']`, "[\f]", `[01]`, `[1.]`, `["\x41"]`} {
+		_, err := lexer.NewLexer(config).TokenizeLine(source, "test", 1)
+		require.Error(t, err, source)
+	}
+}
+
+func TestJSON5(t *testing.T) {
+	config := languages.JSON5()
+	source := "{ // comment\n  key: 'it\\'s', /* comment */ \"hex\": -0xFF,\n  n: [+.5, 5., Infinity, -Infinity, NaN,],\n}"
+	tokens := tokenize(t, config, source)
+	require.Equal(t, []lexer.TokenIdentifier{
+		languages.JSONLeftBrace, lexer.EndOfLineType,
+		lexer.IdentifierType, languages.JSONColon, lexer.StringLiteral, languages.JSONComma, lexer.StringLiteral,
+		languages.JSONColon, lexer.HexLiteral, languages.JSONComma, lexer.EndOfLineType,
+		lexer.IdentifierType, languages.JSONColon, languages.JSONLeftBracket, lexer.NumberLiteral, languages.JSONComma,
+		lexer.NumberLiteral, languages.JSONComma, lexer.NumberLiteral, languages.JSONComma, lexer.NumberLiteral,
+		languages.JSONComma, lexer.NumberLiteral, languages.JSONComma, languages.JSONRightBracket, languages.JSONComma,
+		lexer.EndOfLineType,
+		languages.JSONRightBrace, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+	require.Equal(t, "it's", tokens[4].Value)
+	require.Equal(t, int64(-255), tokens[8].Value)
+	require.Equal(t, 0.5, tokens[14].Value)
+	require.Equal(t, 5.0, tokens[16].Value)
+	require.Equal(t, math.Inf(1), tokens[18].Value)
+	require.Equal(t, math.Inf(-1), tokens[20].Value)
+	require.True(t, math.IsNaN(tokens[22].Value.(float64)))
+
+	for _, source := range []string{`[01]`, `[++1]`, `[0x]`, `["\1"]`, `['a`} {
+		_, err := lexer.NewLexer(config).TokenizeLine(source, "test", 1)
+		require.Error(t, err, source)
+	}
+}
+
+// TestJSONTestSuite runs the parsing cases of Nicolas Seriot's JSONTestSuite in testdata: y_
+// files must lex and parse to the value encoding/json decodes, n_ files must fail to do one
+// or the other, and i_ files, which parsers may accept or reject, must do as jsonAccepted says.
+func TestJSONTestSuite(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "JSONTestSuite", "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 318)
+
+	for _, file := range files {
+		name := filepath.Base(file)
+		t.Run(name, func(t *testing.T) {
+			source, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			var value any
+			tokens, err := lexer.NewLexer(languages.JSON()).Tokenize(bytes.NewReader(source), name)
+			if err == nil {
+				value, err = parseJSON(tokens)
+			}
+			switch name[:2] {
+			case "y_":
+				require.NoError(t, err)
+				var expected any
+				require.NoError(t, json.Unmarshal(source, &expected))
+				require.Equal(t, expected, value)
+			case "n_":
+				require.Error(t, err)
+			case "i_":
+				accepted, found := jsonAccepted[name]
+				require.True(t, found, "outcome of %s isn't listed", name)
+				require.Equal(t, accepted, err == nil, "%v", err)
+			}
+		})
+	}
+}
+
+// jsonAccepted are the outcomes of the JSONTestSuite cases that parsers may accept or reject.
+var jsonAccepted = map[string]bool{
+	// Numbers out of range are float64s, rounded to an infinity or zero
+	"i_number_double_huge_neg_exp.json":   true,
+	"i_number_huge_exp.json":              true,
+	"i_number_neg_int_huge_exp.json":      true,
+	"i_number_pos_double_huge_exp.json":   true,
+	"i_number_real_neg_overflow.json":     true,
+	"i_number_real_pos_overflow.json":     true,
+	"i_number_real_underflow.json":        true,
+	"i_number_too_big_neg_int.json":       true,
+	"i_number_too_big_pos_int.json":       true,
+	"i_number_very_big_negative_int.json": true,
+
+	// A lone surrogate escape is U+FFFD
+	"i_object_key_lone_2nd_surrogate.json":                true,
+	"i_string_1st_surrogate_but_2nd_missing.json":         true,
+	"i_string_1st_valid_surrogate_2nd_invalid.json":       true,
+	"i_string_incomplete_surrogate_and_escape_valid.json": true,
+	"i_string_incomplete_surrogate_pair.json":             true,
+	"i_string_incomplete_surrogates_escape_valid.json":    true,
+	"i_string_invalid_lonely_surrogate.json":              true,
+	"i_string_invalid_surrogate.json":                     true,
+	"i_string_inverted_surrogates_U+1D11E.json":           true,
+	"i_string_lone_second_surrogate.json":                 true,
+
+	// Input is UTF-8, or UTF-16 with a byte order mark, and invalid UTF-8 is an error
+	"i_string_UTF-16LE_with_BOM.json":              true,
+	"i_structure_UTF-8_BOM_empty_object.json":      true,
+	"i_string_utf16BE_no_BOM.json":                 false,
+	"i_string_utf16LE_no_BOM.json":                 false,
+	"i_string_UTF-8_invalid_sequence.json":         false,
+	"i_string_UTF8_surrogate_U+D800.json":          false,
+	"i_string_invalid_utf-8.json":                  false,
+	"i_string_iso_latin_1.json":                    false,
+	"i_string_lone_utf8_continuation_byte.json":    false,
+	"i_string_not_in_unicode_range.json":           false,
+	"i_string_overlong_sequence_2_bytes.json":      false,
+	"i_string_overlong_sequence_6_bytes.json":      false,
+	"i_string_overlong_sequence_6_bytes_null.json": false,
+	"i_string_truncated-utf-8.json":                false,
+
+	// Nesting isn't limited
+	"i_structure_500_nested_arrays.json": true,
+}
+
+// parseJSON returns the value of tokens, lexed by languages.JSON, decoded as encoding/json
+// decodes into an any: numbers are float64s, arrays []any and objects map[string]any.
+func parseJSON(tokens []lexer.Token) (any, error) {
+	p := jsonParser{}
+	for _, t := range tokens {
+		if t.ID != lexer.EndOfLineType && t.ID != lexer.EOFType {
+			p.tokens = append(p.tokens, t)
+		}
+	}
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s after value", p.tokens[p.pos].Literal)
+	}
+	return value, nil
+}
+
+type jsonParser struct {
+	tokens []lexer.Token
+	pos    int
+}
+
+// next returns the next token, with an EOFType at the end.
+func (p *jsonParser) next() lexer.Token {
+	if p.pos == len(p.tokens) {
+		return lexer.NewToken(lexer.EOFType, "", nil)
+	}
+	p.pos++
+	return p.tokens[p.pos-1]
+}
+
+func (p *jsonParser) expect(id lexer.TokenIdentifier) (lexer.Token, error) {
+	if t := p.next(); t.ID == id {
+		return t, nil
+	}
+	return lexer.Token{}, fmt.Errorf("expected token %d", id)
+}
+
+func (p *jsonParser) value() (any, error) {
+	switch t := p.next(); t.ID {
+	case lexer.StringLiteral:
+		return t.Value, nil
+	case lexer.IntegerLiteral:
+		return float64(t.Value.(int64)), nil
+	case lexer.NumberLiteral:
+		return t.Value, nil
+	case languages.JSONTrue:
+		return true, nil
+	case languages.JSONFalse:
+		return false, nil
+	case languages.JSONNull:
+		return nil, nil
+	case languages.JSONLeftBracket:
+		array := []any{}
+		err := p.members(languages.JSONRightBracket, func() error {
+			element, err := p.value()
+			array = append(array, element)
+			return err
+		})
+		return array, err
+	case languages.JSONLeftBrace:
+		object := map[string]any{}
+		err := p.members(languages.JSONRightBrace, func() error {
+			key, err := p.expect(lexer.StringLiteral)
+			if err != nil {
+				return err
+			}
+			if _, err := p.expect(languages.JSONColon); err != nil {
+				return err
+			}
+			object[key.Value.(string)], err = p.value()
+			return err
+		})
+		return object, err
+	}
+	return nil, fmt.Errorf("expected a value")
+}
+
+// members parses the comma separated members of an array or object up to its closing token.
+func (p *jsonParser) members(closing lexer.TokenIdentifier, member func() error) error {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].ID == closing {
+		p.pos++
+		return nil
+	}
+	for {
+		if err := member(); err != nil {
+			return err
+		}
+		switch p.next().ID {
+		case languages.JSONComma:
+		case closing:
+			return nil
+		default:
+			return fmt.Errorf("expected ',' or the end of the array or object")
+		}
+	}
+}
@@ -0,0 +1,13 @@
+// Package languages provides ready-made language configurations for common languages, to use
+// as they are or as a starting point for a dialect:
+//
+//	config := languages.C()
+//	config.Keywords["_Noreturn"] = MyNoreturn
+//	tokens, err := lexer.NewLexer(config).Tokenize(file, "main.c")
+//
+// Each function returns a new configuration, so changing one doesn't affect another. Every
+// language declares named constants for its keyword, operator and symbol tokens, prefixed
+// with the language's name, e.g. CIf or SQLSelect. Identifiers of the tokens of different
+// languages overlap, so tokens should only be compared with the constants of the language
+// that lexed them.
+package languages
@@ -0,0 +1,164 @@
+package languages_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/jrsteele09/go-lexer/lexer/languages"
+	"github.com/jrsteele09/go-lexer/lexer/prg"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenize lexes source, returning its tokens without the final EOFType.
+func tokenize(t *testing.T, config *lexer.LanguageConfig, source string) []lexer.Token {
+	tokens, err := lexer.NewLexer(config).Tokenize(strings.NewReader(source), "test")
+	require.NoError(t, err)
+	require.Equal(t, lexer.EOFType, tokens[len(tokens)-1].ID)
+	return tokens[:len(tokens)-1]
+}
+
+// tokenIDs returns the identifiers of tokens.
+func tokenIDs(tokens []lexer.Token) []lexer.TokenIdentifier {
+	ids := make([]lexer.TokenIdentifier, len(tokens))
+	for i, token := range tokens {
+		ids[i] = token.ID
+	}
+	return ids
+}
+
+func TestC(t *testing.T) {
+	config := languages.C()
+	source := "while (p->next != 0x0) { n <<= 1; } // shift\n/* c */ char c = 'a'; s.len = 0b11 + 1.5;"
+	require.Equal(t, []lexer.TokenIdentifier{
+		languages.CWhile, languages.CLeftParen, lexer.IdentifierType, languages.CArrow, lexer.IdentifierType,
+		languages.CNotEqual, lexer.HexLiteral, languages.CRightParen, languages.CLeftBrace, lexer.IdentifierType,
+		languages.CShiftLeftAssign, lexer.IntegerLiteral, languages.CSemicolon, languages.CRightBrace, lexer.EndOfLineType,
+		languages.CChar, lexer.IdentifierType, languages.CAssign, lexer.StringLiteral, languages.CSemicolon,
+		lexer.IdentifierType, languages.CDot, lexer.IdentifierType, languages.CAssign, lexer.IntegerLiteral,
+		languages.CPlus, lexer.NumberLiteral, languages.CSemicolon, lexer.EndOfLineType,
+	}, tokenIDs(tokenize(t, config, source)))
+
+	require.Equal(t, []lexer.TokenIdentifier{
+		languages.CHash, lexer.IdentifierType, lexer.IdentifierType, languages.CLeftParen, lexer.IdentifierType,
+		languages.CRightParen, lexer.IdentifierType, languages.CTokenPaste, lexer.IdentifierType, lexer.EndOfLineType,
+	}, tokenIDs(tokenize(t, config, "#define CAT(a) a ## _b")))
+}
+
+func TestGo(t *testing.T) {
+	config := languages.Go()
+	source := "v, ok := <-ch // receive\nmask &^= 0b1010; fmt.Println(`raw`, 'r', π...)"
+	require.Equal(t, []lexer.TokenIdentifier{
+		lexer.IdentifierType, languages.GoComma, lexer.IdentifierType, languages.GoDeclare, languages.GoArrow,
+		lexer.IdentifierType, lexer.EndOfLineType,
+		lexer.IdentifierType, languages.GoAndNotAssign, lexer.IntegerLiteral, languages.GoSemicolon,
+		lexer.IdentifierType, languages.GoDot, lexer.IdentifierType, languages.GoLeftParen, lexer.StringLiteral,
+		languages.GoComma, lexer.StringLiteral, languages.GoComma, lexer.IdentifierType, languages.GoEllipsis,
+		languages.GoRightParen, lexer.EndOfLineType,
+	}, tokenIDs(tokenize(t, config, source)))
+
+	tokens := tokenize(t, config, "func main() { go f() }")
+	require.Equal(t, languages.GoFunc, tokens[0].ID)
+	require.Equal(t, languages.GoGo, tokens[5].ID)
+}
+
+func TestINI(t *testing.T) {
+	config := languages.INI()
+	source := "[server] ; comment\n# comment\nhost = example.com\nport: 8080\nname = \"x y\""
+	tokens := tokenize(t, config, source)
+	require.Equal(t, []lexer.TokenIdentifier{
+		languages.INILeftBracket, lexer.IdentifierType, languages.INIRightBracket, lexer.EndOfLineType,
+		lexer.IdentifierType, languages.INIEquals, lexer.IdentifierType, languages.INIDot, lexer.IdentifierType,
+		lexer.EndOfLineType,
+		lexer.IdentifierType, languages.INIColon, lexer.IntegerLiteral, lexer.EndOfLineType,
+		lexer.IdentifierType, languages.INIEquals, lexer.StringLiteral, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+	require.Equal(t, "x y", tokens[len(tokens)-2].Value)
+}
+
+func TestSQL(t *testing.T) {
+	config := languages.SQL()
+	source := "Select a.b, COUNT(*) from t -- comment\nWHERE x <> 'y' /* comment */ and n >= ?;"
+	require.Equal(t, []lexer.TokenIdentifier{
+		languages.SQLSelect, lexer.IdentifierType, languages.SQLDot, lexer.IdentifierType, languages.SQLComma,
+		lexer.IdentifierType, languages.SQLLeftParen, languages.SQLStar, languages.SQLRightParen, languages.SQLFrom,
+		lexer.IdentifierType, lexer.EndOfLineType,
+		languages.SQLWhere, lexer.IdentifierType, languages.SQLNotEqual, lexer.StringLiteral, languages.SQLAnd,
+		lexer.IdentifierType, languages.SQLGreaterOrEqual, lexer.ParameterType, languages.SQLSemicolon,
+		lexer.EndOfLineType,
+	}, tokenIDs(tokenize(t, config, source)))
+
+	source = "select \"Order\", [group], `key`, id::text from t where n = 'it''s' and m=:m or k = $1\n" +
+		"create function f() returns int as $body$\nselect 1; -- 'x'\n$body$;"
+	tokens := tokenize(t, config, source)
+	require.Equal(t, []lexer.TokenIdentifier{
+		languages.SQLSelect, lexer.IdentifierType, languages.SQLComma, lexer.IdentifierType, languages.SQLComma,
+		lexer.IdentifierType, languages.SQLComma, lexer.IdentifierType, languages.SQLCast, lexer.IdentifierType,
+		languages.SQLFrom, lexer.IdentifierType, languages.SQLWhere, lexer.IdentifierType, languages.SQLEquals,
+		lexer.StringLiteral, languages.SQLAnd, lexer.IdentifierType, languages.SQLEquals, lexer.ParameterType,
+		languages.SQLOr, lexer.IdentifierType, languages.SQLEquals, lexer.ParameterType, lexer.EndOfLineType,
+		languages.SQLCreate, lexer.IdentifierType, lexer.IdentifierType, languages.SQLLeftParen,
+		languages.SQLRightParen, lexer.IdentifierType, lexer.IdentifierType, languages.SQLAs, lexer.EndOfLineType,
+		lexer.StringLiteral, languages.SQLSemicolon, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+	require.Equal(t, []string{"Order", "group", "key"}, []string{tokens[1].Literal, tokens[3].Literal, tokens[5].Literal})
+	require.Equal(t, "it's", tokens[15].Value)
+	require.Equal(t, "m", tokens[19].Value)
+	require.Equal(t, int64(1), tokens[23].Value)
+	require.Equal(t, "\nselect 1; -- 'x'\n", tokens[34].Value)
+}
+
+func TestBasic(t *testing.T) {
+	config := languages.Basic()
+	source := "10 print \"hi\";chr$(65):a$=\"x\" rem say hi\n20 IF A%<=2 THEN GOTO 10\n"
+	tokens := tokenize(t, config, source)
+	require.Equal(t, []lexer.TokenIdentifier{
+		lexer.LineNumberType, languages.BasicPrint, lexer.StringLiteral, languages.BasicSemicolon, languages.BasicChr,
+		languages.BasicLeftParen, lexer.IntegerLiteral, languages.BasicRightParen, languages.BasicColon,
+		lexer.IdentifierType, languages.BasicEquals, lexer.StringLiteral, languages.BasicRem, lexer.CommentType,
+		lexer.EndOfLineType, lexer.LineNumberType, languages.BasicIf, lexer.IdentifierType, languages.BasicLessOrEqual, lexer.IntegerLiteral,
+		languages.BasicThen, languages.BasicGoto, lexer.IntegerLiteral, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+	require.Equal(t, map[int64]int{10: 0, 20: 15}, lexer.LineNumbers(tokens))
+
+	// The keyword identifiers are the dialect's token bytes, with TAB( and SPC( lexed as tab and spc
+	for keyword, id := range config.Keywords {
+		dialectID, found := prg.CommodoreBasicV2.Keywords[strings.ToUpper(keyword)]
+		if !found {
+			dialectID = prg.CommodoreBasicV2.Keywords[strings.ToUpper(keyword)+"("]
+		}
+		require.Equal(t, dialectID, id, keyword)
+	}
+
+	program, err := prg.Encode(tokens, prg.CommodoreBasicV2)
+	require.NoError(t, err)
+	listing, err := prg.List(program, prg.CommodoreBasicV2)
+	require.NoError(t, err)
+	require.Equal(t, "10 PRINT \"HI\";CHR$(65):A$=\"X\" REM SAY HI\n20 IF A%<=2 THEN GOTO 10\n", listing)
+}
+
+func TestAsm6502(t *testing.T) {
+	config := languages.Asm6502()
+	source := ".org $1000\nstart: LDA #%101 ; load\n@loop: sta $10,x\n: bne :-\n  asl a\n  jmp (vector)\n.byte <start, >start"
+	tokens := tokenize(t, config, source)
+	require.Equal(t, []lexer.TokenIdentifier{
+		languages.AsmOrg, lexer.HexLiteral, lexer.EndOfLineType,
+		lexer.IdentifierType, languages.AsmLda, languages.AsmImmediate, lexer.IntegerLiteral, lexer.EndOfLineType,
+		languages.AsmLocalLabel, languages.AsmSta, lexer.HexLiteral, languages.AsmComma, languages.AsmX,
+		lexer.EndOfLineType,
+		languages.AsmAnonymousLabel, languages.AsmBne, languages.AsmAnonymousLabel, lexer.EndOfLineType,
+		languages.AsmAsl, languages.AsmA, lexer.EndOfLineType,
+		languages.AsmJmp, languages.AsmLeftParen, lexer.IdentifierType, languages.AsmRightParen, lexer.EndOfLineType,
+		languages.AsmByte, languages.AsmLess, lexer.IdentifierType, languages.AsmComma, languages.AsmGreater,
+		lexer.IdentifierType, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+	require.Equal(t, "start:", tokens[3].Literal)
+	require.Equal(t, lexer.MnemonicCategory, tokens[4].Category)
+	require.Equal(t, -1, tokens[16].Value)
+}
+
+func TestLanguagesAreIndependent(t *testing.T) {
+	config := languages.C()
+	config.Keywords["_Noreturn"] = languages.CHash + 1
+	require.NotContains(t, languages.C().Keywords, "_Noreturn")
+}
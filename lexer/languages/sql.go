@@ -0,0 +1,206 @@
+package languages
+
+import "github.com/jrsteele09/go-lexer/lexer"
+
+// Tokens of SQL.
+const (
+	SQLAdd lexer.TokenIdentifier = iota + lexer.LastStdLiteral
+	SQLAll
+	SQLAlter
+	SQLAnd
+	SQLAs
+	SQLAsc
+	SQLBegin
+	SQLBetween
+	SQLBy
+	SQLCase
+	SQLCheck
+	SQLColumn
+	SQLCommit
+	SQLConstraint
+	SQLCreate
+	SQLCross
+	SQLDefault
+	SQLDelete
+	SQLDesc
+	SQLDistinct
+	SQLDrop
+	SQLElse
+	SQLEnd
+	SQLExists
+	SQLFalse
+	SQLForeign
+	SQLFrom
+	SQLFull
+	SQLGroup
+	SQLHaving
+	SQLIn
+	SQLIndex
+	SQLInner
+	SQLInsert
+	SQLInto
+	SQLIs
+	SQLJoin
+	SQLKey
+	SQLLeft
+	SQLLike
+	SQLLimit
+	SQLNot
+	SQLNull
+	SQLOffset
+	SQLOn
+	SQLOr
+	SQLOrder
+	SQLOuter
+	SQLPrimary
+	SQLReferences
+	SQLRight
+	SQLRollback
+	SQLSelect
+	SQLSet
+	SQLTable
+	SQLThen
+	SQLTransaction
+	SQLTrue
+	SQLUnion
+	SQLUnique
+	SQLUpdate
+	SQLValues
+	SQLView
+	SQLWhen
+	SQLWhere
+	SQLWith
+
+	SQLLessOrEqual    // <=
+	SQLGreaterOrEqual // >=
+	SQLNotEqual       // <>
+	SQLBangEqual      // !=
+	SQLConcat         // ||
+	SQLCast           // ::
+
+	SQLPlus       // +
+	SQLMinus      // -
+	SQLStar       // *
+	SQLSlash      // /
+	SQLPercent    // %
+	SQLEquals     // =
+	SQLLess       // <
+	SQLGreater    // >
+	SQLLeftParen  // (
+	SQLRightParen // )
+	SQLComma      // ,
+	SQLDot        // .
+	SQLSemicolon  // ;
+	SQLColon      // :
+)
+
+// SQL returns the configuration of standard SQL and its common dialects. Keywords are
+// case-insensitive, comments start with "--" or are enclosed in "/*" and "*/", and identifiers
+// that aren't keywords, including function and type names, are IdentifierType tokens.
+// Identifiers may be quoted as "x", [x] or `x`, which gives IdentifierType tokens whatever
+// their name. Strings are single quoted, a doubled quote standing for one, or PostgreSQL's
+// dollar-quoted $tag$...$tag$, which may span lines. ":name", "$1" and "?" are ParameterType
+// tokens, while "::" is PostgreSQL's cast.
+func SQL() *lexer.LanguageConfig {
+	return lexer.NewLanguage("sql").
+		Keyword("add", SQLAdd).
+		Keyword("all", SQLAll).
+		Keyword("alter", SQLAlter).
+		Keyword("and", SQLAnd).
+		Keyword("as", SQLAs).
+		Keyword("asc", SQLAsc).
+		Keyword("begin", SQLBegin).
+		Keyword("between", SQLBetween).
+		Keyword("by", SQLBy).
+		Keyword("case", SQLCase).
+		Keyword("check", SQLCheck).
+		Keyword("column", SQLColumn).
+		Keyword("commit", SQLCommit).
+		Keyword("constraint", SQLConstraint).
+		Keyword("create", SQLCreate).
+		Keyword("cross", SQLCross).
+		Keyword("default", SQLDefault).
+		Keyword("delete", SQLDelete).
+		Keyword("desc", SQLDesc).
+		Keyword("distinct", SQLDistinct).
+		Keyword("drop", SQLDrop).
+		Keyword("else", SQLElse).
+		Keyword("end", SQLEnd).
+		Keyword("exists", SQLExists).
+		Keyword("false", SQLFalse).
+		Keyword("foreign", SQLForeign).
+		Keyword("from", SQLFrom).
+		Keyword("full", SQLFull).
+		Keyword("group", SQLGroup).
+		Keyword("having", SQLHaving).
+		Keyword("in", SQLIn).
+		Keyword("index", SQLIndex).
+		Keyword("inner", SQLInner).
+		Keyword("insert", SQLInsert).
+		Keyword("into", SQLInto).
+		Keyword("is", SQLIs).
+		Keyword("join", SQLJoin).
+		Keyword("key", SQLKey).
+		Keyword("left", SQLLeft).
+		Keyword("like", SQLLike).
+		Keyword("limit", SQLLimit).
+		Keyword("not", SQLNot).
+		Keyword("null", SQLNull).
+		Keyword("offset", SQLOffset).
+		Keyword("on", SQLOn).
+		Keyword("or", SQLOr).
+		Keyword("order", SQLOrder).
+		Keyword("outer", SQLOuter).
+		Keyword("primary", SQLPrimary).
+		Keyword("references", SQLReferences).
+		Keyword("right", SQLRight).
+		Keyword("rollback", SQLRollback).
+		Keyword("select", SQLSelect).
+		Keyword("set", SQLSet).
+		Keyword("table", SQLTable).
+		Keyword("then", SQLThen).
+		Keyword("transaction", SQLTransaction).
+		Keyword("true", SQLTrue).
+		Keyword("union", SQLUnion).
+		Keyword("unique", SQLUnique).
+		Keyword("update", SQLUpdate).
+		Keyword("values", SQLValues).
+		Keyword("view", SQLView).
+		Keyword("when", SQLWhen).
+		Keyword("where", SQLWhere).
+		Keyword("with", SQLWith).
+		Operator("<=", SQLLessOrEqual).
+		Operator(">=", SQLGreaterOrEqual).
+		Operator("<>", SQLNotEqual).
+		Operator("!=", SQLBangEqual).
+		Operator("||", SQLConcat).
+		Operator("::", SQLCast).
+		Symbol('+', SQLPlus).
+		Symbol('-', SQLMinus).
+		Symbol('*', SQLStar).
+		Symbol('/', SQLSlash).
+		Symbol('%', SQLPercent).
+		Symbol('=', SQLEquals).
+		Symbol('<', SQLLess).
+		Symbol('>', SQLGreater).
+		Symbol('(', SQLLeftParen).
+		Symbol(')', SQLRightParen).
+		Symbol(',', SQLComma).
+		Symbol('.', SQLDot).
+		Symbol(';', SQLSemicolon).
+		Symbol(':', SQLColon).
+		LineComment("--").
+		BlockComment("/*", "*/").
+		KeywordCase(lexer.CaseInsensitive).
+		IdentifierProfile(lexer.ASCIIIdentifiers).
+		IdentifierRunes("_").
+		StringDelimiters("'").
+		DoubledQuoteEscapes().
+		DollarQuotedStrings().
+		QuotedIdentifier('"', '"').
+		QuotedIdentifier('[', ']').
+		QuotedIdentifier('`', '`').
+		Parameters(":$?").
+		IdentifierFallback().
+		MustBuild()
+}
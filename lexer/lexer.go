@@ -1,60 +1,162 @@
 package lexer
 
 import (
-	"bufio"
 	"fmt"
 	"io"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/jrsteele09/go-lexer/lexer/comments"
 	"github.com/pkg/errors"
+	"golang.org/x/text/encoding"
 )
 
 const (
 	newLine = '\n'
+
+	// tokenChunkSize is the number of tokens Tokenize collects before starting a new chunk
+	tokenChunkSize = 4096
 )
 
 // Lexer performs lexical analysis on a stream of input.
 type Lexer struct {
 	language      *LanguageConfig
 	commentParser *comments.CommentParser
+	tokenCreator  *TokenCreator
+
+	securityChecks   SecurityCheck     // Checks enabled by WithSecurityChecks
+	warningsAsErrors bool              // Fail the lex on the first warning
+	warnings         []Warning         // Findings of the security checks
+	maxLineLength    int               // Maximum length of lines read by Tokenize, no limit if 0
+	maxTokenLength   int               // Maximum length of token literals, no limit if 0
+	charset          encoding.Encoding // Character set of the input of Tokenize, UTF-8 if nil
+	lineNumber       int64             // Last line number of the current Tokenize, for AscendingLineNumbers
+	hasLineNumber    bool              // Whether the current Tokenize has had a line number
 }
 
-// NewLexer initializes a new Lexer with the given language configuration.
-func NewLexer(language *LanguageConfig) *Lexer {
-	return &Lexer{
+// Option configures a Lexer.
+type Option func(l *Lexer)
+
+// NewLexer initializes a new Lexer with the given language configuration and options.
+func NewLexer(language *LanguageConfig, opts ...Option) *Lexer {
+	commentParser := comments.NewCommentParser(language.Comments)
+	l := &Lexer{
 		language:      language,
-		commentParser: comments.NewCommentParser(language.Comments),
+		commentParser: commentParser,
+		tokenCreator:  NewTokenCreator(commentParser, language),
+	}
+	for _, opt := range opts {
+		opt(l)
 	}
+	return l
 }
 
 // Tokenize reads from an io.Reader line by line, tokenizes each line using TokenizeLine,
-// and returns all the generated tokens.
+// and returns all the generated tokens. Lines may be of any length. The input is UTF-8, or UTF-16 with a byte order
+// mark, and its lines may end with "\n", "\r\n" or "\r". Each line's end of line token
+// has the line ending as its Literal.
 func (l *Lexer) Tokenize(r io.Reader, filename string) ([]Token, error) {
-	var allTokens []Token
-	scanner := bufio.NewScanner(r)
+	// Tokens are collected in chunks and joined at the end, rather than repeatedly
+	// growing (and copying) a single slice for large inputs.
+	var chunks [][]Token
+	tokens := make([]Token, 0, tokenChunkSize)
+	lines := newLineReader(l.decodeInput(r), l.maxLineLength)
 	lineNo := uint(1)
+	l.warnings = nil
+	l.hasLineNumber = false
+	l.tokenCreator.spansLines = false
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		tokens, err := l.TokenizeLine(line, filename, lineNo)
+	for {
+		line, ending, err := lines.readLine()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("[%s line: %d] %w", filename, lineNo, err)
+		}
+
+		lineStart := len(tokens)
+		tokens, err = l.appendLineTokens(tokens, line, ending, filename, lineNo)
 		if err != nil {
 			return nil, fmt.Errorf("[%s line: %d] %w", filename, lineNo, err)
 		}
-		allTokens = append(allTokens, tokens...)
+		if l.language.AscendingLineNumbers {
+			if t, found := lineNumberToken(tokens[lineStart:]); found {
+				if err := l.checkLineNumberOrder(t); err != nil {
+					return nil, fmt.Errorf("[%s line: %d] %w", filename, lineNo, err)
+				}
+			}
+		}
+		if len(tokens) >= tokenChunkSize*3/4 {
+			chunks = append(chunks, tokens)
+			tokens = make([]Token, 0, tokenChunkSize)
+		}
 		lineNo++
 	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
+	if l.tokenCreator.spansLines {
+		return nil, fmt.Errorf("[%s line: %d] unterminated dollar-quoted string", filename, lineNo-1)
 	}
 
-	allTokens = append(allTokens, NewToken(EOFType, "", nil))
-	return allTokens, nil
+	tokens = append(tokens, newToken(TriviaCategory, EOFType, "", nil))
+	return joinTokenChunks(append(chunks, tokens)), nil
+}
+
+// joinTokenChunks concatenates chunks into a single slice of the exact size required.
+func joinTokenChunks(chunks [][]Token) []Token {
+	if len(chunks) == 1 {
+		return chunks[0]
+	}
+	total := 0
+	for _, chunk := range chunks {
+		total += len(chunk)
+	}
+	allTokens := make([]Token, 0, total)
+	for _, chunk := range chunks {
+		allTokens = append(allTokens, chunk...)
+	}
+	return allTokens
 }
 
 // TokenizeLine tokenizes a single line of input and returns an array of tokens.
 func (l *Lexer) TokenizeLine(line string, filename string, lineNo uint) ([]Token, error) {
-	var lineTokens []Token
+	return l.appendLineTokens(nil, line, string(newLine), filename, lineNo)
+}
+
+// appendLineTokens tokenizes a single line ending with ending, appending its tokens to
+// lineTokens, and runs the security checks on it.
+func (l *Lexer) appendLineTokens(lineTokens []Token, line string, ending string, filename string, lineNo uint) ([]Token, error) {
+	if err := validateUTF8(line); err != nil {
+		return nil, err
+	}
+	if l.securityChecks == 0 && l.maxTokenLength == 0 {
+		return l.lexLine(lineTokens, line, ending, filename, lineNo)
+	}
+
+	if err := l.checkLine(line, filename, lineNo); err != nil {
+		return nil, err
+	}
+	lineStart := len(lineTokens)
+	lineTokens, err := l.lexLine(lineTokens, line, ending, filename, lineNo)
+	if err != nil {
+		return nil, err
+	}
+	if l.maxTokenLength > 0 {
+		if err := l.checkTokenLengths(lineTokens[lineStart:]); err != nil {
+			return nil, err
+		}
+	}
+	if l.securityChecks != 0 {
+		for _, token := range lineTokens[lineStart:] {
+			if err := l.checkIdentifier(token); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return lineTokens, nil
+}
+
+// lexLine tokenizes a single line, appending its tokens to lineTokens.
+func (l *Lexer) lexLine(lineTokens []Token, line string, ending string, filename string, lineNo uint) ([]Token, error) {
+	lineStart := len(lineTokens)
 
 	addNewTokens := func(column int, tokens []Token) {
 		if tokens == nil {
@@ -64,29 +166,59 @@ func (l *Lexer) TokenizeLine(line string, filename string, lineNo uint) ([]Token
 			token.SourceLine = lineNo
 			token.Filename = filename
 			token.SourceColumn = uint(column)
+			if token.Category == NoCategory {
+				token.Category = CustomCategory
+			}
 			lineTokens = append(lineTokens, token)
 		}
 	}
 
 	addEndOfLine := func() {
-		if len(lineTokens) != 0 {
-			addNewTokens(len(line), []Token{NewToken(EndOfLineType, string(newLine), nil)})
+		if len(lineTokens) != lineStart {
+			addNewTokens(len(line), []Token{newToken(TriviaCategory, EndOfLineType, ending, nil)})
 		}
 	}
 
-	tokenFactory := NewTokenCreator(l.commentParser, l.language)
+	// The text of the comment being parsed starts at commentFrom, which is 0 for a block
+	// comment continued from the previous line
+	commentFrom := 0
+	opener, closer := l.commentParser.Delimiters()
+	keepComment := func(textEnd, column int) {
+		if l.language.KeepComments {
+			addNewTokens(column, []Token{newToken(CommentCategory, CommentType, line[commentFrom:textEnd], opener)})
+		}
+	}
+	// startComment finds the text of a comment opened by the runes before end, passing
+	// those of them the tokenizers took in after the opener on to the comment parser
+	startComment := func(end int) {
+		opener, closer = l.commentParser.Delimiters()
+		commentFrom = commentStart(line, end, opener)
+		for i, r := range line[commentFrom:end] {
+			if l.commentParser.ParseEndOfComment(r) {
+				closed := commentFrom + i + utf8.RuneLen(r)
+				keepComment(closed-len(closer), closed)
+				return
+			}
+		}
+	}
+
+	tokenFactory := l.tokenCreator
+	tokenFactory.reset()
 
 	for i, r := range line {
+		end := i + utf8.RuneLen(r)
 		if l.commentParser.InComment() {
 			if l.commentParser.IsNewLineComment() {
 				break
 			}
-			l.commentParser.ParseEndOfComment(r)
+			if l.commentParser.ParseEndOfComment(r) {
+				keepComment(end-len(closer), end)
+			}
 			continue
 		}
 
 		for {
-			if tokens, err := tokenFactory.Tokenize(r); err != nil {
+			if tokens, err := tokenFactory.tokenize(r); err != nil {
 				return nil, errors.Wrap(err, "Lexer.TokenizeLine.tokenFactory.Tokenizer")
 			} else if len(tokens) > 0 {
 				addNewTokens(i, tokens)
@@ -96,24 +228,43 @@ func (l *Lexer) TokenizeLine(line string, filename string, lineNo uint) ([]Token
 			}
 			r = tokenFactory.OverflowRune()
 		}
+		if l.commentParser.InComment() {
+			startComment(end)
+		}
+	}
+
+	if !l.commentParser.InComment() {
+		// Need to complete the tokenization process for the last rune,
+		// It could be that a tokenizer was in progress when a newline was reached
+		token, err := tokenFactory.tokenize(newLine)
+		if err != nil {
+			return nil, errors.Wrap(err, "Lexer.TokenizeLine.tokenFactory.Tokenizer")
+		}
+		addNewTokens(len(line), token)
+		if l.commentParser.InComment() { // An opener ending the line, e.g. "rem"
+			startComment(len(line))
+		}
 	}
 
 	if l.commentParser.InComment() {
+		keepComment(len(line), len(line))
 		if l.commentParser.IsNewLineComment() {
 			l.commentParser.Reset()
 		}
-		addEndOfLine()
-		return lineTokens, nil
-	}
-
-	// Need to complete the tokenization process for the last rune,
-	// It could be that a tokenizer was in progress when a newline was reached
-	token, err := tokenFactory.Tokenize(newLine)
-	if err != nil {
-		return nil, errors.Wrap(err, "Lexer.TokenizeLine.tokenFactory.Tokenizer")
 	}
-
-	addNewTokens(len(line), token)
 	addEndOfLine()
+	l.labelLineNumber(lineTokens[lineStart:])
 	return lineTokens, nil
 }
+
+// commentStart returns where the text of a comment opened by open starts in line: after
+// the last opener ending at or before end. Openers are matched regardless of case, as
+// identifier-shaped ones may be.
+func commentStart(line string, end int, open string) int {
+	for e := end; e >= len(open); e-- {
+		if strings.EqualFold(line[e-len(open):e], open) {
+			return e
+		}
+	}
+	return end
+}
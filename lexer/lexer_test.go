@@ -230,6 +230,69 @@ func TestCrossLineComments(t *testing.T) {
 	require.Equal(t, 5, len(allTokens)) // Includes two endOfLine tokens + endOfFile token
 }
 
+// TestCommentDelimiters tests comments whose opener ends a line or is straight after their closer
+func TestCommentDelimiters(t *testing.T) {
+	for source, want := range map[string]int{
+		"abc //\ndef":     5,
+		"abc rem\ndef":    5,
+		"abc /**/ def":    4,
+		"abc /***/ def":   4,
+		"abc /* */\ndef":  5,
+		"abc ;\ndef ; x":  5,
+		"abc /*\n*/ def":  5,
+		"abc /*\n\n*/def": 5,
+	} {
+		tokens, err := NewBasicLexer().Tokenize(strings.NewReader(source), "testfile")
+		require.NoError(t, err, source)
+		require.Len(t, tokens, want, source)
+	}
+}
+
+// TestKeepComments tests that comments are tokens of their text when a language keeps them
+func TestKeepComments(t *testing.T) {
+	const remToken lexer.TokenIdentifier = 0x8F
+	config := NewBasicLanguage()
+	config.KeepComments = true
+	config.Keywords = map[string]lexer.TokenIdentifier{"rem": remToken}
+	l := lexer.NewLexer(config)
+
+	tokens, err := l.Tokenize(strings.NewReader("abc // one\n/* two\nthree */ def;four\nrem five"), "testfile")
+	require.NoError(t, err)
+	type comment struct {
+		id      lexer.TokenIdentifier
+		literal string
+		value   any
+		column  uint
+	}
+	var got []comment
+	for _, token := range tokens {
+		got = append(got, comment{token.ID, token.Literal, token.Value, token.SourceColumn})
+	}
+	require.Equal(t, []comment{
+		{IntegerVariableToken, "abc", nil, 3},
+		{lexer.CommentType, " one", "//", 10},
+		{lexer.EndOfLineType, "\n", nil, 10},
+		{lexer.CommentType, " two", "/*", 6},
+		{lexer.EndOfLineType, "\n", nil, 6},
+		{lexer.CommentType, "three ", "/*", 8},
+		{IntegerVariableToken, "def", nil, 12},
+		{lexer.CommentType, "four", ";", 17},
+		{lexer.EndOfLineType, "\n", nil, 17},
+		{remToken, "rem", nil, 3},
+		{lexer.CommentType, " five", "rem", 8},
+		{lexer.EndOfLineType, "\n", nil, 8},
+		{lexer.EOFType, "", nil, 0},
+	}, got)
+	require.Equal(t, lexer.CommentCategory, tokens[1].Category)
+	require.Equal(t, []lexer.TokenIdentifier{lexer.CommentType}, config.TokenSet(lexer.CommentCategory).IDs())
+
+	// An opener ending the line is an empty comment
+	tokens, err = l.TokenizeLine("abc //", "testfile", 1)
+	require.NoError(t, err)
+	require.Equal(t, lexer.CommentType, tokens[1].ID)
+	require.Equal(t, "", tokens[1].Literal)
+}
+
 // TestFloatLexer tests tokenization of floating point numbers
 func TestFloatLexer(t *testing.T) {
 	l := NewBasicLexer()
@@ -477,7 +540,12 @@ func TestAVerySimpleIdentifier(t *testing.T) {
 
 // NewBasicLexer constructs a new Lexer using predefined language settings
 func NewBasicLexer() *lexer.Lexer {
-	ll := lexer.NewLexerLanguage(lexer.LanguageConfig{
+	return lexer.NewLexer(NewBasicLanguage())
+}
+
+// NewBasicLanguage constructs the predefined language settings used by NewBasicLexer
+func NewBasicLanguage() *lexer.LanguageConfig {
+	return lexer.NewLexerLanguage(lexer.LanguageConfig{
 		Keywords:                KeywordTokens,
 		Operators:               OperatorTokens,
 		Symbols:                 SymbolTokens,
@@ -490,7 +558,6 @@ func NewBasicLexer() *lexer.Lexer {
 			IntegerVariableTokenCreator,
 			BasicLangstringVariableTokenCreator},
 	})
-	return lexer.NewLexer(ll)
 }
 
 func IntegerVariableTokenCreator(identifier string) lexer.Token {
@@ -0,0 +1,55 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLineNumberOrder is returned by Tokenize for a line number that doesn't follow the line
+// number before it, when the language has AscendingLineNumbers.
+var ErrLineNumberOrder = errors.New("line numbers out of order")
+
+// labelLineNumber makes the first token of a line a LineNumberType token if the language has
+// LineNumberLabels and the token is a decimal integer.
+func (l *Lexer) labelLineNumber(lineTokens []Token) {
+	if !l.language.LineNumberLabels || len(lineTokens) == 0 || lineTokens[0].ID != IntegerLiteral {
+		return
+	}
+	if _, decimal := lineTokens[0].Value.(int64); decimal {
+		lineTokens[0].ID = LineNumberType
+	}
+}
+
+// lineNumberToken returns the LineNumberType token starting a line, if there is one.
+func lineNumberToken(lineTokens []Token) (Token, bool) {
+	if len(lineTokens) == 0 || lineTokens[0].ID != LineNumberType {
+		return Token{}, false
+	}
+	return lineTokens[0], true
+}
+
+// checkLineNumberOrder returns an ErrLineNumberOrder error if a line number doesn't ascend
+// from the previous one.
+func (l *Lexer) checkLineNumberOrder(t Token) error {
+	number := t.Value.(int64)
+	if l.hasLineNumber && number <= l.lineNumber {
+		return fmt.Errorf("%w: %d follows %d", ErrLineNumberOrder, number, l.lineNumber)
+	}
+	l.lineNumber, l.hasLineNumber = number, true
+	return nil
+}
+
+// LineNumbers maps the line numbers of LineNumberType tokens to their indexes in tokens, for
+// resolving the targets of statements such as GOTO and GOSUB. If a line number is repeated,
+// its last line is used, as when a line is typed again in BASIC.
+func LineNumbers(tokens []Token) map[int64]int {
+	lines := map[int64]int{}
+	for i, t := range tokens {
+		if t.ID == LineNumberType {
+			if number, ok := t.Value.(int64); ok {
+				lines[number] = i
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,46 @@
+package lexer_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+func newLineNumberedLanguage(ascending bool) *lexer.LanguageConfig {
+	return lexer.NewLanguage("basic").
+		Keyword("goto", lexer.LastStdLiteral).
+		Keyword("print", lexer.LastStdLiteral+1).
+		LineNumberLabels(ascending).
+		MustBuild()
+}
+
+func TestLineNumberLabels(t *testing.T) {
+	source := "10 print 20\n\n20 goto 10\nprint 30\n20 print\n"
+	tokens, err := lexer.NewLexer(newLineNumberedLanguage(false)).Tokenize(strings.NewReader(source), "program.bas")
+	require.NoError(t, err)
+
+	require.Equal(t, lexer.LineNumberType, tokens[0].ID)
+	require.Equal(t, int64(10), tokens[0].Value)
+	require.Equal(t, lexer.LiteralCategory, tokens[0].Category)
+	require.Equal(t, lexer.IntegerLiteral, tokens[2].ID)
+	require.Equal(t, lexer.IntegerLiteral, tokens[9].ID) // Only a line's first token is a line number
+
+	require.Equal(t, map[int64]int{10: 0, 20: 11}, lexer.LineNumbers(tokens))
+}
+
+func TestAscendingLineNumbers(t *testing.T) {
+	l := lexer.NewLexer(newLineNumberedLanguage(true))
+	_, err := l.Tokenize(strings.NewReader("10 print\n20 print\nprint\n"), "program.bas")
+	require.NoError(t, err)
+
+	_, err = l.Tokenize(strings.NewReader("10 print\n20 print\n20 print\n"), "program.bas")
+	require.True(t, errors.Is(err, lexer.ErrLineNumberOrder))
+	require.EqualError(t, err, "[program.bas line: 3] line numbers out of order: 20 follows 20")
+
+	// Each Tokenize starts a new program
+	_, err = l.Tokenize(strings.NewReader("5 print\n"), "program.bas")
+	require.NoError(t, err)
+}
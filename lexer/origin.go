@@ -0,0 +1,102 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position is a place in a source file.
+type Position struct {
+	Filename string
+	Line     uint
+	Column   uint
+}
+
+// String returns the position as "file:line".
+func (p Position) String() string {
+	return fmt.Sprintf("%s:%d", p.Filename, p.Line)
+}
+
+// Position returns the position of the token.
+func (t Token) Position() Position {
+	return Position{Filename: t.Filename, Line: t.SourceLine, Column: t.SourceColumn}
+}
+
+// Origin is how a token came to be where it is when it wasn't lexed there, because a
+// preprocessor expanded it from a macro's body or included it from another file. Each
+// Origin links to the Origin of the place it happened, e.g. the macro whose body used
+// the macro, or the file that included the file.
+type Origin struct {
+	Macro      string   // The macro the token was expanded from, or "" if its file was included
+	Site       Position // Where the macro was expanded, or the #include that included the file
+	Definition Position // Where the token was written in the macro's definition
+	Parent     *Origin  // The origin of Site, or nil if it was lexed where it is
+}
+
+// String describes the chain of origins for diagnostics, innermost first, e.g.
+// "in macro MAX expanded at main.c:12, included from main.c:1".
+func (o *Origin) String() string {
+	var steps []string
+	for ; o != nil; o = o.Parent {
+		if o.Macro != "" {
+			steps = append(steps, fmt.Sprintf("in macro %s expanded at %s", o.Macro, o.Site))
+		} else {
+			steps = append(steps, fmt.Sprintf("included from %s", o.Site))
+		}
+	}
+	return strings.Join(steps, ", ")
+}
+
+// Source returns where a token was written: in the definition of the macro it was expanded
+// from, if it was, or else where it is.
+func (t Token) Source() Position {
+	if t.Origin != nil && t.Origin.Macro != "" {
+		return t.Origin.Definition
+	}
+	return t.Position()
+}
+
+// SourceMap maps the tokens of a preprocessed stream back to the files they were written
+// in, e.g. for debug info. It marshals to JSON.
+type SourceMap struct {
+	Files    []string  `json:"files"`
+	Mappings []Mapping `json:"mappings"` // The mapping of each token of the stream, in order
+}
+
+// Mapping maps a token of a stream to where it was written.
+type Mapping struct {
+	Line         uint   `json:"line"`            // The line of the stream, counting its EndOfLineType tokens from 1
+	File         int    `json:"file"`            // The index in Files of the file the token was written in
+	SourceLine   uint   `json:"sourceLine"`      // The line the token was written at
+	SourceColumn uint   `json:"sourceColumn"`    // The column the token was written at
+	Macro        string `json:"macro,omitempty"` // The macro the token was expanded from, if any
+}
+
+// NewSourceMap returns the source map of a stream of tokens, such as a preprocessor's
+// output, mapping each token except EOFType to its Source.
+func NewSourceMap(tokens []Token) *SourceMap {
+	m := &SourceMap{Files: []string{}, Mappings: []Mapping{}}
+	files := make(map[string]int)
+	line := uint(1)
+	for _, t := range tokens {
+		if t.ID == EOFType {
+			continue
+		}
+		source := t.Source()
+		file, found := files[source.Filename]
+		if !found {
+			file = len(m.Files)
+			files[source.Filename] = file
+			m.Files = append(m.Files, source.Filename)
+		}
+		mapping := Mapping{Line: line, File: file, SourceLine: source.Line, SourceColumn: source.Column}
+		if t.Origin != nil {
+			mapping.Macro = t.Origin.Macro
+		}
+		m.Mappings = append(m.Mappings, mapping)
+		if t.ID == EndOfLineType {
+			line++
+		}
+	}
+	return m
+}
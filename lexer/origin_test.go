@@ -0,0 +1,42 @@
+package lexer_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrigin(t *testing.T) {
+	included := &lexer.Origin{Site: lexer.Position{Filename: "main.asm", Line: 1, Column: 8}}
+	expanded := &lexer.Origin{
+		Macro:      "FOO",
+		Site:       lexer.Position{Filename: "main.asm", Line: 12, Column: 3},
+		Definition: lexer.Position{Filename: "macros.inc", Line: 4, Column: 20},
+		Parent:     included,
+	}
+	require.Equal(t, "in macro FOO expanded at main.asm:12, included from main.asm:1", expanded.String())
+
+	tokens := []lexer.Token{
+		{ID: lexer.IdentifierType, Literal: "lda", Filename: "macros.inc", SourceLine: 3, SourceColumn: 3, Origin: included},
+		{ID: lexer.IntegerLiteral, Literal: "1", Filename: "main.asm", SourceLine: 12, SourceColumn: 3, Origin: expanded},
+		{ID: lexer.EndOfLineType, Filename: "main.asm", SourceLine: 12, SourceColumn: 3},
+		{ID: lexer.IdentifierType, Literal: "rts", Filename: "main.asm", SourceLine: 13, SourceColumn: 3},
+		{ID: lexer.EOFType},
+	}
+	require.Equal(t, lexer.Position{Filename: "macros.inc", Line: 4, Column: 20}, tokens[1].Source())
+	require.Equal(t, lexer.Position{Filename: "macros.inc", Line: 3, Column: 3}, tokens[0].Source())
+
+	sourceMap, err := json.Marshal(lexer.NewSourceMap(tokens))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"files": ["macros.inc", "main.asm"],
+		"mappings": [
+			{"line": 1, "file": 0, "sourceLine": 3, "sourceColumn": 3},
+			{"line": 1, "file": 0, "sourceLine": 4, "sourceColumn": 20, "macro": "FOO"},
+			{"line": 1, "file": 1, "sourceLine": 12, "sourceColumn": 3},
+			{"line": 2, "file": 1, "sourceLine": 13, "sourceColumn": 3}
+		]
+	}`, string(sourceMap))
+}
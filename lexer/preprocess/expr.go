@@ -0,0 +1,236 @@
+package preprocess
+
+import (
+	"reflect"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+)
+
+// binaryPrecedence is the precedence of the binary operators of #if expressions, higher
+// binding tighter.
+var binaryPrecedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"|":  3,
+	"^":  4,
+	"&":  5,
+	"==": 6, "!=": 6,
+	"<": 7, "<=": 7, ">": 7, ">=": 7,
+	"<<": 8, ">>": 8,
+	"+": 9, "-": 9,
+	"*": 10, "/": 10, "%": 10,
+}
+
+// evaluate reports whether the expression of an #if or #elif is non-zero. defined(NAME)
+// and defined NAME are 1 if NAME is a macro, then macros are expanded, and names left
+// over are 0, as in C.
+func (p *Preprocessor) evaluate(tokens []lexer.Token, hash lexer.Token) (bool, error) {
+	var replaced []lexer.Token
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].Literal != "defined" || !isName(tokens[i]) {
+			replaced = append(replaced, tokens[i])
+			continue
+		}
+		var name lexer.Token
+		switch {
+		case i+1 < len(tokens) && isName(tokens[i+1]):
+			name = tokens[i+1]
+			i++
+		case i+3 < len(tokens) && tokens[i+1].Literal == "(" && isName(tokens[i+2]) && tokens[i+3].Literal == ")":
+			name = tokens[i+2]
+			i += 3
+		default:
+			return false, errorAt(hash, "defined needs a macro name")
+		}
+		value := int64(0)
+		if p.Defined(name.Literal) {
+			value = 1
+		}
+		replaced = append(replaced, lexer.Token{ID: lexer.IntegerLiteral, Category: lexer.LiteralCategory, Value: value})
+	}
+
+	expanded, _, err := p.expand(replaced, false)
+	if err != nil {
+		return false, err
+	}
+	if len(expanded) == 0 {
+		return false, errorAt(hash, "#if needs an expression")
+	}
+	e := evaluator{tokens: expanded, hash: hash}
+	value, err := e.expression(0, true)
+	if err != nil {
+		return false, err
+	}
+	if e.pos < len(e.tokens) {
+		return false, errorAt(hash, "unexpected %s in #if", text([]lexer.Token{e.tokens[e.pos]}))
+	}
+	return value != 0, nil
+}
+
+// evaluator evaluates an #if expression by precedence climbing.
+type evaluator struct {
+	tokens []lexer.Token
+	pos    int
+	hash   lexer.Token
+}
+
+// next returns the literal of the next token, or "" at the end.
+func (e *evaluator) next() string {
+	if e.pos < len(e.tokens) {
+		return e.tokens[e.pos].Literal
+	}
+	return ""
+}
+
+// expression evaluates a conditional expression whose binary operators bind at least as
+// tightly as minPrecedence. Unless evaluate is set, its value isn't used, as for the right
+// of "0 &&", so it's only parsed and can't fail on, e.g., a division by zero.
+func (e *evaluator) expression(minPrecedence int, evaluate bool) (int64, error) {
+	left, err := e.unary(evaluate)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := e.next()
+		precedence, binary := binaryPrecedence[op]
+		if !binary || precedence < minPrecedence {
+			break
+		}
+		e.pos++
+		evaluateRight := evaluate
+		switch op {
+		case "&&":
+			evaluateRight = evaluate && left != 0
+		case "||":
+			evaluateRight = evaluate && left == 0
+		}
+		right, err := e.expression(precedence+1, evaluateRight)
+		if err != nil {
+			return 0, err
+		}
+		if left, err = e.binary(op, left, right, evaluate); err != nil {
+			return 0, err
+		}
+	}
+	if minPrecedence > 0 || e.next() != "?" {
+		return left, nil
+	}
+
+	e.pos++
+	then, err := e.expression(0, evaluate && left != 0)
+	if err != nil {
+		return 0, err
+	}
+	if e.next() != ":" {
+		return 0, errorAt(e.hash, "? without : in #if")
+	}
+	e.pos++
+	otherwise, err := e.expression(0, evaluate && left == 0)
+	if err != nil {
+		return 0, err
+	}
+	if left != 0 {
+		return then, nil
+	}
+	return otherwise, nil
+}
+
+func (e *evaluator) binary(op string, left, right int64, evaluate bool) (int64, error) {
+	switch op {
+	case "||":
+		return truth(left != 0 || right != 0), nil
+	case "&&":
+		return truth(left != 0 && right != 0), nil
+	case "|":
+		return left | right, nil
+	case "^":
+		return left ^ right, nil
+	case "&":
+		return left & right, nil
+	case "==":
+		return truth(left == right), nil
+	case "!=":
+		return truth(left != right), nil
+	case "<":
+		return truth(left < right), nil
+	case "<=":
+		return truth(left <= right), nil
+	case ">":
+		return truth(left > right), nil
+	case ">=":
+		return truth(left >= right), nil
+	case "<<":
+		return left << uint64(right), nil
+	case ">>":
+		return left >> uint64(right), nil
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	}
+	if right == 0 {
+		if !evaluate {
+			return 0, nil
+		}
+		return 0, errorAt(e.hash, "division by zero in #if")
+	}
+	if op == "/" {
+		return left / right, nil
+	}
+	return left % right, nil
+}
+
+func (e *evaluator) unary(evaluate bool) (int64, error) {
+	if e.pos == len(e.tokens) {
+		return 0, errorAt(e.hash, "unexpected end of #if")
+	}
+	t := e.tokens[e.pos]
+	e.pos++
+	switch t.Literal {
+	case "!", "~", "-", "+":
+		value, err := e.unary(evaluate)
+		if err != nil {
+			return 0, err
+		}
+		switch t.Literal {
+		case "!":
+			return truth(value == 0), nil
+		case "~":
+			return ^value, nil
+		case "-":
+			return -value, nil
+		}
+		return value, nil
+	case "(":
+		value, err := e.expression(0, evaluate)
+		if err != nil {
+			return 0, err
+		}
+		if e.next() != ")" {
+			return 0, errorAt(e.hash, "( without ) in #if")
+		}
+		e.pos++
+		return value, nil
+	}
+
+	if t.ID == lexer.IntegerLiteral || t.ID == lexer.HexLiteral {
+		switch v := reflect.ValueOf(t.Value); {
+		case v.CanInt():
+			return v.Int(), nil
+		case v.CanUint():
+			return int64(v.Uint()), nil
+		}
+	} else if isName(t) {
+		return 0, nil // Names that aren't macros are 0
+	}
+	return 0, errorAt(e.hash, "unexpected %s in #if", text([]lexer.Token{t}))
+}
+
+func truth(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,280 @@
+package preprocess
+
+import (
+	"slices"
+	"unicode"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+)
+
+// macro is a #define.
+type macro struct {
+	name     string
+	function bool     // Whether the macro takes arguments, even none
+	params   []string // The names of a function-like macro's parameters
+	body     []lexer.Token
+}
+
+// Define defines a macro as "#define name body" would, e.g. Define("MAX(a, b)", "a > b ? a : b").
+func (p *Preprocessor) Define(name, body string) error {
+	tokens, err := p.lexer.TokenizeLine(name+" "+body, "", 0)
+	if err != nil {
+		return err
+	}
+	if n := len(tokens); n > 0 && tokens[n-1].ID == lexer.EndOfLineType {
+		tokens = tokens[:n-1]
+	}
+	return p.define(tokens, lexer.Token{})
+}
+
+// Undefine removes a macro, if it's defined.
+func (p *Preprocessor) Undefine(name string) {
+	delete(p.macros, name)
+}
+
+// Defined reports whether a macro is defined.
+func (p *Preprocessor) Defined(name string) bool {
+	_, found := p.macros[name]
+	return found
+}
+
+// define defines a macro from the tokens following "#define".
+func (p *Preprocessor) define(tokens []lexer.Token, hash lexer.Token) error {
+	if len(tokens) == 0 || !isName(tokens[0]) {
+		return errorAt(hash, "#define needs a macro name")
+	}
+	m := &macro{name: tokens[0].Literal}
+	body := tokens[1:]
+	if len(body) > 0 && body[0].Literal == "(" && adjacent(tokens[0], body) {
+		m.function = true
+		body = body[1:]
+		for {
+			if len(body) > 0 && body[0].Literal == ")" && len(m.params) == 0 {
+				body = body[1:]
+				break
+			}
+			if len(body) < 2 || !isName(body[0]) || (body[1].Literal != "," && body[1].Literal != ")") {
+				return errorAt(hash, "invalid parameters of macro %s", m.name)
+			}
+			if slices.Contains(m.params, body[0].Literal) {
+				return errorAt(hash, "duplicate parameter %s of macro %s", body[0].Literal, m.name)
+			}
+			m.params = append(m.params, body[0].Literal)
+			closed := body[1].Literal == ")"
+			body = body[2:]
+			if closed {
+				break
+			}
+		}
+	}
+	if n := len(body); n > 0 && (body[0].Literal == "##" || body[n-1].Literal == "##") {
+		return errorAt(hash, "## at the start or end of macro %s", m.name)
+	}
+	m.body = slices.Clone(body)
+	p.macros[m.name] = m
+	return nil
+}
+
+// adjacent reports whether the first of tokens, which follow name, starts where name ends.
+// A token's SourceColumn is where it ends, and the symbols of a run, such as "()", all end
+// at the end of the run, so the start of the run is found from the length of its tokens.
+func adjacent(name lexer.Token, tokens []lexer.Token) bool {
+	first := tokens[0]
+	length := uint(0)
+	for _, t := range tokens {
+		if t.SourceLine != first.SourceLine || t.SourceColumn != first.SourceColumn || t.ID == lexer.EndOfLineType {
+			break
+		}
+		length += uint(len(t.Literal))
+	}
+	return first.SourceLine == name.SourceLine && first.SourceColumn-length == name.SourceColumn
+}
+
+// item is a token being expanded, with the macros disabled for it: those whose expansion
+// it's part of, which it isn't expanded by again.
+type item struct {
+	lexer.Token
+	hidden []string
+}
+
+// expand replaces the macros in tokens with their expansions. more reports whether the
+// tokens of the following line may be added, in which case a call whose arguments aren't
+// closed, or which may still be followed by them, returns incomplete instead.
+func (p *Preprocessor) expand(tokens []lexer.Token, more bool) (out []lexer.Token, incomplete bool, err error) {
+	items := make([]item, len(tokens))
+	for i, t := range tokens {
+		items[i].Token = t
+	}
+	expanded, incomplete, err := p.rescan(items, more)
+	if err != nil || incomplete {
+		return nil, incomplete, err
+	}
+	out = make([]lexer.Token, len(expanded))
+	for i, it := range expanded {
+		out[i] = it.Token
+	}
+	return out, false, nil
+}
+
+// rescan replaces the macros in items with their expansions, as expand does. An expansion
+// is rescanned with the items after it, so that it can end with the name of a function-like
+// macro whose arguments follow it.
+func (p *Preprocessor) rescan(items []item, more bool) (out []item, incomplete bool, err error) {
+	for i := 0; i < len(items); i++ {
+		t := items[i]
+		m := p.macros[t.Literal]
+		if m == nil || !isName(t.Token) || slices.Contains(t.hidden, m.name) {
+			out = append(out, t)
+			continue
+		}
+
+		var args [][]item
+		end := i
+		if m.function {
+			open := i + 1
+			for open < len(items) && items[open].ID == lexer.EndOfLineType {
+				open++
+			}
+			if open == len(items) && more {
+				return nil, true, nil
+			} else if open == len(items) || items[open].Literal != "(" {
+				out = append(out, t) // Without arguments, a function-like macro's name is left alone
+				continue
+			}
+			if args, end = arguments(items, open); end < 0 {
+				if more {
+					return nil, true, nil
+				}
+				return nil, false, errorAt(t.Token, "unterminated call of macro %s", m.name)
+			}
+			if len(m.params) == 0 && len(args) == 1 && len(args[0]) == 0 {
+				args = nil
+			}
+			if len(args) != len(m.params) {
+				return nil, false, errorAt(t.Token, "macro %s takes %d arguments, not %d", m.name, len(m.params), len(args))
+			}
+		}
+
+		body, err := p.substitute(m, args, t)
+		if err != nil {
+			return nil, false, err
+		}
+		items = slices.Concat(items[:i], body, items[end+1:])
+		i-- // Rescan from the start of the expansion
+	}
+	return out, false, nil
+}
+
+// arguments splits the arguments of a call, from its '(' at items[open], returning the
+// index of its ')', or -1 if it isn't closed. Line breaks within the call are dropped.
+func arguments(items []item, open int) (args [][]item, end int) {
+	depth := 0
+	arg := []item{}
+	for i := open + 1; i < len(items); i++ {
+		t := items[i]
+		switch {
+		case t.ID == lexer.EndOfLineType:
+			continue
+		case t.Literal == "(":
+			depth++
+		case t.Literal == ")" && depth == 0:
+			return append(args, arg), i
+		case t.Literal == ")":
+			depth--
+		case t.Literal == "," && depth == 0:
+			args = append(args, arg)
+			arg = []item{}
+			continue
+		}
+		arg = append(arg, t)
+	}
+	return nil, -1
+}
+
+// substitute returns the body of a macro with its parameters replaced by their
+// arguments, expanded unless they're operands of ##, and its ## operators applied. The
+// tokens of the body take the position of the macro's name where it's used, with an
+// Origin giving where they were defined. Every item of the body disables the macro, and
+// the macros disabled for its name.
+func (p *Preprocessor) substitute(m *macro, args [][]item, at item) ([]item, error) {
+	var out []item
+	operand := 0 // The index in out of the last operand, which may be empty
+	for i := 0; i < len(m.body); i++ {
+		t := m.body[i]
+		if t.Literal == "##" {
+			i++
+			right := p.operand(m, m.body[i], args, at)
+			if operand == len(out) || len(right) == 0 {
+				operand = len(out)
+				out = append(out, right...)
+				continue
+			}
+			pasted, err := p.paste(out[len(out)-1].Token, right[0].Token)
+			if err != nil {
+				return nil, err
+			}
+			out[len(out)-1].Token = pasted
+			operand = len(out) - 1
+			out = append(out, right[1:]...)
+			continue
+		}
+
+		operand = len(out)
+		if k := slices.Index(m.params, t.Literal); k >= 0 && isName(t) && (i+1 == len(m.body) || m.body[i+1].Literal != "##") {
+			expanded, _, err := p.rescan(args[k], false)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, expanded...)
+			continue
+		}
+		out = append(out, p.operand(m, t, args, at)...)
+	}
+
+	disabled := append(at.hidden[:len(at.hidden):len(at.hidden)], m.name)
+	for i := range out {
+		for _, name := range disabled {
+			if !slices.Contains(out[i].hidden, name) {
+				out[i].hidden = append(out[i].hidden[:len(out[i].hidden):len(out[i].hidden)], name)
+			}
+		}
+	}
+	return out, nil
+}
+
+// operand returns the unexpanded argument of a parameter, or else the body token t
+// expanded at the position of at.
+func (p *Preprocessor) operand(m *macro, t lexer.Token, args [][]item, at item) []item {
+	if k := slices.Index(m.params, t.Literal); k >= 0 && isName(t) {
+		return args[k]
+	}
+	t.Origin = &lexer.Origin{Macro: m.name, Site: at.Position(), Definition: t.Position(), Parent: at.Origin}
+	t.Filename, t.SourceLine, t.SourceColumn = at.Filename, at.SourceLine, at.SourceColumn
+	return []item{{Token: t}}
+}
+
+// paste joins the literals of two tokens and lexes them as one token, at the position of
+// left.
+func (p *Preprocessor) paste(left, right lexer.Token) (lexer.Token, error) {
+	tokens, err := p.lexer.TokenizeLine(left.Literal+right.Literal, left.Filename, left.SourceLine)
+	if err != nil || len(tokens) != 2 {
+		return lexer.Token{}, errorAt(left, "pasting %s and %s doesn't give a token", left.Literal, right.Literal)
+	}
+	t := tokens[0]
+	t.SourceColumn, t.Origin = left.SourceColumn, left.Origin
+	return t, nil
+}
+
+// isName reports whether t is spelt as a macro name: a letter or '_' followed by letters,
+// digits and '_'.
+func isName(t lexer.Token) bool {
+	if t.Literal == "" || t.Category == lexer.LiteralCategory {
+		return false
+	}
+	for i, r := range t.Literal {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,414 @@
+// Package preprocess runs the directives of the C preprocessor over go-lexer tokens, so
+// that every token keeps the file, line and column it was lexed at:
+//
+//   - #include "file" and #include <file> lex the file, found by a Resolver, with the same
+//     Lexer and preprocess its tokens in place of the directive
+//   - #define and #undef manage object-like macros, e.g. "#define SIZE 10", and function-like
+//     macros, whose name is immediately followed by their parameters, e.g.
+//     "#define MAX(a, b) ((a) > (b) ? (a) : (b))". The ## operator pastes two tokens into one.
+//   - #if, #ifdef, #ifndef, #elif, #else and #endif keep or drop lines, #if and #elif
+//     evaluating integer constant expressions with defined(NAME)
+//   - #error fails with its message
+//
+// As in C, a line ending with a backslash is joined to the line after it before lexing, so
+// that a directive can continue over several lines. The tokens of the joined lines take
+// their position in the joined line, and the lines after it keep their numbers.
+//
+// A directive is a line whose first token's Literal is "#", followed by the directive's name,
+// so the language must lex '#' as a token, e.g. languages.C(). Directive lines are removed
+// from the output. The tokens of a macro's body take the position of the macro's name where
+// it's used, while its arguments keep their own. Expanded and included tokens have an
+// Origin, which errors describe, and lexer.NewSourceMap maps the output back to the files
+// it was written in.
+package preprocess
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+)
+
+// Resolver finds an included file, returning its filename, which becomes the Filename of
+// its tokens, and its contents. name is the file named by the #include, from the filename
+// of the including file, and system reports whether the name was in angle brackets.
+type Resolver func(name string, from string, system bool) (filename string, r io.ReadCloser, err error)
+
+// FSResolver returns a Resolver that opens included files in fsys, first relative to the
+// directory of the including file, unless the name is in angle brackets, then relative to
+// each of dirs in turn. Filenames are slash-separated paths, as fs.FS expects.
+func FSResolver(fsys fs.FS, dirs ...string) Resolver {
+	return func(name string, from string, system bool) (string, io.ReadCloser, error) {
+		var candidates []string
+		if !system {
+			candidates = append(candidates, path.Join(path.Dir(from), name))
+		}
+		for _, dir := range dirs {
+			candidates = append(candidates, path.Join(dir, name))
+		}
+		for _, filename := range candidates {
+			f, err := fsys.Open(filename)
+			if err == nil {
+				return filename, f, nil
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return "", nil, err
+			}
+		}
+		return "", nil, fs.ErrNotExist
+	}
+}
+
+// Preprocessor preprocesses the tokens of files lexed by a Lexer. Its macros persist from
+// one call of Preprocess to the next.
+type Preprocessor struct {
+	lexer    *lexer.Lexer
+	resolve  Resolver
+	macros   map[string]*macro
+	includes []include // Files being preprocessed, innermost last
+}
+
+// include is a file being preprocessed, the first of them given to Preprocess and the
+// rest included by the one before.
+type include struct {
+	filename string
+	guard    string // The macro of the file's include guard, if it has one
+}
+
+// condition is the state of an #if, #ifdef or #ifndef and its #elif and #else branches.
+type condition struct {
+	start   lexer.Token // The '#' of the #if, for error messages
+	parent  bool        // Whether the lines around the #if are kept
+	active  bool        // Whether the lines of the current branch are kept
+	taken   bool        // Whether a branch has been active
+	sawElse bool
+}
+
+// New returns a Preprocessor lexing files with l and finding included files with resolve,
+// which may be nil if #include isn't needed.
+func New(l *lexer.Lexer, resolve Resolver) *Preprocessor {
+	return &Preprocessor{lexer: l, resolve: resolve, macros: make(map[string]*macro)}
+}
+
+// Preprocess lexes r and returns its preprocessed tokens, ending with an EOFType token.
+// Errors give the file and line of the directive or macro call that caused them.
+func (p *Preprocessor) Preprocess(r io.Reader, filename string) ([]lexer.Token, error) {
+	p.includes = p.includes[:0]
+	tokens, err := p.file(nil, r, filename, nil)
+	if err != nil {
+		return nil, err
+	}
+	return append(tokens, lexer.Token{ID: lexer.EOFType, Category: lexer.TriviaCategory, Filename: filename}), nil
+}
+
+// file lexes a file and appends its preprocessed tokens to out. origin is the origin of
+// the file's tokens, if it's included.
+func (p *Preprocessor) file(out []lexer.Token, r io.Reader, filename string, origin *lexer.Origin) ([]lexer.Token, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := p.lexer.Tokenize(bytes.NewReader(spliceLines(src)), filename)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tokens {
+		tokens[i].Origin = origin
+	}
+	lines := splitLines(tokens)
+	p.includes = append(p.includes, include{filename: filename, guard: includeGuard(lines)})
+	defer func() { p.includes = p.includes[:len(p.includes)-1] }()
+
+	var conditions []condition
+	for i := 0; i < len(lines); i++ {
+		active := len(conditions) == 0 || conditions[len(conditions)-1].active
+		if hash, name, args, found := directive(lines[i]); found {
+			if out, err = p.directive(out, &conditions, active, hash, name, args); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !active {
+			continue
+		}
+
+		// A macro call may continue onto the following lines
+		line := lines[i]
+		for {
+			more := i+1 < len(lines) && !isDirective(lines[i+1])
+			expanded, incomplete, err := p.expand(line, more)
+			if err != nil {
+				return nil, err
+			}
+			if !incomplete {
+				out = append(out, expanded...)
+				break
+			}
+			i++
+			line = append(line[:len(line):len(line)], lines[i]...)
+		}
+	}
+	if len(conditions) > 0 {
+		return nil, errorAt(conditions[len(conditions)-1].start, "#if without #endif")
+	}
+	return out, nil
+}
+
+// directive carries out a directive, appending the tokens of an included file to out.
+func (p *Preprocessor) directive(out []lexer.Token, conditions *[]condition, active bool, hash, name lexer.Token, args []lexer.Token) ([]lexer.Token, error) {
+	var top *condition
+	if n := len(*conditions); n > 0 {
+		top = &(*conditions)[n-1]
+	}
+
+	switch name.Literal {
+	case "if", "ifdef", "ifndef":
+		c := condition{start: hash, parent: active}
+		if active {
+			var err error
+			if c.active, err = p.condition(name.Literal, args, hash); err != nil {
+				return nil, err
+			}
+		}
+		c.taken = c.active
+		*conditions = append(*conditions, c)
+		return out, nil
+	case "elif":
+		if top == nil || top.sawElse {
+			return nil, errorAt(hash, "#elif without #if")
+		}
+		top.active = false
+		if top.parent && !top.taken {
+			var err error
+			if top.active, err = p.condition("if", args, hash); err != nil {
+				return nil, err
+			}
+			top.taken = top.active
+		}
+		return out, nil
+	case "else":
+		if top == nil || top.sawElse {
+			return nil, errorAt(hash, "#else without #if")
+		}
+		top.sawElse = true
+		top.active = top.parent && !top.taken
+		top.taken = true
+		return out, nil
+	case "endif":
+		if top == nil {
+			return nil, errorAt(hash, "#endif without #if")
+		}
+		*conditions = (*conditions)[:len(*conditions)-1]
+		return out, nil
+	}
+
+	if !active {
+		return out, nil // Other directives, even unknown ones, are ignored in dropped lines
+	}
+	switch name.Literal {
+	case "":
+		return out, nil
+	case "define":
+		return out, p.define(args, hash)
+	case "undef":
+		if len(args) != 1 || !isName(args[0]) {
+			return nil, errorAt(hash, "#undef needs a macro name")
+		}
+		delete(p.macros, args[0].Literal)
+		return out, nil
+	case "include":
+		return p.include(out, args, hash)
+	case "error":
+		return nil, errorAt(hash, "#error %s", text(args))
+	}
+	return nil, errorAt(hash, "unknown directive #%s", name.Literal)
+}
+
+// condition evaluates the condition of an #if, #ifdef or #ifndef.
+func (p *Preprocessor) condition(kind string, args []lexer.Token, hash lexer.Token) (bool, error) {
+	if kind == "if" {
+		return p.evaluate(args, hash)
+	}
+	if len(args) != 1 || !isName(args[0]) {
+		return false, errorAt(hash, "#%s needs a macro name", kind)
+	}
+	return p.Defined(args[0].Literal) == (kind == "ifdef"), nil
+}
+
+// include preprocesses an included file, appending its tokens to out.
+func (p *Preprocessor) include(out []lexer.Token, args []lexer.Token, hash lexer.Token) ([]lexer.Token, error) {
+	name, system, found := includeName(args)
+	if !found {
+		return nil, errorAt(hash, "#include needs \"file\" or <file>")
+	}
+	if p.resolve == nil {
+		return nil, errorAt(hash, "#include %q without a resolver", name)
+	}
+	filename, r, err := p.resolve(name, hash.Filename, system)
+	if err != nil {
+		return nil, errorAt(hash, "#include %q: %w", name, err)
+	}
+	defer r.Close()
+
+	// Including a file that's still being preprocessed would repeat forever, unless its
+	// include guard is defined by then, leaving nothing to include
+	for _, in := range p.includes {
+		if in.filename != filename {
+			continue
+		}
+		if in.guard != "" && p.Defined(in.guard) {
+			return out, nil
+		}
+		chain := make([]string, 0, len(p.includes)+1)
+		for _, in := range p.includes {
+			chain = append(chain, in.filename)
+		}
+		return nil, errorAt(hash, "include cycle %s", strings.Join(append(chain, filename), " -> "))
+	}
+	return p.file(out, r, filename, &lexer.Origin{Site: hash.Position(), Parent: hash.Origin})
+}
+
+// includeGuard returns the macro of a file's include guard: an #ifndef on its first line
+// whose #endif is on its last line.
+func includeGuard(lines [][]lexer.Token) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	_, name, args, found := directive(lines[0])
+	if !found || name.Literal != "ifndef" || len(args) != 1 || !isName(args[0]) {
+		return ""
+	}
+	depth := 0
+	for i, line := range lines {
+		if _, name, _, found := directive(line); found {
+			switch name.Literal {
+			case "if", "ifdef", "ifndef":
+				depth++
+			case "endif":
+				if depth--; depth == 0 && i < len(lines)-1 {
+					return ""
+				}
+			}
+		}
+	}
+	return args[0].Literal
+}
+
+// includeName returns the name of an #include's file, reporting whether it was in angle
+// brackets.
+func includeName(args []lexer.Token) (name string, system bool, found bool) {
+	switch {
+	case len(args) == 1 && args[0].ID == lexer.StringLiteral:
+		name, found = args[0].Value.(string)
+		return name, false, found
+	case len(args) > 2 && args[0].Literal == "<" && args[len(args)-1].Literal == ">":
+		var b strings.Builder
+		for _, t := range args[1 : len(args)-1] {
+			b.WriteString(t.Literal)
+		}
+		return b.String(), true, true
+	}
+	return "", false, false
+}
+
+// spliceLines joins each line ending with a backslash to the line after it, adding an
+// empty line after the joined line for each line it joined, so that the lines after it
+// keep their numbers.
+func spliceLines(src []byte) []byte {
+	if !bytes.Contains(src, []byte("\\\n")) && !bytes.Contains(src, []byte("\\\r")) {
+		return src
+	}
+	out := make([]byte, 0, len(src))
+	joined := 0
+	for len(src) > 0 {
+		i := bytes.IndexAny(src, "\r\n")
+		if i < 0 {
+			return append(out, src...)
+		}
+		end := i + 1
+		if src[i] == '\r' && end < len(src) && src[end] == '\n' {
+			end++
+		}
+		if i > 0 && src[i-1] == '\\' {
+			out = append(out, src[:i-1]...)
+			joined++
+		} else {
+			out = append(out, src[:end]...)
+			for ; joined > 0; joined-- {
+				out = append(out, src[i:end]...)
+			}
+		}
+		src = src[end:]
+	}
+	return out
+}
+
+// splitLines splits tokens into lines, each ending with its EndOfLineType token, dropping
+// the EOFType token.
+func splitLines(tokens []lexer.Token) [][]lexer.Token {
+	var lines [][]lexer.Token
+	start := 0
+	for i, t := range tokens {
+		switch t.ID {
+		case lexer.EndOfLineType:
+			lines = append(lines, tokens[start:i+1:i+1])
+			start = i + 1
+		case lexer.EOFType:
+			if start < i {
+				lines = append(lines, tokens[start:i:i])
+			}
+			return lines
+		}
+	}
+	if start < len(tokens) {
+		lines = append(lines, tokens[start:])
+	}
+	return lines
+}
+
+// directive splits a directive line into its '#', its name and its arguments, reporting
+// false if the line isn't a directive. A '#' alone is a directive that does nothing.
+func directive(line []lexer.Token) (hash, name lexer.Token, args []lexer.Token, found bool) {
+	if !isDirective(line) {
+		return hash, name, nil, false
+	}
+	if n := len(line); line[n-1].ID == lexer.EndOfLineType {
+		line = line[:n-1]
+	}
+	if len(line) < 2 {
+		return line[0], lexer.Token{}, nil, true
+	}
+	return line[0], line[1], line[2:], true
+}
+
+func isDirective(line []lexer.Token) bool {
+	return len(line) > 0 && line[0].Literal == "#" && line[0].Category != lexer.LiteralCategory
+}
+
+// errorAt returns an error at the position of t, if it has one, followed by its origin.
+func errorAt(t lexer.Token, format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	if t.Origin != nil {
+		err = fmt.Errorf("%w (%s)", err, t.Origin)
+	}
+	if t.Filename == "" && t.SourceLine == 0 {
+		return err
+	}
+	return fmt.Errorf("[%s line: %d] %w", t.Filename, t.SourceLine, err)
+}
+
+// text joins the tokens' literals with spaces, for messages.
+func text(tokens []lexer.Token) string {
+	literals := make([]string, len(tokens))
+	for i, t := range tokens {
+		literals[i] = t.Literal
+		if t.ID == lexer.StringLiteral {
+			literals[i] = fmt.Sprintf("%q", t.Value)
+		}
+	}
+	return strings.Join(literals, " ")
+}
@@ -0,0 +1,283 @@
+package preprocess_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/jrsteele09/go-lexer/lexer/languages"
+	"github.com/jrsteele09/go-lexer/lexer/preprocess"
+	"github.com/stretchr/testify/require"
+)
+
+func newPreprocessor(files fstest.MapFS) *preprocess.Preprocessor {
+	return preprocess.New(lexer.NewLexer(languages.C()), preprocess.FSResolver(files, "include"))
+}
+
+// source joins the literals of tokens, one line of output per line.
+func source(tokens []lexer.Token) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		switch t.ID {
+		case lexer.EndOfLineType:
+			b.WriteString("\n")
+		case lexer.EOFType:
+		case lexer.StringLiteral:
+			b.WriteString(`"` + t.Value.(string) + `" `)
+		default:
+			b.WriteString(t.Literal + " ")
+		}
+	}
+	return b.String()
+}
+
+func TestMacros(t *testing.T) {
+	code := strings.Join([]string{
+		"#define SIZE 10",
+		"#define MAX(a, b) ((a) > (b) ? (a) : (b))",
+		"#define NAME(prefix, n) prefix ## _ ## n",
+		"#define F (x)",
+		"#define NONE() 0",
+		"int NAME(buf, SIZE)[SIZE];",
+		"x = MAX(SIZE,",
+		"        f(1, 2));",
+		"y = F + MAX + NONE();",
+		"#undef SIZE",
+		"z = SIZE;",
+	}, "\n")
+	tokens, err := newPreprocessor(nil).Preprocess(strings.NewReader(code), "main.c")
+	require.NoError(t, err)
+	require.Equal(t, strings.Join([]string{
+		"int buf_SIZE [ 10 ] ; ",
+		"x = ( ( 10 ) > ( f ( 1 , 2 ) ) ? ( 10 ) : ( f ( 1 , 2 ) ) ) ; ",
+		"y = ( x ) + MAX + 0 ; ",
+		"z = SIZE ; ",
+		"",
+	}, "\n"), source(tokens))
+	require.Equal(t, lexer.EOFType, tokens[len(tokens)-1].ID)
+
+	// Body tokens take the position of the macro's name, arguments keep their own
+	for _, token := range tokens {
+		if token.Literal == "f" {
+			require.Equal(t, uint(8), token.SourceLine)
+		} else if token.Literal == "?" {
+			require.Equal(t, uint(7), token.SourceLine)
+			require.Equal(t, uint(7), token.SourceColumn)
+		}
+	}
+
+	for code, message := range map[string]string{
+		"#define F(a) a\nF(1, 2)":               "[main.c line: 2] macro F takes 1 arguments, not 2",
+		"#define F(a) a\nF(1":                   "[main.c line: 2] unterminated call of macro F",
+		"#define F(a, a) a":                     "duplicate parameter a of macro F",
+		"#define F(a ## a":                      "invalid parameters of macro F",
+		"#define F ## a":                        "## at the start or end of macro F",
+		"#define F(a, b) a ## b\nF(+, /)":       "pasting + and / doesn't give a token",
+		"#define":                               "#define needs a macro name",
+		"#pragma once":                          "unknown directive #pragma",
+		"#error unsupported \"target\"":         `[main.c line: 1] #error unsupported "target"`,
+		"#include \"missing.h\"":                "#include \"missing.h\": file does not exist",
+		"#include missing.h":                    "#include needs \"file\" or <file>",
+		"#define SELF SELF +\n#if SELF\n#endif": "unexpected end of #if",
+	} {
+		_, err := newPreprocessor(nil).Preprocess(strings.NewReader(code), "main.c")
+		require.ErrorContains(t, err, message, code)
+	}
+}
+
+func TestLineSplicing(t *testing.T) {
+	code := "#define A 1 + \\\n 2\n#define MAX(a, b) \\\r\n  ((a) > (b) \\\r\n  ? (a) : (b))\nx = A;\ny = MAX(A, 3);"
+	tokens, err := newPreprocessor(nil).Preprocess(strings.NewReader(code), "main.c")
+	require.NoError(t, err)
+	require.Equal(t, "x = 1 + 2 ; \ny = ( ( 1 + 2 ) > ( 3 ) ? ( 1 + 2 ) : ( 3 ) ) ; \n", source(tokens))
+
+	// The lines after joined lines keep their numbers
+	require.Equal(t, uint(6), tokens[0].SourceLine)
+	require.Equal(t, uint(7), tokens[len(tokens)-2].SourceLine)
+}
+
+func TestRecursiveMacros(t *testing.T) {
+	code := "#define foo foo + bar\n#define bar foo\n#define G(x) x G(x)\nfoo; G(G(1));"
+	tokens, err := newPreprocessor(nil).Preprocess(strings.NewReader(code), "main.c")
+	require.NoError(t, err)
+	require.Equal(t, "foo + foo ; 1 G ( 1 ) G ( 1 G ( 1 ) ) ; \n", source(tokens))
+
+	// A macro left alone in an argument isn't expanded when the argument is rescanned
+	code = "#define foo foo + 1\n#define ID(x) x\nID(foo); ID(ID)(2);"
+	tokens, err = newPreprocessor(nil).Preprocess(strings.NewReader(code), "main.c")
+	require.NoError(t, err)
+	require.Equal(t, "foo + 1 ; ID ( 2 ) ; \n", source(tokens))
+}
+
+func TestRescanning(t *testing.T) {
+	// An expansion is rescanned with the tokens after it, which can call a macro it ends with
+	code := strings.Join([]string{
+		"#define F G",
+		"#define G(x) x+1",
+		"#define CALL(f) f(3)",
+		"F(2); CALL(G); F",
+		"(4) F;",
+	}, "\n")
+	tokens, err := newPreprocessor(nil).Preprocess(strings.NewReader(code), "main.c")
+	require.NoError(t, err)
+	require.Equal(t, "2 + 1 ; 3 + 1 ; 4 + 1 G ; \n", source(tokens))
+}
+
+func TestDefine(t *testing.T) {
+	p := newPreprocessor(nil)
+	require.NoError(t, p.Define("DEBUG", ""))
+	require.NoError(t, p.Define("SQUARE(x)", "x * x"))
+	require.True(t, p.Defined("DEBUG"))
+	require.ErrorContains(t, p.Define("1", ""), "#define needs a macro name")
+
+	tokens, err := p.Preprocess(strings.NewReader("#ifdef DEBUG\nSQUARE(2)\n#endif"), "main.c")
+	require.NoError(t, err)
+	require.Equal(t, "2 * 2 \n", source(tokens))
+
+	p.Undefine("DEBUG")
+	require.False(t, p.Defined("DEBUG"))
+	tokens, err = p.Preprocess(strings.NewReader("#ifdef DEBUG\nSQUARE(2)\n#endif"), "main.c")
+	require.NoError(t, err)
+	require.Equal(t, "", source(tokens))
+}
+
+func TestConditionals(t *testing.T) {
+	code := strings.Join([]string{
+		"#define VERSION 3",
+		"#define TWICE(x) ((x) * 2)",
+		"#if VERSION >= 3 && defined(TWICE) && !defined UNKNOWN",
+		"a",
+		"#  if UNKNOWN || 0x10 != 16",
+		"b",
+		"#  elif TWICE(VERSION) == 6 ? 1 : 0",
+		"c",
+		"#  else",
+		"d",
+		"#  endif",
+		"#elif 1 / 0",
+		"e",
+		"#else",
+		"#  bogus directive",
+		"f",
+		"#endif",
+		"#ifndef VERSION",
+		"g",
+		"#elif (1 << 4 | 1) % 16 - -1 == 2 && ~0 == -1",
+		"h",
+		"#endif",
+		"#if 0 && 1 / 0 || 1 || 1 % 0",
+		"i",
+		"#endif",
+		"#if VERSION ? 1 : 1 / 0",
+		"j",
+		"#endif",
+		"#",
+	}, "\n")
+	tokens, err := newPreprocessor(nil).Preprocess(strings.NewReader(code), "main.c")
+	require.NoError(t, err)
+	require.Equal(t, "a \nc \nh \ni \nj \n", source(tokens))
+
+	for code, message := range map[string]string{
+		"#if 1":                          "[main.c line: 1] #if without #endif",
+		"#else":                          "#else without #if",
+		"#if 1\n#else\n#elif 1":          "[main.c line: 3] #elif without #if",
+		"#endif":                         "#endif without #if",
+		"#if 1 / 0\n#endif":              "division by zero in #if",
+		"#if (1\n#endif":                 "( without ) in #if",
+		"#if 1 ? 2\n#endif":              "? without : in #if",
+		"#if 1 2\n#endif":                "unexpected 2 in #if",
+		"#if 1.5\n#endif":                "unexpected 1.5 in #if",
+		"#if 'a'\n#endif":                `unexpected "a" in #if`,
+		"#if 0 ? 1 / 0 : 2 % 0\n#endif":  "division by zero in #if",
+		"#if\n#endif":                    "#if needs an expression",
+		"#if defined(\n#endif":           "defined needs a macro name",
+		"#ifdef\n#endif":                 "#ifdef needs a macro name",
+		"#define F(x) x\n#if F(\n#endif": "unterminated call of macro F",
+	} {
+		_, err := newPreprocessor(nil).Preprocess(strings.NewReader(code), "main.c")
+		require.ErrorContains(t, err, message, code)
+	}
+}
+
+func TestIncludes(t *testing.T) {
+	files := fstest.MapFS{
+		"src/main.c":      {Data: []byte("#include \"config.h\"\n#include <lib.h>\nint x = LIMIT;\n")},
+		"src/config.h":    {Data: []byte("#ifndef CONFIG_H\n#define CONFIG_H\n#include <lib.h>\n#define LIMIT MAX\n#endif\n")},
+		"include/lib.h":   {Data: []byte("#ifndef LIB_H\n#define LIB_H\nint lib;\n#define MAX 100\n#endif\n")},
+		"src/a.h":         {Data: []byte("a\n#include \"b.h\"\n")},
+		"src/b.h":         {Data: []byte("b\n#include \"a.h\"\n")},
+		"src/cycle.c":     {Data: []byte("#include \"a.h\"\n")},
+		"src/sys.h":       {Data: []byte("local\n")},
+		"src/sys.c":       {Data: []byte("#include <sys.h>\n")},
+		"include/twice.h": {Data: []byte("t\n")},
+		"src/twice.c":     {Data: []byte("#include <twice.h>\n#include <twice.h>\n")},
+		"src/guard1.h":    {Data: []byte("#ifndef GUARD1\n#define GUARD1\n#include \"guard2.h\"\ng1\n#endif\n")},
+		"src/guard2.h":    {Data: []byte("#ifndef GUARD2\n#define GUARD2\n#include \"guard1.h\"\ng2\n#endif\n")},
+	}
+	p := newPreprocessor(files)
+	main, err := files.Open("src/main.c")
+	require.NoError(t, err)
+	tokens, err := p.Preprocess(main, "src/main.c")
+	require.NoError(t, err)
+	require.Equal(t, "int lib ; \nint x = 100 ; \n", source(tokens))
+	require.Equal(t, "include/lib.h", tokens[0].Filename)
+	require.Equal(t, uint(3), tokens[0].SourceLine)
+	require.Equal(t, "src/main.c", tokens[len(tokens)-1].Filename)
+
+	tokens, err = newPreprocessor(files).Preprocess(strings.NewReader("#include <twice.h>\n#include <twice.h>\n"), "src/twice.c")
+	require.NoError(t, err)
+	require.Equal(t, "t \nt \n", source(tokens))
+
+	// Guarded headers may include each other
+	tokens, err = newPreprocessor(files).Preprocess(strings.NewReader("#include \"guard1.h\"\n#include \"guard2.h\"\n"), "src/guards.c")
+	require.NoError(t, err)
+	require.Equal(t, "g2 \ng1 \n", source(tokens))
+
+	_, err = newPreprocessor(files).Preprocess(strings.NewReader("#include \"a.h\"\n"), "src/cycle.c")
+	require.ErrorContains(t, err, "[src/b.h line: 2] include cycle src/cycle.c -> src/a.h -> src/b.h -> src/a.h")
+	_, err = newPreprocessor(files).Preprocess(strings.NewReader("x\n#include \"cycle.c\"\n"), "src/cycle.c")
+	require.ErrorContains(t, err, "[src/cycle.c line: 2] include cycle src/cycle.c -> src/cycle.c")
+
+	_, err = newPreprocessor(files).Preprocess(strings.NewReader("#include <sys.h>\n"), "src/sys.c")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = preprocess.New(lexer.NewLexer(languages.C()), nil).Preprocess(strings.NewReader("#include <lib.h>"), "main.c")
+	require.ErrorContains(t, err, `#include "lib.h" without a resolver`)
+}
+
+func TestOrigins(t *testing.T) {
+	files := fstest.MapFS{
+		"macros.h": {Data: []byte("#define ONE 1\n#define INC(x) (x + ONE)\n#define BAD(a) INC(a, a)\n")},
+		"main.c":   {Data: []byte("#include \"macros.h\"\nint y;\ny = INC(y);\n#ifdef CHECK\nBAD(y)\n#endif\n")},
+	}
+	p := newPreprocessor(files)
+	require.NoError(t, p.Define("CHECK", ""))
+	main, err := files.Open("main.c")
+	require.NoError(t, err)
+	_, err = p.Preprocess(main, "main.c")
+	require.EqualError(t, err, "[main.c line: 5] macro INC takes 1 arguments, not 2 (in macro BAD expanded at main.c:5)")
+
+	p.Undefine("CHECK")
+	main, err = files.Open("main.c")
+	require.NoError(t, err)
+	tokens, err := p.Preprocess(main, "main.c")
+	require.NoError(t, err)
+	require.Equal(t, "int y ; \ny = ( y + 1 ) ; \n", source(tokens))
+
+	// 1 comes from ONE in the body of INC, used at line 3 of main.c
+	one := tokens[9]
+	require.Equal(t, "1", one.Literal)
+	require.Equal(t, lexer.Position{Filename: "main.c", Line: 3, Column: 7}, one.Position())
+	require.Equal(t, "in macro ONE expanded at main.c:3, in macro INC expanded at main.c:3", one.Origin.String())
+	require.Equal(t, lexer.Position{Filename: "macros.h", Line: 1, Column: 13}, one.Source())
+	require.Equal(t, lexer.Position{Filename: "macros.h", Line: 2, Column: 23}, one.Origin.Parent.Definition)
+	require.Nil(t, tokens[0].Origin)
+
+	sourceMap := lexer.NewSourceMap(tokens)
+	require.Equal(t, []string{"main.c", "macros.h"}, sourceMap.Files)
+	require.Len(t, sourceMap.Mappings, len(tokens)-1)
+	require.Equal(t, lexer.Mapping{Line: 2, File: 1, SourceLine: 1, SourceColumn: 13, Macro: "ONE"}, sourceMap.Mappings[9])
+	require.Equal(t, lexer.Mapping{Line: 2, File: 0, SourceLine: 3, SourceColumn: 9}, sourceMap.Mappings[7])
+}
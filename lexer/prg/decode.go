@@ -0,0 +1,333 @@
+package prg
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"golang.org/x/text/encoding"
+)
+
+// Decode lists a PRG file's BASIC program, returning its tokens as if Lexer.Tokenize had
+// lexed the listing that List returns with LineNumberLabels: each line starts with a
+// LineNumberType token and ends with an EndOfLineType token, and an EOFType token ends the
+// program. Tokens' SourceLine and SourceColumn give their line in the listing and the byte
+// offset at which they end.
+//
+// Token bytes become KeywordCategory tokens with the dialect's identifiers. Where several
+// keywords share a token, such as "^" and "↑", the text the dialect's character set can
+// encode is used, then the shortest. Text between tokens becomes IntegerLiteral,
+// NumberLiteral, StringLiteral and IdentifierType tokens, and SymbolCategory tokens of a
+// single rune with the NullType identifier, as the dialect doesn't identify its symbols.
+// A string left unterminated at the end of its line has its text, from the opening quote,
+// as its Literal. The text after REM is a single CommentType token.
+func Decode(program []byte, dialect *Dialect, filename string) ([]lexer.Token, error) {
+	listing, err := decode(program, dialect, filename)
+	if err != nil {
+		return nil, err
+	}
+	return listing.tokens, nil
+}
+
+// List lists a PRG file's BASIC program as source text, with spaces around keywords that
+// would otherwise run into their neighbours, e.g. "10 IF A<=B THEN PRINT A$".
+func List(program []byte, dialect *Dialect) (string, error) {
+	listing, err := decode(program, dialect, "")
+	if err != nil {
+		return "", err
+	}
+	return listing.source.String(), nil
+}
+
+type listing struct {
+	filename string
+	tokens   []lexer.Token
+	source   strings.Builder
+	line     strings.Builder // The listing's current line
+	lineNo   uint
+}
+
+// decode decodes the lines of a program into a listing.
+func decode(program []byte, dialect *Dialect, filename string) (*listing, error) {
+	if len(program) < 2 {
+		return nil, fmt.Errorf("prg: program has no load address")
+	}
+	d := newDecoder(dialect)
+	l := &listing{filename: filename, lineNo: 1}
+	for offset := 2; ; l.lineNo++ {
+		if offset+2 > len(program) {
+			return nil, fmt.Errorf("prg: [%s line: %d] program is truncated", filename, l.lineNo)
+		}
+		if binary.LittleEndian.Uint16(program[offset:]) == 0 {
+			break
+		}
+		end := offset + 4
+		for end < len(program) && program[end] != 0 {
+			end++
+		}
+		if end >= len(program) {
+			return nil, fmt.Errorf("prg: [%s line: %d] program is truncated", filename, l.lineNo)
+		}
+
+		number := binary.LittleEndian.Uint16(program[offset+2:])
+		l.add(0, lexer.Token{ID: lexer.LineNumberType, Category: lexer.LiteralCategory, Literal: strconv.Itoa(int(number)), Value: int64(number)})
+		items, err := d.decodeLine(program[offset+4 : end])
+		if err != nil {
+			return nil, fmt.Errorf("prg: [%s line: %d] %w", filename, l.lineNo, err)
+		}
+		l.addItems(items)
+		l.add(0, lexer.Token{ID: lexer.EndOfLineType, Category: lexer.TriviaCategory, Literal: "\n"})
+		l.source.WriteString(l.line.String())
+		l.line.Reset()
+		offset = end + 1
+	}
+	l.tokens = append(l.tokens, lexer.Token{ID: lexer.EOFType, Category: lexer.TriviaCategory, Filename: filename, SourceLine: l.lineNo})
+	return l, nil
+}
+
+// item is a keyword token, or text between them.
+type item struct {
+	keyword *lexer.Token
+	text    string
+}
+
+type decoder struct {
+	keywords map[lexer.TokenIdentifier]string // The text listed for each token
+	prefixes map[byte]bool                    // First bytes of two byte tokens
+	charset  *encoding.Decoder
+}
+
+func newDecoder(dialect *Dialect) *decoder {
+	d := &decoder{keywords: map[lexer.TokenIdentifier]string{}, prefixes: map[byte]bool{}}
+	if dialect.Charset != nil {
+		d.charset = dialect.Charset.NewDecoder()
+	}
+
+	var encoder *encoding.Encoder
+	if dialect.Charset != nil {
+		encoder = dialect.Charset.NewEncoder()
+	}
+	encodable := func(text string) bool {
+		if encoder == nil {
+			return true
+		}
+		_, err := encoder.String(text)
+		return err == nil
+	}
+
+	keywords := make([]string, 0, len(dialect.Keywords))
+	for keyword := range dialect.Keywords {
+		keywords = append(keywords, keyword)
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if a, b := encodable(keywords[i]), encodable(keywords[j]); a != b {
+			return a
+		}
+		if len(keywords[i]) != len(keywords[j]) {
+			return len(keywords[i]) < len(keywords[j])
+		}
+		return keywords[i] < keywords[j]
+	})
+	for _, keyword := range keywords {
+		id := dialect.Keywords[keyword]
+		if _, found := d.keywords[id]; !found {
+			d.keywords[id] = keyword
+		}
+		if id < 0 || id > 0xFF {
+			d.prefixes[byte(uint16(id)>>8)] = true
+		}
+	}
+	return d
+}
+
+// decodeLine splits the bytes of a line after its line number into keywords and text. Bytes
+// in strings and after REM are always text.
+func (d *decoder) decodeLine(body []byte) ([]item, error) {
+	var items []item
+	textStart := 0
+	flush := func(end int) error {
+		if end == textStart {
+			return nil
+		}
+		text, err := d.decodeText(body[textStart:end])
+		if err != nil {
+			return err
+		}
+		items = append(items, item{text: text})
+		return nil
+	}
+
+	inString := false
+	for i := 0; i < len(body); i++ {
+		b := body[i]
+		if b == '"' {
+			inString = !inString
+		}
+		if inString {
+			continue
+		}
+
+		id, size := lexer.TokenIdentifier(b), 1
+		if d.prefixes[b] && i+1 < len(body) {
+			id, size = ExtendedToken(b, body[i+1]), 2
+		}
+		keyword, found := d.keywords[id]
+		if !found {
+			if size == 2 {
+				return nil, fmt.Errorf("unknown token $%02X%02X", b, body[i+1])
+			}
+			continue
+		}
+
+		if err := flush(i); err != nil {
+			return nil, err
+		}
+		items = append(items, item{keyword: &lexer.Token{ID: id, Category: lexer.KeywordCategory, Literal: keyword}})
+		i += size - 1
+		textStart = i + 1
+
+		if strings.EqualFold(keyword, "REM") {
+			if textStart < len(body) {
+				remark, err := d.decodeText(body[textStart:])
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item{keyword: &lexer.Token{ID: lexer.CommentType, Category: lexer.CommentCategory, Literal: remark}})
+			}
+			return items, nil
+		}
+	}
+	return items, flush(len(body))
+}
+
+// decodeText decodes text in the dialect's character set.
+func (d *decoder) decodeText(b []byte) (string, error) {
+	if d.charset == nil {
+		return string(b), nil
+	}
+	return d.charset.String(string(b))
+}
+
+// addItems adds the tokens of a line's keywords and text, separating them by the spaces in
+// the text, or by a space where a keyword would run into its neighbour.
+func (l *listing) addItems(items []item) {
+	spaces := 1 // After the line number
+	for _, it := range items {
+		if it.keyword != nil {
+			l.add(spaces, *it.keyword)
+			spaces = 0
+			continue
+		}
+
+		text := it.text
+		for len(text) > 0 {
+			r, _ := utf8.DecodeRuneInString(text)
+			if r == ' ' {
+				spaces++
+				text = text[1:]
+				continue
+			}
+			var t lexer.Token
+			t, text = textToken(text)
+			l.add(spaces, t)
+			spaces = 0
+		}
+	}
+}
+
+// add adds a token to the listing's current line, after the given number of spaces, or a
+// single space if the token would otherwise run into the previous one.
+func (l *listing) add(spaces int, t lexer.Token) {
+	if spaces == 0 && l.line.Len() > 0 && needsSpace(l.tokens[len(l.tokens)-1], t) {
+		spaces = 1
+	}
+	l.line.WriteString(strings.Repeat(" ", spaces))
+	l.line.WriteString(listed(t))
+
+	t.Filename = l.filename
+	t.SourceLine = l.lineNo
+	t.SourceColumn = uint(l.line.Len() - 1)
+	if t.ID == lexer.EndOfLineType {
+		t.SourceColumn = uint(l.line.Len() - len(t.Literal))
+	}
+	l.tokens = append(l.tokens, t)
+}
+
+// needsSpace reports whether a keyword would run into the token next to it. Remarks are
+// listed as they are.
+func needsSpace(previous, next lexer.Token) bool {
+	if next.Category == lexer.CommentCategory || next.Category == lexer.TriviaCategory {
+		return false
+	}
+	if previous.Category == lexer.KeywordCategory && isLetter(lastRune(previous.Literal)) {
+		return !strings.ContainsRune("(),:;", firstRune(listed(next)))
+	}
+	if next.Category == lexer.KeywordCategory && isLetter(firstRune(next.Literal)) {
+		last := lastRune(listed(previous))
+		return isWordRune(last) || strings.ContainsRune(`"$%)`, last)
+	}
+	return false
+}
+
+// listed returns the text of a token in the listing.
+func listed(t lexer.Token) string {
+	if t.ID == lexer.StringLiteral && t.Literal == "" {
+		return `"` + fmt.Sprint(t.Value) + `"`
+	}
+	return t.Literal
+}
+
+// textToken returns the first token of text that isn't a keyword, and the text after it.
+func textToken(text string) (lexer.Token, string) {
+	r, size := utf8.DecodeRuneInString(text)
+	switch {
+	case r == '"':
+		end := strings.IndexByte(text[1:], '"')
+		if end < 0 { // BASIC allows a string at the end of a line to be unterminated
+			return lexer.Token{ID: lexer.StringLiteral, Category: lexer.LiteralCategory, Literal: text, Value: text[1:]}, ""
+		}
+		return lexer.Token{ID: lexer.StringLiteral, Category: lexer.LiteralCategory, Value: text[1 : end+1]}, text[end+2:]
+
+	case unicode.IsDigit(r) || (r == '.' && len(text) > 1 && unicode.IsDigit(rune(text[1]))):
+		end, point := 0, false
+		for end < len(text) && (unicode.IsDigit(rune(text[end])) || (text[end] == '.' && !point)) {
+			point = point || text[end] == '.'
+			end++
+		}
+		literal := text[:end]
+		if point {
+			number, _ := strconv.ParseFloat(literal, 64)
+			return lexer.Token{ID: lexer.NumberLiteral, Category: lexer.LiteralCategory, Literal: literal, Value: number}, text[end:]
+		}
+		number, err := strconv.ParseInt(literal, 10, 64)
+		if err != nil {
+			float, _ := strconv.ParseFloat(literal, 64)
+			return lexer.Token{ID: lexer.NumberLiteral, Category: lexer.LiteralCategory, Literal: literal, Value: float}, text[end:]
+		}
+		return lexer.Token{ID: lexer.IntegerLiteral, Category: lexer.LiteralCategory, Literal: literal, Value: number}, text[end:]
+
+	case isLetter(r):
+		end := size
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if !isLetter(r) && !unicode.IsDigit(r) {
+				break
+			}
+			end += size
+		}
+		if end < len(text) && (text[end] == '$' || text[end] == '%') {
+			end++
+		}
+		return lexer.Token{ID: lexer.IdentifierType, Category: lexer.IdentifierCategory, Literal: text[:end]}, text[end:]
+	}
+	return lexer.Token{ID: lexer.NullType, Category: lexer.SymbolCategory, Literal: text[:size]}, text[size:]
+}
+
+func isLetter(r rune) bool {
+	return ('A' <= r && r <= 'Z') || ('a' <= r && r <= 'z')
+}
@@ -0,0 +1,70 @@
+package prg_test
+
+import (
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/jrsteele09/go-lexer/lexer/prg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	source := "10 PRINT \"{clr}HELLO\";A$\n20 IF A<=B THEN PRINT A B:GOTO 10\n30 X=PEEK(53280)AND 15^2\n"
+	encoded, err := prg.Encode(tokenize(t, source), prg.CommodoreBasicV2)
+	require.NoError(t, err)
+
+	listed, err := prg.List(encoded, prg.CommodoreBasicV2)
+	require.NoError(t, err)
+	require.Equal(t, "10 PRINT \"{clr}HELLO\";A$\n20 IF A<=B THEN PRINT A B:GOTO 10\n30 X=PEEK(53280) AND 15↑2\n", listed)
+
+	// Remarks are listed as they are, even with token bytes or a quote in them
+	remark := []byte{0x01, 0x08, 0x0F, 0x08, 0x28, 0x00, 0x8F, ' ', 'A', 0x99, '"', 'B', 0x00, 0x00, 0x00}
+	listed, err = prg.List(remark, prg.CommodoreBasicV2)
+	require.NoError(t, err)
+	require.Equal(t, "40 REM A{lgrn}\"B\n", listed)
+}
+
+func TestDecode(t *testing.T) {
+	encoded, err := prg.Encode(tokenize(t, "10 PRINT \"HI\";1.5:GOTO 10\n"), prg.CommodoreBasicV2)
+	require.NoError(t, err)
+
+	tokens, err := prg.Decode(encoded, prg.CommodoreBasicV2, "program.prg")
+	require.NoError(t, err)
+	require.Len(t, tokens, 10)
+	require.Equal(t, lexer.Token{ID: lexer.LineNumberType, Category: lexer.LiteralCategory, Literal: "10", Value: int64(10), Filename: "program.prg", SourceLine: 1, SourceColumn: 1}, tokens[0])
+	require.Equal(t, lexer.Token{ID: 0x99, Category: lexer.KeywordCategory, Literal: "PRINT", Filename: "program.prg", SourceLine: 1, SourceColumn: 7}, tokens[1])
+	require.Equal(t, "HI", tokens[2].Value)
+	require.Equal(t, uint(12), tokens[2].SourceColumn)
+	require.Equal(t, lexer.SymbolCategory, tokens[3].Category)
+	require.Equal(t, 1.5, tokens[4].Value)
+	require.Equal(t, lexer.TokenIdentifier(0x89), tokens[6].ID)
+	require.Equal(t, lexer.Token{ID: lexer.EndOfLineType, Category: lexer.TriviaCategory, Literal: "\n", Filename: "program.prg", SourceLine: 1, SourceColumn: 25}, tokens[8])
+	require.Equal(t, lexer.EOFType, tokens[9].ID)
+
+	reencoded, err := prg.Encode(tokens, prg.CommodoreBasicV2)
+	require.NoError(t, err)
+	require.Equal(t, encoded, reencoded)
+}
+
+func TestDecodeExtendedTokens(t *testing.T) {
+	dialect := &prg.Dialect{
+		LoadAddress: 0x1C01,
+		Keywords:    map[string]lexer.TokenIdentifier{"PRINT": 0x99, "GOTO": prg.ExtendedToken(0xCE, 0x02), "^": 0xAE, "**": 0xAE},
+	}
+	encoded, err := prg.Encode(tokenize(t, "5 print 1: goto 5\n"), dialect)
+	require.NoError(t, err)
+	listed, err := prg.List(append(encoded[:len(encoded)-3], 0xAE, 0, 0, 0), dialect)
+	require.NoError(t, err)
+	require.Equal(t, "5 PRINT 1:GOTO 5^\n", listed)
+
+	_, err = prg.List([]byte{0x01, 0x1C, 0x07, 0x1C, 0x05, 0x00, 0xCE, 0x05, 0x00, 0x00, 0x00}, dialect)
+	require.ErrorContains(t, err, "prg: [ line: 1] unknown token $CE05")
+}
+
+func TestDecodeErrors(t *testing.T) {
+	_, err := prg.List([]byte{0x01}, prg.CommodoreBasicV2)
+	require.ErrorContains(t, err, "program has no load address")
+
+	_, err = prg.Decode([]byte{0x01, 0x08, 0x07, 0x08, 0x0A, 0x00, 0x99}, prg.CommodoreBasicV2, "program.prg")
+	require.ErrorContains(t, err, "prg: [program.prg line: 1] program is truncated")
+}
@@ -0,0 +1,57 @@
+package prg
+
+import (
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/jrsteele09/go-lexer/lexer/charset"
+	"golang.org/x/text/encoding"
+)
+
+// Dialect describes how a BASIC dialect stores its programs.
+type Dialect struct {
+	Name        string
+	LoadAddress uint16 // Address the program is loaded at, e.g. 0x0801 on the C64
+
+	// Keywords maps the text of each token to its byte, or to an ExtendedToken of two bytes.
+	// The table is compatible with LanguageConfig.Keywords.
+	Keywords map[string]lexer.TokenIdentifier
+
+	Charset   encoding.Encoding // Character set of the program's text
+	UpperCase bool              // Upper case text before encoding it, for character sets without lower case
+}
+
+// ExtendedToken returns the identifier of a two byte token, such as BASIC 7.0's 0xCE 0x02.
+// Its identifier is outside the range of a one byte token, 0x00 to 0xFF.
+func ExtendedToken(prefix, code byte) lexer.TokenIdentifier {
+	return lexer.TokenIdentifier(int16(uint16(prefix)<<8 | uint16(code)))
+}
+
+// tokenBytes appends the bytes of a token identifier to b.
+func tokenBytes(b []byte, id lexer.TokenIdentifier) []byte {
+	if id < 0 || id > 0xFF {
+		b = append(b, byte(uint16(id)>>8))
+	}
+	return append(b, byte(id))
+}
+
+// CommodoreBasicV2 is the BASIC of the Commodore 64 and VIC-20, loaded at 0x0801 as on the C64.
+var CommodoreBasicV2 = &Dialect{
+	Name:        "Commodore BASIC V2",
+	LoadAddress: 0x0801,
+	Keywords: map[string]lexer.TokenIdentifier{
+		"END": 0x80, "FOR": 0x81, "NEXT": 0x82, "DATA": 0x83, "INPUT#": 0x84, "INPUT": 0x85,
+		"DIM": 0x86, "READ": 0x87, "LET": 0x88, "GOTO": 0x89, "RUN": 0x8A, "IF": 0x8B,
+		"RESTORE": 0x8C, "GOSUB": 0x8D, "RETURN": 0x8E, "REM": 0x8F, "STOP": 0x90, "ON": 0x91,
+		"WAIT": 0x92, "LOAD": 0x93, "SAVE": 0x94, "VERIFY": 0x95, "DEF": 0x96, "POKE": 0x97,
+		"PRINT#": 0x98, "PRINT": 0x99, "CONT": 0x9A, "LIST": 0x9B, "CLR": 0x9C, "CMD": 0x9D,
+		"SYS": 0x9E, "OPEN": 0x9F, "CLOSE": 0xA0, "GET": 0xA1, "NEW": 0xA2, "TAB(": 0xA3,
+		"TO": 0xA4, "FN": 0xA5, "SPC(": 0xA6, "THEN": 0xA7, "NOT": 0xA8, "STEP": 0xA9,
+		"+": 0xAA, "-": 0xAB, "*": 0xAC, "/": 0xAD, "↑": 0xAE, "^": 0xAE, "AND": 0xAF, "OR": 0xB0,
+		">": 0xB1, "=": 0xB2, "<": 0xB3, "SGN": 0xB4, "INT": 0xB5, "ABS": 0xB6, "USR": 0xB7,
+		"FRE": 0xB8, "POS": 0xB9, "SQR": 0xBA, "RND": 0xBB, "LOG": 0xBC, "EXP": 0xBD,
+		"COS": 0xBE, "SIN": 0xBF, "TAN": 0xC0, "ATN": 0xC1, "PEEK": 0xC2, "LEN": 0xC3,
+		"STR$": 0xC4, "VAL": 0xC5, "ASC": 0xC6, "CHR$": 0xC7, "LEFT$": 0xC8, "RIGHT$": 0xC9,
+		"MID$": 0xCA, "GO": 0xCB, "π": 0xFF,
+	},
+	Charset:   charset.PETSCIIUpper,
+	UpperCase: true,
+}
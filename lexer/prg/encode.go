@@ -0,0 +1,214 @@
+// Package prg converts between lexed BASIC programs and the tokenized PRG files that
+// Commodore computers and their emulators load. A PRG file starts with the program's load
+// address, followed by its lines: each has the address of the next line, the line number,
+// the line's text with keywords replaced by token bytes, and a 0x00 terminator. Two 0x00
+// bytes end the program. Encode crunches a program into a PRG file, and List and Decode
+// list one back into source.
+package prg
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"golang.org/x/text/encoding"
+)
+
+// MaxLineNumber is the largest line number Commodore BASIC accepts.
+const MaxLineNumber = 63999
+
+// Encode crunches a BASIC program, lexed by Lexer.Tokenize, into a PRG file. Each line must
+// start with its line number, and line numbers must ascend.
+//
+// Keyword, operator and symbol tokens are crunched like the BASIC editor does, replacing the
+// dialect's keywords in their text with token bytes regardless of case, so that "<=" becomes
+// the tokens for '<' and '='. Identifiers, numbers, strings and comments, such as the remark
+// after REM, are copied as text in the dialect's character set. The lexer doesn't keep
+// whitespace, so spaces only separate identifiers and numbers that would otherwise run
+// together.
+func Encode(tokens []lexer.Token, dialect *Dialect) ([]byte, error) {
+	e := newEncoder(dialect)
+	prg := binary.LittleEndian.AppendUint16(nil, dialect.LoadAddress)
+	address := int(dialect.LoadAddress)
+	previous := -1
+
+	for start := 0; start < len(tokens); {
+		end := start
+		for end < len(tokens) && tokens[end].ID != lexer.EndOfLineType && tokens[end].ID != lexer.EOFType {
+			end++
+		}
+		line := tokens[start:end]
+		start = end + 1
+		if len(line) == 0 {
+			continue
+		}
+
+		number, err := lineNumber(line[0])
+		if err != nil {
+			return nil, positioned(line[0], err)
+		}
+		if number <= previous {
+			return nil, positioned(line[0], fmt.Errorf("line number %d doesn't follow %d", number, previous))
+		}
+		previous = number
+
+		body, err := e.encodeLine(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		address += 4 + len(body) + 1
+		if address > 0xFFFF {
+			return nil, positioned(line[0], fmt.Errorf("program is too large to load at $%04X", dialect.LoadAddress))
+		}
+		prg = binary.LittleEndian.AppendUint16(prg, uint16(address))
+		prg = binary.LittleEndian.AppendUint16(prg, uint16(number))
+		prg = append(append(prg, body...), 0)
+	}
+	return append(prg, 0, 0), nil
+}
+
+// lineNumber returns the line number of the first token of a line, an IntegerLiteral or the
+// LineNumberType token of a language with LineNumberLabels.
+func lineNumber(t lexer.Token) (int, error) {
+	if t.ID != lexer.IntegerLiteral && t.ID != lexer.LineNumberType {
+		return 0, fmt.Errorf("line doesn't start with a line number")
+	}
+	number, err := strconv.Atoi(t.Literal)
+	if err != nil || number < 0 || number > MaxLineNumber {
+		return 0, fmt.Errorf("invalid line number %s", t.Literal)
+	}
+	return number, nil
+}
+
+// positioned adds the source position of a token to an error.
+func positioned(t lexer.Token, err error) error {
+	return fmt.Errorf("prg: [%s line: %d] %w", t.Filename, t.SourceLine, err)
+}
+
+type encoder struct {
+	dialect  *Dialect
+	keywords []string // The dialect's keywords, longest first
+	charset  *encoding.Encoder
+}
+
+func newEncoder(dialect *Dialect) *encoder {
+	e := &encoder{dialect: dialect, keywords: make([]string, 0, len(dialect.Keywords))}
+	for keyword := range dialect.Keywords {
+		e.keywords = append(e.keywords, keyword)
+	}
+	sort.Slice(e.keywords, func(i, j int) bool {
+		if len(e.keywords[i]) != len(e.keywords[j]) {
+			return len(e.keywords[i]) > len(e.keywords[j])
+		}
+		return e.keywords[i] < e.keywords[j]
+	})
+	if dialect.Charset != nil {
+		e.charset = dialect.Charset.NewEncoder()
+	}
+	return e
+}
+
+// encodeLine returns the bytes of the tokens of a line after its line number.
+func (e *encoder) encodeLine(tokens []lexer.Token) ([]byte, error) {
+	var body []byte
+	wordEnd := false // The line so far ends with a letter or digit that isn't part of a token byte
+	for _, t := range tokens {
+		var err error
+		switch t.Category {
+		case lexer.KeywordCategory, lexer.OperatorCategory, lexer.SymbolCategory:
+			body, err = e.crunch(body, e.upper(t.Literal))
+			wordEnd = false
+		case lexer.CommentCategory: // A remark, such as the text after REM, is never crunched
+			body, err = e.appendText(body, e.upper(t.Literal))
+			wordEnd = false
+		default:
+			text := t.Literal
+			if t.ID == lexer.StringLiteral {
+				text = `"` + fmt.Sprint(t.Value) + `"`
+			}
+			text = e.upper(text)
+			if wordEnd && isWordRune(firstRune(text)) {
+				body = append(body, ' ')
+			}
+			body, err = e.appendText(body, text)
+			wordEnd = t.ID != lexer.StringLiteral && isWordRune(lastRune(text))
+		}
+		if err != nil {
+			return nil, positioned(t, err)
+		}
+	}
+	return body, nil
+}
+
+// crunch appends text to body, replacing the dialect's keywords with their token bytes.
+func (e *encoder) crunch(body []byte, text string) ([]byte, error) {
+	for len(text) > 0 {
+		if keyword := e.keywordPrefix(text); keyword != "" {
+			body = tokenBytes(body, e.dialect.Keywords[keyword])
+			text = text[len(keyword):]
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(text)
+		var err error
+		if body, err = e.appendText(body, text[:size]); err != nil {
+			return nil, err
+		}
+		text = text[size:]
+	}
+	return body, nil
+}
+
+// keywordPrefix returns the longest keyword that text starts with, ignoring case, or "" if
+// there isn't one.
+func (e *encoder) keywordPrefix(text string) string {
+	for _, keyword := range e.keywords {
+		if len(text) >= len(keyword) && strings.EqualFold(text[:len(keyword)], keyword) {
+			return keyword
+		}
+	}
+	return ""
+}
+
+// appendText appends text to body in the dialect's character set.
+func (e *encoder) appendText(body []byte, text string) ([]byte, error) {
+	if e.charset == nil {
+		return append(body, text...), nil
+	}
+	encoded, err := e.charset.String(text)
+	if err != nil {
+		return nil, err
+	}
+	return append(body, encoded...), nil
+}
+
+// upper upper cases the ASCII letters of text if the dialect's character set has no lower case.
+func (e *encoder) upper(text string) string {
+	if !e.dialect.UpperCase {
+		return text
+	}
+	return strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		return r
+	}, text)
+}
+
+func isWordRune(r rune) bool {
+	return ('A' <= r && r <= 'Z') || ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') || r == '.'
+}
+
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
+
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
@@ -0,0 +1,119 @@
+package prg_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/jrsteele09/go-lexer/lexer/languages"
+	"github.com/jrsteele09/go-lexer/lexer/prg"
+	"github.com/stretchr/testify/require"
+)
+
+// newBasicLexer returns a lexer of a Commodore BASIC like language, with token identifiers
+// of its own rather than the dialect's token bytes.
+func newBasicLexer() *lexer.Lexer {
+	config := lexer.NewLanguage("cbm").
+		Keyword("print", lexer.LastStdLiteral).
+		Keyword("goto", lexer.LastStdLiteral+1).
+		Keyword("if", lexer.LastStdLiteral+2).
+		Keyword("then", lexer.LastStdLiteral+3).
+		Operator("<=", lexer.LastStdLiteral+4).
+		Symbol(';', lexer.LastStdLiteral+5).
+		Symbol(':', lexer.LastStdLiteral+6).
+		Symbol('(', lexer.LastStdLiteral+7).
+		Symbol(')', lexer.LastStdLiteral+8).
+		Symbol('=', lexer.LastStdLiteral+9).
+		Symbol('^', lexer.LastStdLiteral+10).
+		Keyword("and", lexer.LastStdLiteral+11).
+		Keyword("peek", lexer.LastStdLiteral+12).
+		StringDelimiters(`"`).
+		IdentifierTermination("$%").
+		KeywordCase(lexer.CaseInsensitive).
+		IdentifierFallback().
+		MustBuild()
+	return lexer.NewLexer(config)
+}
+
+func tokenize(t *testing.T, source string) []lexer.Token {
+	t.Helper()
+	tokens, err := newBasicLexer().Tokenize(strings.NewReader(source), "program.bas")
+	require.NoError(t, err)
+	return tokens
+}
+
+func TestEncode(t *testing.T) {
+	tokens := tokenize(t, "10 PRINT \"{clr}hello\";a$\n\n20 goto 10\n")
+	encoded, err := prg.Encode(tokens, prg.CommodoreBasicV2)
+	require.NoError(t, err)
+	require.Equal(t, []byte{
+		0x01, 0x08,
+		0x12, 0x08, 0x0A, 0x00, 0x99, '"', 0x93, 'H', 'E', 'L', 'L', 'O', '"', ';', 'A', '$', 0x00,
+		0x1A, 0x08, 0x14, 0x00, 0x89, '1', '0', 0x00,
+		0x00, 0x00,
+	}, encoded)
+}
+
+func TestEncodeCrunchesOperators(t *testing.T) {
+	encoded, err := prg.Encode(tokenize(t, "10 if a<=b then print a b\n"), prg.CommodoreBasicV2)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x8B, 'A', 0xB3, 0xB2, 'B', 0xA7, 0x99, 'A', ' ', 'B', 0x00}, encoded[6:len(encoded)-2])
+}
+
+func TestEncodeRemarks(t *testing.T) {
+	// The text after REM isn't crunched, and keeps its spaces
+	tokens, err := lexer.NewLexer(languages.Basic()).Tokenize(strings.NewReader("10 REM HELLO  PRINT\n20 rem\n"), "program.bas")
+	require.NoError(t, err)
+	encoded, err := prg.Encode(tokens, prg.CommodoreBasicV2)
+	require.NoError(t, err)
+	require.Equal(t, []byte{
+		0x01, 0x08,
+		0x14, 0x08, 0x0A, 0x00, 0x8F, ' ', 'H', 'E', 'L', 'L', 'O', ' ', ' ', 'P', 'R', 'I', 'N', 'T', 0x00,
+		0x1A, 0x08, 0x14, 0x00, 0x8F, 0x00,
+		0x00, 0x00,
+	}, encoded)
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	source := "10 REM HELLO WORLD\n20 PRINT \"HI\";A$:REM SAY HI\n30 IF A%<=2 THEN GOTO 10\n"
+	l := lexer.NewLexer(languages.Basic())
+	tokens, err := l.Tokenize(strings.NewReader(source), "program.bas")
+	require.NoError(t, err)
+	encoded, err := prg.Encode(tokens, prg.CommodoreBasicV2)
+	require.NoError(t, err)
+
+	listed, err := prg.List(encoded, prg.CommodoreBasicV2)
+	require.NoError(t, err)
+	require.Equal(t, source, listed)
+
+	tokens, err = l.Tokenize(strings.NewReader(listed), "program.bas")
+	require.NoError(t, err)
+	reencoded, err := prg.Encode(tokens, prg.CommodoreBasicV2)
+	require.NoError(t, err)
+	require.Equal(t, encoded, reencoded)
+}
+
+func TestEncodeDialect(t *testing.T) {
+	dialect := &prg.Dialect{
+		Name:        "extended",
+		LoadAddress: 0x1C01,
+		Keywords:    map[string]lexer.TokenIdentifier{"PRINT": 0x99, "GOTO": prg.ExtendedToken(0xCE, 0x02)},
+	}
+	encoded, err := prg.Encode(tokenize(t, "5 print 1: goto 5\n"), dialect)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x01, 0x1C, 0x0C, 0x1C, 0x05, 0x00, 0x99, '1', ':', 0xCE, 0x02, '5', 0x00, 0x00, 0x00}, encoded)
+}
+
+func TestEncodeErrors(t *testing.T) {
+	_, err := prg.Encode(tokenize(t, "print 1\n"), prg.CommodoreBasicV2)
+	require.ErrorContains(t, err, "prg: [program.bas line: 1] line doesn't start with a line number")
+
+	_, err = prg.Encode(tokenize(t, "20 print\n10 print\n"), prg.CommodoreBasicV2)
+	require.ErrorContains(t, err, "[program.bas line: 2] line number 10 doesn't follow 20")
+
+	_, err = prg.Encode(tokenize(t, "64000 print\n"), prg.CommodoreBasicV2)
+	require.ErrorContains(t, err, "invalid line number 64000")
+
+	_, err = prg.Encode(tokenize(t, "10 print \"~\"\n"), prg.CommodoreBasicV2)
+	require.ErrorContains(t, err, "can't be encoded")
+}
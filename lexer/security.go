@@ -0,0 +1,218 @@
+package lexer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SecurityCheck selects checks for source that displays differently to how it's lexed, such
+// as Trojan Source attacks (CVE-2021-42574). Checks are combined with |.
+type SecurityCheck uint8
+
+const (
+	// BidiCheck reports bidirectional control characters, which can reorder how comments and
+	// strings are displayed so that the code reads differently to how it runs.
+	BidiCheck SecurityCheck = 1 << iota
+
+	// InvisibleCheck reports zero-width and other invisible characters in identifiers, which
+	// make distinct identifiers look the same. While it's enabled, identifiers take in
+	// invisible characters, so that they're reported rather than failing the lex.
+	InvisibleCheck
+
+	// MixedScriptCheck reports identifiers mixing letters of different scripts, such as a
+	// Cyrillic 'а' in an otherwise Latin identifier.
+	MixedScriptCheck
+
+	// AllSecurityChecks enables every check.
+	AllSecurityChecks = BidiCheck | InvisibleCheck | MixedScriptCheck
+)
+
+// Warning is a finding of a security check, positioned like a Token.
+type Warning struct {
+	Check        SecurityCheck // The check that made the finding
+	Message      string
+	Filename     string
+	SourceLine   uint // The line in the source text of the finding
+	SourceColumn uint // The column in the source text of the finding
+}
+
+// Error returns the warning with its position, so that warnings can be returned as errors.
+func (w Warning) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", w.Filename, w.SourceLine, w.SourceColumn, w.Message)
+}
+
+// WithSecurityChecks enables security checks, whose findings are available from
+// Lexer.Warnings. Warnings don't fail the lex unless WithWarningsAsErrors is also used.
+func WithSecurityChecks(checks SecurityCheck) Option {
+	return func(l *Lexer) {
+		l.securityChecks = checks
+		l.tokenCreator.invisibleIdentifiers = checks&InvisibleCheck != 0
+	}
+}
+
+// WithWarningsAsErrors makes the first warning fail the lex, returning the Warning as the error.
+func WithWarningsAsErrors() Option {
+	return func(l *Lexer) {
+		l.warningsAsErrors = true
+	}
+}
+
+// Warnings returns the warnings found since the last call to Tokenize, or since the Lexer was
+// created for TokenizeLine.
+func (l *Lexer) Warnings() []Warning {
+	return l.warnings
+}
+
+// warn records a warning, returning it as an error if warnings fail the lex.
+func (l *Lexer) warn(check SecurityCheck, filename string, lineNo uint, column int, format string, args ...any) error {
+	w := Warning{
+		Check:        check,
+		Message:      fmt.Sprintf(format, args...),
+		Filename:     filename,
+		SourceLine:   lineNo,
+		SourceColumn: uint(column),
+	}
+	l.warnings = append(l.warnings, w)
+	if l.warningsAsErrors {
+		return w
+	}
+	return nil
+}
+
+// checkLine reports the bidirectional control characters of a line.
+func (l *Lexer) checkLine(line string, filename string, lineNo uint) error {
+	if l.securityChecks&BidiCheck == 0 {
+		return nil
+	}
+	for i, r := range line {
+		if unicode.Is(unicode.Bidi_Control, r) {
+			if err := l.warn(BidiCheck, filename, lineNo, i, "bidirectional control character %U can reorder how the source is displayed", r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkIdentifier reports invisible characters and mixed scripts in an identifier token.
+func (l *Lexer) checkIdentifier(token Token) error {
+	if token.Category != IdentifierCategory && token.Category != KeywordCategory {
+		return nil
+	}
+	literal := token.Literal
+	if l.securityChecks&InvisibleCheck != 0 {
+		for _, r := range literal {
+			if isInvisible(r) {
+				if err := l.warn(InvisibleCheck, token.Filename, token.SourceLine, int(token.SourceColumn), "identifier %q contains invisible character %U", literal, r); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	if l.securityChecks&MixedScriptCheck != 0 {
+		if scripts := identifierScripts(literal); !isSingleScript(scripts) {
+			return l.warn(MixedScriptCheck, token.Filename, token.SourceLine, int(token.SourceColumn), "identifier %q mixes %s scripts", literal, strings.Join(scripts, " and "))
+		}
+	}
+	return nil
+}
+
+// invisibleRunes are the invisible characters that aren't format characters (Cf).
+var invisibleRunes = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x034f, Hi: 0x034f, Stride: 1}, // Combining grapheme joiner
+		{Lo: 0x115f, Hi: 0x1160, Stride: 1}, // Hangul fillers
+		{Lo: 0x17b4, Hi: 0x17b5, Stride: 1}, // Khmer inherent vowels
+		{Lo: 0x180b, Hi: 0x180f, Stride: 1}, // Mongolian variation selectors
+		{Lo: 0x3164, Hi: 0x3164, Stride: 1}, // Hangul filler
+		{Lo: 0xfe00, Hi: 0xfe0f, Stride: 1}, // Variation selectors
+		{Lo: 0xffa0, Hi: 0xffa0, Stride: 1}, // Halfwidth Hangul filler
+	},
+	R32: []unicode.Range32{
+		{Lo: 0xe0100, Hi: 0xe01ef, Stride: 1}, // Variation selectors supplement
+	},
+}
+
+// isInvisible reports whether r is a zero-width or otherwise invisible character.
+func isInvisible(r rune) bool {
+	return r >= utf8.RuneSelf && unicode.In(r, unicode.Cf, invisibleRunes)
+}
+
+// identifierScripts returns the sorted names of the scripts of an identifier's letters.
+// Common and inherited runes such as digits and combining marks don't belong to a script.
+func identifierScripts(identifier string) []string {
+	var scripts []string
+	for _, r := range identifier {
+		if script := runeScript(r); script != "" && !containsString(scripts, script) {
+			scripts = append(scripts, script)
+		}
+	}
+	sort.Strings(scripts)
+	return scripts
+}
+
+// commonScripts are checked first by runeScript, as most identifiers are written in them.
+var commonScripts = []string{"Latin", "Greek", "Cyrillic", "Han", "Arabic", "Hebrew", "Hiragana", "Katakana", "Hangul"}
+
+// runeScript returns the name of the script of r, or "" for common and inherited runes.
+func runeScript(r rune) string {
+	if r < utf8.RuneSelf {
+		if ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') {
+			return "Latin"
+		}
+		return ""
+	}
+	if unicode.In(r, unicode.Common, unicode.Inherited) {
+		return ""
+	}
+	for _, script := range commonScripts {
+		if unicode.Is(unicode.Scripts[script], r) {
+			return script
+		}
+	}
+	for script, table := range unicode.Scripts {
+		if unicode.Is(table, r) {
+			return script
+		}
+	}
+	return ""
+}
+
+// cjkScripts are the combinations of scripts written together, following Unicode Technical
+// Standard #39.
+var cjkScripts = [][]string{
+	{"Han", "Hiragana", "Katakana"},
+	{"Han", "Hangul"},
+	{"Han", "Bopomofo"},
+}
+
+// isSingleScript reports whether the scripts of an identifier, as returned by
+// identifierScripts, are a single script or a combination written together.
+func isSingleScript(scripts []string) bool {
+	if len(scripts) <= 1 {
+		return true
+	}
+next:
+	for _, combination := range cjkScripts {
+		for _, script := range scripts {
+			if !containsString(combination, script) {
+				continue next
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
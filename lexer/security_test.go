@@ -0,0 +1,96 @@
+package lexer_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBidiCheck(t *testing.T) {
+	// The classic Trojan Source comment: the override hides that "admin" ends the comment early
+	source := "a = 1\nb = \"x\u202e\" // \u2066check\u2069 admin\n"
+
+	l := lexer.NewLexer(NewBasicLanguage(), lexer.WithSecurityChecks(lexer.AllSecurityChecks))
+	_, err := l.Tokenize(strings.NewReader(source), "testfile")
+	require.NoError(t, err)
+
+	warnings := l.Warnings()
+	require.Len(t, warnings, 3)
+	require.Equal(t, lexer.BidiCheck, warnings[0].Check)
+	require.Equal(t, uint(2), warnings[0].SourceLine)
+	require.Equal(t, uint(6), warnings[0].SourceColumn)
+	require.Equal(t, "testfile:2:6: bidirectional control character U+202E can reorder how the source is displayed", warnings[0].Error())
+	require.Equal(t, uint(14), warnings[1].SourceColumn)
+
+	_, err = l.Tokenize(strings.NewReader("a = 1\n"), "testfile")
+	require.NoError(t, err)
+	require.Empty(t, l.Warnings(), "Tokenize clears the warnings")
+}
+
+func TestSecurityChecksAreOptIn(t *testing.T) {
+	l := NewBasicLexer()
+	_, err := l.Tokenize(strings.NewReader("a = \"\u202e\"\n"), "testfile")
+	require.NoError(t, err)
+	require.Empty(t, l.Warnings())
+}
+
+func TestInvisibleCheck(t *testing.T) {
+	for _, profile := range []lexer.IdentifierProfile{lexer.DefaultIdentifiers, lexer.UAX31Identifiers} {
+		language := lexer.NewLanguage("invisible").
+			Symbol('=', EqualsSymbolToken).
+			IdentifierProfile(profile).
+			IdentifierFallback().
+			MustBuild()
+
+		// Identifiers take in invisible characters only while they're checked
+		_, err := lexer.NewLexer(language).TokenizeLine("ab\u200bcd = 1", "testfile", 3)
+		require.ErrorContains(t, err, "unknown character")
+
+		l := lexer.NewLexer(language, lexer.WithSecurityChecks(lexer.AllSecurityChecks))
+		tokens, err := l.TokenizeLine("ab\u200bcd = \u2060x", "testfile", 3)
+		require.NoError(t, err)
+		require.Equal(t, "ab\u200bcd", tokens[0].Literal)
+		require.Equal(t, []lexer.Warning{{
+			Check:        lexer.InvisibleCheck,
+			Message:      `identifier "ab\u200bcd" contains invisible character U+200B`,
+			Filename:     "testfile",
+			SourceLine:   3,
+			SourceColumn: 7, // Tokens are positioned where they end
+		}, {
+			Check:        lexer.InvisibleCheck,
+			Message:      `identifier "\u2060x" contains invisible character U+2060`,
+			Filename:     "testfile",
+			SourceLine:   3,
+			SourceColumn: 14,
+		}}, l.Warnings())
+	}
+}
+
+func TestMixedScriptCheck(t *testing.T) {
+	language := lexer.NewLanguage("scripts").
+		Symbol('=', EqualsSymbolToken).
+		IdentifierProfile(lexer.UAX31Identifiers).
+		IdentifierFallback().
+		MustBuild()
+
+	// The 'а' of the first "pаypal" is Cyrillic
+	l := lexer.NewLexer(language, lexer.WithSecurityChecks(lexer.MixedScriptCheck))
+	_, err := l.TokenizeLine("pаypal = paypal = пример = 変数カウンタ = x1_ü", "testfile", 0)
+	require.NoError(t, err)
+	require.Len(t, l.Warnings(), 1)
+	require.Equal(t, `identifier "pаypal" mixes Cyrillic and Latin scripts`, l.Warnings()[0].Message)
+	require.Equal(t, uint(7), l.Warnings()[0].SourceColumn)
+}
+
+func TestWarningsAsErrors(t *testing.T) {
+	l := lexer.NewLexer(NewBasicLanguage(), lexer.WithSecurityChecks(lexer.BidiCheck), lexer.WithWarningsAsErrors())
+	_, err := l.Tokenize(strings.NewReader("a = 1\n// \u202e\n"), "testfile")
+	require.Error(t, err)
+
+	var warning lexer.Warning
+	require.True(t, errors.As(err, &warning))
+	require.Equal(t, uint(2), warning.SourceLine)
+}
@@ -0,0 +1,337 @@
+package shellwords
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+)
+
+// scanner lexes command lines, tracking the line and column at which each token ends.
+type scanner struct {
+	source    string
+	filename  string
+	pos       int
+	tokens    []lexer.Token
+	lineStart int // Index of the first token of the current line in tokens
+
+	line      uint // Line of the source at offset located
+	lineBegin int  // Offset of the start of that line
+	located   int  // Offset up to which line and lineBegin have been counted
+}
+
+func scan(source string, filename string, lineNo uint) ([]lexer.Token, error) {
+	s := &scanner{source: source, filename: filename, line: lineNo}
+	if err := s.scan(); err != nil {
+		line, _ := s.locate(s.pos)
+		return nil, fmt.Errorf("[%s line: %d] %w", filename, line, err)
+	}
+	s.tokens = append(s.tokens, lexer.Token{ID: lexer.EOFType, Category: lexer.TriviaCategory, Filename: filename})
+	return s.tokens, nil
+}
+
+func (s *scanner) scan() error {
+	for s.pos < len(s.source) {
+		c := s.source[s.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			s.pos++
+		case s.continuesLine():
+			s.pos += 2
+		case c == '\n':
+			if len(s.tokens) > s.lineStart {
+				s.emit(lexer.TriviaCategory, lexer.EndOfLineType, "\n", nil)
+			}
+			s.pos++
+			s.lineStart = len(s.tokens)
+		case c == '#':
+			if end := strings.IndexByte(s.source[s.pos:], '\n'); end >= 0 {
+				s.pos += end
+			} else {
+				s.pos = len(s.source)
+			}
+		case isOperatorStart(c):
+			s.operator()
+		default:
+			if err := s.word(); err != nil {
+				return err
+			}
+		}
+	}
+	if len(s.tokens) > s.lineStart {
+		s.emit(lexer.TriviaCategory, lexer.EndOfLineType, "\n", nil)
+	}
+	return nil
+}
+
+// emit adds a token ending at the current offset, or an end of line token at its line break.
+func (s *scanner) emit(category lexer.Category, id lexer.TokenIdentifier, literal string, value any) {
+	line, column := s.locate(s.pos)
+	s.tokens = append(s.tokens, lexer.Token{
+		ID: id, Category: category, Literal: literal, Value: value,
+		Filename: s.filename, SourceLine: line, SourceColumn: uint(column),
+	})
+}
+
+// locate returns the line and column of offset, which mustn't be before an offset already
+// located.
+func (s *scanner) locate(offset int) (uint, int) {
+	for ; s.located < offset; s.located++ {
+		if s.source[s.located] == '\n' {
+			s.line++
+			s.lineBegin = s.located + 1
+		}
+	}
+	return s.line, offset - s.lineBegin
+}
+
+// continuesLine reports whether the source continues with a backslash escaping a line break.
+func (s *scanner) continuesLine() bool {
+	return strings.HasPrefix(s.source[s.pos:], "\\\n")
+}
+
+func isOperatorStart(c byte) bool {
+	return strings.IndexByte("|&;()<>", c) >= 0
+}
+
+func (s *scanner) operator() {
+	for _, op := range operators {
+		if strings.HasPrefix(s.source[s.pos:], op.text) {
+			s.pos += len(op.text)
+			s.emit(lexer.OperatorCategory, op.id, op.text, op.text)
+			return
+		}
+	}
+}
+
+// word reads a word up to the blank, line break or operator that ends it.
+func (s *scanner) word() error {
+	start := s.pos
+	w := wordBuilder{}
+	for s.pos < len(s.source) {
+		c := s.source[s.pos]
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' || isOperatorStart(c) {
+			break
+		}
+		switch c {
+		case '\\':
+			if s.continuesLine() {
+				s.pos += 2
+			} else if s.pos+1 < len(s.source) {
+				_, size := utf8.DecodeRuneInString(s.source[s.pos+1:])
+				w.literal(s.source[s.pos+1:s.pos+1+size], true)
+				s.pos += 1 + size
+			} else {
+				w.literal("\\", false)
+				s.pos++
+			}
+		case '\'':
+			end := strings.IndexByte(s.source[s.pos+1:], '\'')
+			if end < 0 {
+				return fmt.Errorf("unterminated single quote")
+			}
+			w.literal(s.source[s.pos+1:s.pos+1+end], true)
+			s.pos += end + 2
+		case '"':
+			if err := s.doubleQuoted(&w); err != nil {
+				return err
+			}
+		case '$':
+			if err := s.dollar(&w, false); err != nil {
+				return err
+			}
+		case '`':
+			if err := s.backquoted(&w, false); err != nil {
+				return err
+			}
+		default:
+			w.literal(s.source[s.pos:s.pos+1], false)
+			s.pos++
+		}
+	}
+
+	literal := s.source[start:s.pos]
+	if s.pos < len(s.source) && (s.source[s.pos] == '<' || s.source[s.pos] == '>') {
+		if n, err := strconv.ParseInt(literal, 10, 64); err == nil && isDigits(literal) {
+			s.emit(lexer.LiteralCategory, IONumber, literal, n)
+			return nil
+		}
+	}
+	s.emit(lexer.LiteralCategory, Word, literal, w.parts)
+	return nil
+}
+
+// doubleQuoted reads a double quoted string, from its opening quote.
+func (s *scanner) doubleQuoted(w *wordBuilder) error {
+	s.pos++
+	w.literal("", true) // Even "" is a word
+	for s.pos < len(s.source) {
+		switch c := s.source[s.pos]; c {
+		case '"':
+			s.pos++
+			return nil
+		case '\\':
+			switch {
+			case s.continuesLine():
+				s.pos += 2
+			case s.pos+1 < len(s.source) && strings.IndexByte("$`\"\\", s.source[s.pos+1]) >= 0:
+				w.literal(s.source[s.pos+1:s.pos+2], true)
+				s.pos += 2
+			default:
+				w.literal("\\", true)
+				s.pos++
+			}
+		case '$':
+			if err := s.dollar(w, true); err != nil {
+				return err
+			}
+		case '`':
+			if err := s.backquoted(w, true); err != nil {
+				return err
+			}
+		default:
+			w.literal(s.source[s.pos:s.pos+1], true)
+			s.pos++
+		}
+	}
+	return fmt.Errorf("unterminated double quote")
+}
+
+// dollar reads an expansion starting with '$', or a literal '$' if none follows it.
+func (s *scanner) dollar(w *wordBuilder, quoted bool) error {
+	rest := s.source[s.pos+1:]
+	switch {
+	case strings.HasPrefix(rest, "(("):
+		end, err := closing(rest[2:], "((", "))")
+		if err != nil {
+			return err
+		}
+		w.expansion(Arithmetic, rest[2:2+end], quoted)
+		s.pos += 1 + 2 + end + 2
+	case strings.HasPrefix(rest, "("):
+		end, err := closing(rest[1:], "(", ")")
+		if err != nil {
+			return err
+		}
+		w.expansion(Command, rest[1:1+end], quoted)
+		s.pos += 1 + 1 + end + 1
+	case strings.HasPrefix(rest, "{"):
+		end, err := closing(rest[1:], "{", "}")
+		if err != nil {
+			return err
+		}
+		w.expansion(Variable, rest[1:1+end], quoted)
+		s.pos += 1 + 1 + end + 1
+	case len(rest) > 0 && isNameStart(rest[0]):
+		n := 1
+		for n < len(rest) && (isNameStart(rest[n]) || isDigit(rest[n])) {
+			n++
+		}
+		w.expansion(Variable, rest[:n], quoted)
+		s.pos += 1 + n
+	case len(rest) > 0 && (isDigit(rest[0]) || strings.IndexByte("?#@*$!-", rest[0]) >= 0):
+		w.expansion(Variable, rest[:1], quoted)
+		s.pos += 2
+	default:
+		w.literal("$", quoted)
+		s.pos++
+	}
+	return nil
+}
+
+// closing returns the offset in s of the delimiter that closes an expansion opened by
+// open, skipping nested expansions and quoted text.
+func closing(s string, open, close string) (int, error) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case depth == 0 && strings.HasPrefix(s[i:], close):
+			return i, nil
+		case s[i] == open[0]:
+			depth++
+		case s[i] == close[0]:
+			depth--
+		case s[i] == '\\':
+			i++
+		case s[i] == '\'' || s[i] == '"':
+			end := closingQuote(s[i+1:], s[i])
+			if end < 0 {
+				return 0, fmt.Errorf("unterminated $%s", open)
+			}
+			i += end + 1
+		}
+	}
+	return 0, fmt.Errorf("unterminated $%s", open)
+}
+
+// closingQuote returns the offset in s of the quote that closes quoted text, or -1 if
+// there's none. Backslashes escape characters in double quotes, but not in single quotes.
+func closingQuote(s string, quote byte) int {
+	if quote == '\'' {
+		return strings.IndexByte(s, quote)
+	}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// backquoted reads an old style `command` substitution, from its opening backquote.
+func (s *scanner) backquoted(w *wordBuilder, quoted bool) error {
+	var command strings.Builder
+	for i := s.pos + 1; i < len(s.source); i++ {
+		switch c := s.source[i]; {
+		case c == '`':
+			w.expansion(Command, command.String(), quoted)
+			s.pos = i + 1
+			return nil
+		case c == '\\' && i+1 < len(s.source) && strings.IndexByte("$`\\", s.source[i+1]) >= 0:
+			i++
+			command.WriteByte(s.source[i])
+		default:
+			command.WriteByte(c)
+		}
+	}
+	return fmt.Errorf("unterminated backquote")
+}
+
+// wordBuilder collects the parts of a word, joining adjacent literal text quoted alike.
+type wordBuilder struct {
+	parts []Part
+}
+
+func (w *wordBuilder) literal(text string, quoted bool) {
+	if n := len(w.parts); n > 0 && w.parts[n-1].Kind == Literal && w.parts[n-1].Quoted == quoted {
+		w.parts[n-1].Text += text
+		return
+	}
+	w.parts = append(w.parts, Part{Kind: Literal, Text: text, Quoted: quoted})
+}
+
+func (w *wordBuilder) expansion(kind PartKind, text string, quoted bool) {
+	w.parts = append(w.parts, Part{Kind: kind, Text: text, Quoted: quoted})
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,146 @@
+// Package shellwords lexes command lines with the quoting rules of the POSIX shell, e.g.
+// `grep -n "$PATTERN" *.go | sort > 'out file'`. Unlike stringsplitter, quotes may appear
+// anywhere in a word, so a'b c'd is the single word "ab cd":
+//
+//   - single quotes keep everything up to the next single quote literally
+//   - double quotes keep their text, but expand $ and backquotes, and a backslash escapes
+//     $ ` " \ and the line break within them
+//   - outside quotes a backslash escapes any rune, and a backslash before a line break
+//     continues the line
+//   - a '#' starting a word comments out the rest of the line
+//
+// Each word is a Word token whose Value is its Parts: literal text, with its quotes and
+// escapes removed, and the variables, command substitutions and arithmetic expansions that
+// a shell would expand. Control and redirection operators, such as "|" and ">>", are
+// OperatorCategory tokens. Here-document bodies aren't read, so their lines are lexed as
+// commands, and neither tilde expansion nor globbing is done.
+package shellwords
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+)
+
+// Tokens of command lines.
+const (
+	Word     lexer.TokenIdentifier = iota + lexer.LastStdLiteral // A word, see Part
+	IONumber                                                     // The file descriptor of a redirection, e.g. the 2 of "2>&1"
+
+	Pipe         // |
+	Or           // ||
+	Background   // &
+	And          // &&
+	Semicolon    // ;
+	CaseBreak    // ;;
+	LeftParen    // (
+	RightParen   // )
+	RedirectIn   // <
+	RedirectOut  // >
+	Append       // >>
+	HereDoc      // <<
+	HereDocStrip // <<-
+	DupIn        // <&
+	DupOut       // >&
+	ReadWrite    // <>
+	Clobber      // >|
+)
+
+// operators are the control and redirection operators, longest first so that the first
+// match is the longest.
+var operators = []struct {
+	text string
+	id   lexer.TokenIdentifier
+}{
+	{"<<-", HereDocStrip},
+	{"||", Or}, {"&&", And}, {";;", CaseBreak}, {">>", Append}, {"<<", HereDoc},
+	{"<&", DupIn}, {">&", DupOut}, {"<>", ReadWrite}, {">|", Clobber},
+	{"|", Pipe}, {"&", Background}, {";", Semicolon}, {"(", LeftParen}, {")", RightParen},
+	{"<", RedirectIn}, {">", RedirectOut},
+}
+
+// TokenNames are the names of the tokens of command lines, for lexer.LanguageConfig's
+// TokenNames, e.g. "Pipe".
+var TokenNames = map[lexer.TokenIdentifier]string{
+	Word: "Word", IONumber: "IONumber", Pipe: "Pipe", Or: "Or", Background: "Background", And: "And",
+	Semicolon: "Semicolon", CaseBreak: "CaseBreak", LeftParen: "LeftParen", RightParen: "RightParen",
+	RedirectIn: "RedirectIn", RedirectOut: "RedirectOut", Append: "Append", HereDoc: "HereDoc",
+	HereDocStrip: "HereDocStrip", DupIn: "DupIn", DupOut: "DupOut", ReadWrite: "ReadWrite", Clobber: "Clobber",
+}
+
+// PartKind is the kind of a Part of a word.
+type PartKind int
+
+const (
+	Literal    PartKind = iota // Text, with its quotes and escapes removed
+	Variable                   // $NAME, a special parameter such as $? or $1, or ${...}, whose Text is the name or the braces' contents
+	Command                    // $(...) or `...`, whose Text is the command
+	Arithmetic                 // $((...)), whose Text is the expression
+)
+
+// Part is a piece of a word: literal text or an expansion.
+type Part struct {
+	Kind   PartKind
+	Text   string
+	Quoted bool // Whether the part was quoted, so a shell wouldn't split or glob it
+}
+
+// Tokenize reads command lines from r and returns their tokens. Each line with tokens ends
+// with an EndOfLineType token, and an EOFType token ends the input. Quotes, expansions and
+// escaped line breaks may span lines; a token's SourceLine and SourceColumn give the line
+// and byte offset at which it ends. Unterminated quotes and expansions are errors.
+func Tokenize(r io.Reader, filename string) ([]lexer.Token, error) {
+	source, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return scan(string(source), filename, 1)
+}
+
+// TokenizeLine returns the tokens of a single command line, as Tokenize does but without
+// the EOFType token.
+func TokenizeLine(line string, filename string, lineNo uint) ([]lexer.Token, error) {
+	tokens, err := scan(line, filename, lineNo)
+	if err != nil {
+		return nil, err
+	}
+	return tokens[:len(tokens)-1], nil
+}
+
+// Split splits a simple command line into its words, e.g. for exec.Command, expanding each
+// expansion with expand, or to nothing if expand is nil. An expansion's result isn't split
+// into further words. Operators, such as pipes and redirections, are errors.
+func Split(line string, expand func(Part) string) ([]string, error) {
+	tokens, err := scan(line, "", 1)
+	if err != nil {
+		return nil, err
+	}
+	var words []string
+	for _, t := range tokens {
+		switch t.ID {
+		case Word:
+			words = append(words, Expand(t.Value.([]Part), expand))
+		case lexer.EndOfLineType, lexer.EOFType:
+		default:
+			return nil, fmt.Errorf("unexpected %s in a simple command", t.Literal)
+		}
+	}
+	return words, nil
+}
+
+// Expand joins the parts of a word, replacing each expansion with the result of expand, or
+// with nothing if expand is nil.
+func Expand(parts []Part, expand func(Part) string) string {
+	var b strings.Builder
+	for _, p := range parts {
+		switch {
+		case p.Kind == Literal:
+			b.WriteString(p.Text)
+		case expand != nil:
+			b.WriteString(expand(p))
+		}
+	}
+	return b.String()
+}
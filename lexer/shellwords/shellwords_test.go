@@ -0,0 +1,135 @@
+package shellwords_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/jrsteele09/go-lexer/lexer/shellwords"
+	"github.com/stretchr/testify/require"
+)
+
+func tokenIDs(tokens []lexer.Token) []lexer.TokenIdentifier {
+	ids := make([]lexer.TokenIdentifier, len(tokens))
+	for i, t := range tokens {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+func parts(t lexer.Token) []shellwords.Part {
+	return t.Value.([]shellwords.Part)
+}
+
+func TestQuoting(t *testing.T) {
+	tokens, err := shellwords.TokenizeLine(`a'b c'd "x \"y\" \q" \  e\$ '' "" it\'s`, "test.sh", 1)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{
+		shellwords.Word, shellwords.Word, shellwords.Word, shellwords.Word, shellwords.Word, shellwords.Word, shellwords.Word,
+		lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+
+	var words []string
+	for _, token := range tokens[:len(tokens)-1] {
+		words = append(words, shellwords.Expand(parts(token), nil))
+	}
+	require.Equal(t, []string{"ab cd", `x "y" \q`, " ", "e$", "", "", "it's"}, words)
+	require.Equal(t, `a'b c'd`, tokens[0].Literal)
+	require.Equal(t, []shellwords.Part{
+		{Kind: shellwords.Literal, Text: "a"},
+		{Kind: shellwords.Literal, Text: "b c", Quoted: true},
+		{Kind: shellwords.Literal, Text: "d"},
+	}, parts(tokens[0]))
+	require.Equal(t, []shellwords.Part{{Kind: shellwords.Literal, Text: "", Quoted: true}}, parts(tokens[4]))
+}
+
+func TestExpansions(t *testing.T) {
+	tokens, err := shellwords.TokenizeLine(`$HOME/bin "${USER:-me}'s $1" $(ls "a b" $(pwd)) $((1+(2*3))) `+"`date`"+` $ $?`, "test.sh", 1)
+	require.NoError(t, err)
+	require.Len(t, tokens, 8)
+
+	require.Equal(t, []shellwords.Part{
+		{Kind: shellwords.Variable, Text: "HOME"},
+		{Kind: shellwords.Literal, Text: "/bin"},
+	}, parts(tokens[0]))
+	require.Equal(t, []shellwords.Part{
+		{Kind: shellwords.Literal, Text: "", Quoted: true},
+		{Kind: shellwords.Variable, Text: "USER:-me", Quoted: true},
+		{Kind: shellwords.Literal, Text: "'s ", Quoted: true},
+		{Kind: shellwords.Variable, Text: "1", Quoted: true},
+	}, parts(tokens[1]))
+	require.Equal(t, []shellwords.Part{{Kind: shellwords.Command, Text: `ls "a b" $(pwd)`}}, parts(tokens[2]))
+	require.Equal(t, []shellwords.Part{{Kind: shellwords.Arithmetic, Text: "1+(2*3)"}}, parts(tokens[3]))
+	require.Equal(t, []shellwords.Part{{Kind: shellwords.Command, Text: "date"}}, parts(tokens[4]))
+	require.Equal(t, []shellwords.Part{{Kind: shellwords.Literal, Text: "$"}}, parts(tokens[5]))
+	require.Equal(t, []shellwords.Part{{Kind: shellwords.Variable, Text: "?"}}, parts(tokens[6]))
+
+	env := map[string]string{"HOME": "/home/me", "USER": "ann", "1": "x"}
+	expand := func(p shellwords.Part) string { return env[strings.TrimSuffix(p.Text, ":-me")] }
+	require.Equal(t, "/home/me/bin", shellwords.Expand(parts(tokens[0]), expand))
+	require.Equal(t, "ann's x", shellwords.Expand(parts(tokens[1]), expand))
+
+	tokens, err = shellwords.TokenizeLine(`$(echo "a\")") b`, "test.sh", 1)
+	require.NoError(t, err)
+	require.Equal(t, []shellwords.Part{{Kind: shellwords.Command, Text: `echo "a\")"`}}, parts(tokens[0]))
+	require.Equal(t, "b", tokens[1].Literal)
+}
+
+func TestOperators(t *testing.T) {
+	tokens, err := shellwords.TokenizeLine("cat<in|sort -r 2>&1 >>out&&echo ok||(exit 1);a#b # comment", "test.sh", 1)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{
+		shellwords.Word, shellwords.RedirectIn, shellwords.Word, shellwords.Pipe, shellwords.Word, shellwords.Word,
+		shellwords.IONumber, shellwords.DupOut, shellwords.Word, shellwords.Append, shellwords.Word, shellwords.And,
+		shellwords.Word, shellwords.Word, shellwords.Or, shellwords.LeftParen, shellwords.Word, shellwords.Word,
+		shellwords.RightParen, shellwords.Semicolon, shellwords.Word, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+	require.Equal(t, int64(2), tokens[6].Value)
+	require.Equal(t, lexer.OperatorCategory, tokens[7].Category)
+	require.Equal(t, "a#b", tokens[20].Literal)
+}
+
+func TestTokenizeLines(t *testing.T) {
+	source := "echo 'a\nb' \\\n  c\n\n# comment\nls\n"
+	tokens, err := shellwords.Tokenize(strings.NewReader(source), "test.sh")
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{
+		shellwords.Word, shellwords.Word, shellwords.Word, lexer.EndOfLineType,
+		shellwords.Word, lexer.EndOfLineType,
+		lexer.EOFType,
+	}, tokenIDs(tokens))
+	require.Equal(t, "a\nb", shellwords.Expand(parts(tokens[1]), nil))
+	require.Equal(t, uint(2), tokens[1].SourceLine)
+	require.Equal(t, uint(2), tokens[1].SourceColumn)
+	require.Equal(t, uint(3), tokens[2].SourceLine)
+	require.Equal(t, uint(3), tokens[2].SourceColumn)
+	require.Equal(t, uint(6), tokens[4].SourceLine)
+
+	// The last line ends alike with or without a line break
+	for _, source := range []string{"ls\n", "ls"} {
+		tokens, err := shellwords.Tokenize(strings.NewReader(source), "test.sh")
+		require.NoError(t, err)
+		require.Equal(t, "\n", tokens[1].Literal, source)
+	}
+
+	for source, message := range map[string]string{
+		"echo\n'abc":    "[test.sh line: 2] unterminated single quote",
+		`echo "abc`:     "unterminated double quote",
+		"echo `abc":     "unterminated backquote",
+		`echo ${abc`:    "unterminated ${",
+		`echo $(ls ")"`: "unterminated $(",
+		`echo $((1+2)`:  "unterminated $((",
+	} {
+		_, err := shellwords.Tokenize(strings.NewReader(source), "test.sh")
+		require.ErrorContains(t, err, message, source)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	words, err := shellwords.Split(`git commit -m "fix $ISSUE" --author='A B'`, func(p shellwords.Part) string { return "#" + p.Text })
+	require.NoError(t, err)
+	require.Equal(t, []string{"git", "commit", "-m", "fix #ISSUE", "--author=A B"}, words)
+
+	_, err = shellwords.Split("ls | wc", nil)
+	require.ErrorContains(t, err, "unexpected | in a simple command")
+}
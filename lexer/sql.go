@@ -0,0 +1,211 @@
+package lexer
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// quotingTokenizer returns the tokenizer started by r when it opens a quoted identifier, a
+// dollar-quoted string or a parameter, reporting false otherwise.
+func (tf *TokenCreator) quotingTokenizer(r rune) (TokenizerHandler, bool) {
+	lc := tf.languageConfig
+	switch {
+	case r == '$' && lc.DollarQuotedStrings:
+		return tf.dollarQuotes.start(), true
+	case lc.isParameterPrefix(r):
+		return tf.parameters.start(string(r)), true
+	}
+	if close, found := lc.QuotedIdentifiers[r]; found {
+		return tf.quotedIdentifiers.start(r, close), true
+	}
+	return nil, false
+}
+
+// isParameterPrefix reports whether r starts a ParameterType token.
+func (ll *LanguageConfig) isParameterPrefix(r rune) bool {
+	return ll.Parameters != "" && strings.ContainsRune(ll.Parameters, r)
+}
+
+// endsSymbols reports whether r, a symbol following symbols, starts a parameter rather than
+// continuing them, e.g. the ':' of "=:id" but not of the operator "::".
+func (ll *LanguageConfig) endsSymbols(symbols []byte, r rune) bool {
+	if !ll.isParameterPrefix(r) {
+		return false
+	}
+	run := string(utf8.AppendRune(symbols, r))
+	if ll.compiled != nil {
+		node := ll.compiled.root
+		for i := 0; i < len(run) && node != nil; i++ {
+			node = node.next[run[i]]
+		}
+		return node == nil
+	}
+	for operator := range ll.Operators {
+		if strings.HasPrefix(operator, run) {
+			return false
+		}
+	}
+	return true
+}
+
+// quotedIdentifierTokenizer reads an identifier between the delimiters of QuotedIdentifiers,
+// e.g. "order" or [order]. Its token's Literal is the name without the delimiters, and its
+// Value the identifier as written.
+type quotedIdentifierTokenizer struct {
+	tf          *TokenCreator
+	open, close rune
+	name        []byte
+	closing     bool // The closing delimiter has been read, ending the identifier unless it's doubled
+	handler     TokenizerHandler
+}
+
+func (q *quotedIdentifierTokenizer) start(open, close rune) TokenizerHandler {
+	q.open, q.close = open, close
+	q.name = q.name[:0]
+	q.closing = false
+	if q.handler == nil {
+		q.handler = q.tokenize
+	}
+	return q.handler
+}
+
+func (q *quotedIdentifierTokenizer) tokenize(r rune) ([]Token, completed, error) {
+	if q.closing {
+		q.closing = false
+		if r != q.close {
+			q.tf.SetOverFlow(r)
+			if len(q.name) == 0 {
+				return nil, false, fmt.Errorf("empty quoted identifier")
+			}
+			name := string(q.name)
+			closing := string(q.close)
+			quoted := string(q.open) + strings.ReplaceAll(name, closing, closing+closing) + closing
+			return q.tf.emit(newToken(IdentifierCategory, IdentifierType, name, quoted)), true, nil
+		}
+	} else if r == q.close {
+		q.closing = true
+		return nil, false, nil
+	} else if r == newLine {
+		return nil, false, fmt.Errorf("unterminated quoted identifier")
+	}
+
+	q.name = utf8.AppendRune(q.name, r)
+	return nil, false, q.tf.checkLength(len(q.name))
+}
+
+// parameterTokenizer reads a ParameterType token: a prefix of Parameters followed by a name,
+// e.g. ":id", by digits, e.g. "$1", or by nothing, e.g. "?". Named parameters have the name
+// as their Value, numbered parameters an int64 and anonymous parameters nil. A prefix that's
+// also a symbol, followed by neither, is a symbol, e.g. the ':' of "::".
+type parameterTokenizer struct {
+	tf      *TokenCreator
+	literal []byte
+	prefix  int // Length of the prefix in literal
+	handler TokenizerHandler
+}
+
+func (p *parameterTokenizer) start(initialString string) TokenizerHandler {
+	p.literal = append(p.literal[:0], initialString...)
+	_, p.prefix = utf8.DecodeRuneInString(initialString)
+	if p.handler == nil {
+		p.handler = p.tokenize
+	}
+	return p.handler
+}
+
+func (p *parameterTokenizer) tokenize(r rune) ([]Token, completed, error) {
+	tf := p.tf
+	body := p.literal[p.prefix:]
+	switch {
+	case len(body) == 0 && ('0' <= r && r <= '9'):
+		p.literal = append(p.literal, byte(r))
+		return nil, false, tf.checkLength(len(p.literal))
+	case len(body) > 0 && '0' <= body[0] && body[0] <= '9':
+		if '0' <= r && r <= '9' {
+			p.literal = append(p.literal, byte(r))
+			return nil, false, tf.checkLength(len(p.literal))
+		}
+	case tf.languageConfig.isIdentifierChar(r, len(body)):
+		p.literal = utf8.AppendRune(p.literal, r)
+		return nil, false, tf.checkLength(len(p.literal))
+	}
+
+	literal := string(p.literal)
+	if len(body) == 0 {
+		if _, found := tf.languageConfig.symbol(rune(p.literal[0])); found && p.prefix == 1 {
+			tf.SetTokenizer(tf.symbols.start(literal))
+			return tf.symbols.tokenize(r)
+		}
+		tf.SetOverFlow(r)
+		return tf.emit(newToken(LiteralCategory, ParameterType, literal, nil)), true, nil
+	}
+
+	tf.SetOverFlow(r)
+	if '0' <= body[0] && body[0] <= '9' {
+		number, err := strconv.ParseInt(string(body), 10, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid parameter %s", literal)
+		}
+		return tf.emit(newToken(LiteralCategory, ParameterType, literal, number)), true, nil
+	}
+	return tf.emit(newToken(LiteralCategory, ParameterType, literal, string(body))), true, nil
+}
+
+// dollarQuoteTokenizer reads a PostgreSQL dollar-quoted string, $tag$...$tag$ where the tag
+// is an identifier or empty. The string may span lines, each line break adding '\n' to its
+// Value, and the StringLiteral has the position of its closing '$'. With '$' in Parameters,
+// "$1" and "$name" are parameters, because a tag is only read up to its closing '$'.
+type dollarQuoteTokenizer struct {
+	tf      *TokenCreator
+	tag     []byte // Opening delimiter, "$tag$" once it's complete
+	opened  bool
+	body    []byte
+	handler TokenizerHandler
+}
+
+func (d *dollarQuoteTokenizer) start() TokenizerHandler {
+	d.tag = append(d.tag[:0], '$')
+	d.opened = false
+	d.body = d.body[:0]
+	if d.handler == nil {
+		d.handler = d.tokenize
+	}
+	return d.handler
+}
+
+func (d *dollarQuoteTokenizer) tokenize(r rune) ([]Token, completed, error) {
+	tf := d.tf
+	if d.opened {
+		if r == newLine {
+			tf.spansLines = true
+		}
+		d.body = utf8.AppendRune(d.body, r)
+		if !bytes.HasSuffix(d.body, d.tag) {
+			return nil, false, tf.checkLength(len(d.body) - len(d.tag))
+		}
+		tf.spansLines = false
+		value := string(d.body[:len(d.body)-len(d.tag)])
+		return tf.emit(newToken(LiteralCategory, StringLiteral, "", value)), true, nil
+	}
+
+	switch {
+	case r == '$':
+		d.tag = append(d.tag, '$')
+		d.opened = true
+		return nil, false, nil
+	case tf.languageConfig.isIdentifierChar(r, len(d.tag)-1):
+		d.tag = utf8.AppendRune(d.tag, r)
+		return nil, false, tf.checkLength(len(d.tag))
+	case tf.languageConfig.isParameterPrefix('$'):
+		tf.SetTokenizer(tf.parameters.start(string(d.tag)))
+		return tf.parameters.tokenize(r)
+	}
+	if _, found := tf.languageConfig.symbol('$'); found && len(d.tag) == 1 {
+		tf.SetTokenizer(tf.symbols.start("$"))
+		return tf.symbols.tokenize(r)
+	}
+	return nil, false, fmt.Errorf("invalid dollar quote %s", d.tag)
+}
@@ -0,0 +1,134 @@
+package lexer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+const CastToken lexer.TokenIdentifier = 0x121
+
+// newSQLLexer returns a lexer of SQL quoted identifiers, strings and parameters.
+func newSQLLexer() *lexer.Lexer {
+	return lexer.NewLexer(newSQLLanguage())
+}
+
+func newSQLLanguage() *lexer.LanguageConfig {
+	return lexer.NewLanguage("sql").
+		Operator("::", CastToken).
+		Symbol(':', ColonToken).
+		Symbol('=', EqualsSymbolToken).
+		Symbol(',', CommaToken).
+		StringDelimiters("'").
+		DoubledQuoteEscapes().
+		DollarQuotedStrings().
+		QuotedIdentifier('"', '"').
+		QuotedIdentifier('[', ']').
+		Parameters(":$?").
+		IdentifierProfile(lexer.ASCIIIdentifiers).
+		IdentifierRunes("_").
+		IdentifierFallback().
+		MustBuild()
+}
+
+func TestQuotedIdentifiers(t *testing.T) {
+	tokens, err := newSQLLexer().TokenizeLine(`"Order Id", [a]]b], "say ""hi"""`, "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{
+		lexer.IdentifierType, CommaToken, lexer.IdentifierType, CommaToken, lexer.IdentifierType, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+	require.Equal(t, "Order Id", tokens[0].Literal)
+	require.Equal(t, `"Order Id"`, tokens[0].Value)
+	require.Equal(t, lexer.IdentifierCategory, tokens[0].Category)
+	require.Equal(t, "a]b", tokens[2].Literal)
+	require.Equal(t, "[a]]b]", tokens[2].Value)
+	require.Equal(t, `say "hi"`, tokens[4].Literal)
+
+	for source, message := range map[string]string{`"abc`: "unterminated quoted identifier", `[]`: "empty quoted identifier"} {
+		_, err := newSQLLexer().TokenizeLine(source, "testfile", 0)
+		require.ErrorContains(t, err, message, source)
+	}
+}
+
+func TestDoubledQuoteEscapes(t *testing.T) {
+	tokens, err := newSQLLexer().TokenizeLine(`'it''s','','a\n'`, "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{
+		lexer.StringLiteral, CommaToken, lexer.StringLiteral, CommaToken, lexer.StringLiteral, lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+	require.Equal(t, "it's", tokens[0].Value)
+	require.Equal(t, "", tokens[2].Value)
+	require.Equal(t, `a\n`, tokens[4].Value)
+}
+
+func TestDollarQuotedStrings(t *testing.T) {
+	source := "x = $$it's$$,\n$fn$\nbegin\n  $$ $f$\n\n$fn$, $a$"
+	tokens, err := newSQLLexer().Tokenize(strings.NewReader(source+"$a$\n"), "testfile")
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{
+		lexer.IdentifierType, EqualsSymbolToken, lexer.StringLiteral, CommaToken, lexer.EndOfLineType,
+		lexer.StringLiteral, CommaToken, lexer.StringLiteral, lexer.EndOfLineType,
+		lexer.EOFType,
+	}, tokenIDs(tokens))
+	require.Equal(t, "it's", tokens[2].Value)
+	require.Equal(t, "\nbegin\n  $$ $f$\n\n", tokens[5].Value)
+	require.Equal(t, uint(6), tokens[5].SourceLine)
+	require.Equal(t, uint(3), tokens[5].SourceColumn)
+	require.Equal(t, "", tokens[7].Value)
+
+	_, err = newSQLLexer().Tokenize(strings.NewReader(source), "testfile")
+	require.ErrorContains(t, err, "[testfile line: 6] unterminated dollar-quoted string")
+}
+
+func TestParameters(t *testing.T) {
+	source := "id=:id, $1, ?, :2, $name, x::int, y := :"
+	tokens, err := newSQLLexer().TokenizeLine(source, "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, []lexer.TokenIdentifier{
+		lexer.IdentifierType, EqualsSymbolToken, lexer.ParameterType, CommaToken,
+		lexer.ParameterType, CommaToken,
+		lexer.ParameterType, CommaToken,
+		lexer.ParameterType, CommaToken,
+		lexer.ParameterType, CommaToken,
+		lexer.IdentifierType, CastToken, lexer.IdentifierType, CommaToken,
+		lexer.IdentifierType, ColonToken, EqualsSymbolToken, ColonToken,
+		lexer.EndOfLineType,
+	}, tokenIDs(tokens))
+
+	values := []any{tokens[2].Value, tokens[4].Value, tokens[6].Value, tokens[8].Value, tokens[10].Value}
+	require.Equal(t, []any{"id", int64(1), nil, int64(2), "name"}, values)
+	require.Equal(t, ":id", tokens[2].Literal)
+	require.Equal(t, "$1", tokens[4].Literal)
+	require.Equal(t, "?", tokens[6].Literal)
+	require.Equal(t, lexer.LiteralCategory, tokens[2].Category)
+
+	compiled, err := lexer.NewLexer(newSQLLanguage().Compile()).TokenizeLine(source, "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, tokens, compiled)
+}
+
+func TestValidateQuoting(t *testing.T) {
+	err := lexer.NewLexerLanguage(lexer.LanguageConfig{
+		Symbols:             map[rune]lexer.TokenIdentifier{'[': 10, ':': 11},
+		QuotedIdentifiers:   map[rune]rune{'[': ']', '"': '"', '?': '?', '<': 0},
+		StringDelimiters:    "'",
+		Parameters:          ":?'",
+		DollarQuotedStrings: true,
+	}).Validate()
+	require.Equal(t, []string{
+		`invalid entry: quoted identifier '<' has no closing delimiter`,
+		`conflict: quoted identifier delimiter '?' also starts parameters or strings`,
+		`conflict: quoted identifier delimiter '[' also starts a symbol`,
+		`conflict: parameter prefix '\'' also starts a string`,
+	}, problems(err))
+
+	require.NoError(t, lexer.NewLexerLanguage(lexer.LanguageConfig{
+		Symbols:             map[rune]lexer.TokenIdentifier{':': 11},
+		QuotedIdentifiers:   map[rune]rune{'"': '"', '[': ']'},
+		StringDelimiters:    "'",
+		Parameters:          ":$?",
+		DollarQuotedStrings: true,
+	}).Validate())
+}
@@ -1,6 +1,9 @@
 package lexer
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 // TokenIdentifier is a type used to distinguish between different kinds of tokens.
 // It's an integer code that gives the token its "identity".
@@ -33,22 +36,59 @@ const (
 	LastStdLiteral
 )
 
+// Standard token types added since LastStdLiteral was fixed are in a reserved range at the
+// top of the identifiers, so that custom token types declared after LastStdLiteral keep their
+// numbers. Custom token types must be below FirstReservedType.
+const (
+	// FirstReservedType is the first identifier of the reserved range.
+	FirstReservedType TokenIdentifier = math.MaxInt16 - 0xFF
+
+	// IdentifierType represents an identifier that isn't a keyword, when the language
+	// falls back to it rather than requiring TokenCreators.
+	IdentifierType TokenIdentifier = FirstReservedType + iota - 1
+
+	// LineNumberType represents the integer starting a line of a line-numbered language such
+	// as BASIC, when the language has LineNumberLabels.
+	LineNumberType
+
+	// ParameterType represents a query parameter such as ":name", "$1" or "?", when the
+	// language has Parameters.
+	ParameterType
+
+	// CommentType represents the text of a comment, without its delimiters, when the
+	// language has KeepComments.
+	CommentType
+
+	// lastReservedType marks the end of the standard token types in the reserved range.
+	lastReservedType
+)
+
 // Token represents a single lexical token in the language being parsed.
 // Each token has an identifier, a literal string representation, and an optional value.
 // The SourceLine and SourceColumn fields represent the token's position in the source text.
 type Token struct {
 	ID           TokenIdentifier // The identifier for the type of token.
+	Category     Category        // The category of the token, e.g. KeywordCategory.
 	Literal      string          // The literal string content of the token.
 	Value        any             // The value that the token represents, can be nil.
 	Filename     string
-	SourceLine   uint // The line in the source text where this token occurs.
-	SourceColumn uint // The column in the source text where this token occurs.
+	SourceLine   uint    // The line in the source text where this token occurs.
+	SourceColumn uint    // The column in the source text where this token occurs.
+	Origin       *Origin // How a preprocessor produced the token, or nil if it was lexed where it is.
 }
 
 // String returns a string representation of a Token instance.
 // The representation includes the token's identifier, its literal string, and its value.
+// Use LanguageConfig.FormatToken to name the identifiers of a language's tokens.
 func (t Token) String() string {
-	return fmt.Sprintf("%d: %s: %v", int(t.ID), t.Literal, t.Value)
+	return fmt.Sprintf("%s: %s: %v", t.ID, t.Literal, t.Value)
+}
+
+// newToken creates a token of the given category.
+func newToken(category Category, id TokenIdentifier, literal string, value any) Token {
+	t := NewToken(id, literal, value)
+	t.Category = category
+	return t
 }
 
 // NewToken is a constructor function for creating a new Token.
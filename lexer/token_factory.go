@@ -1,37 +1,96 @@
 package lexer
 
 import (
+	"slices"
 	"unicode"
 
 	"github.com/jrsteele09/go-lexer/lexer/comments"
-	"github.com/jrsteele09/go-lexer/lexer/utils"
 	"github.com/pkg/errors"
 )
 
 // TokenCreator manages the creation of tokens for a given lexer.
 type TokenCreator struct {
-	overflowRune     *rune
-	currentTokenizer TokenizerHandler
-	commentParser    *comments.CommentParser
-	languageConfig   *LanguageConfig
+	overflowRune         rune
+	hasOverflow          bool
+	currentTokenizer     TokenizerHandler
+	selector             TokenizerHandler
+	commentParser        *comments.CommentParser
+	languageConfig       *LanguageConfig
+	folding              *caseFolding     // Case folded tables, nil if keywords are case-sensitive
+	asm                  *assemblerTables // Assembler tables, nil unless the language has an Assembler profile
+	previous             Token            // Last token of the current line, only tracked for assemblers
+	hasPrevious          bool
+	spansLines           bool    // The current token continues on the next line, e.g. a dollar-quoted string
+	invisibleIdentifiers bool    // Identifiers take in invisible characters, for InvisibleCheck
+	maxTokenLength       int     // Maximum length of the tokens being built, no limit if 0
+	longestRun           int     // The longestSymbolRun without compiled tables, -1 until needed on each line
+	out                  []Token // Reused result slice, only valid until the next call to Tokenize
+
+	// Built-in tokenizers reused by the selector, so starting a token doesn't allocate
+	numbers     numberTokenizer
+	strings     stringTokenizer
+	identifiers identifierTokenizer
+	symbols     symbolTokenizer
+	hex         hexTokenizer
+	binary      binaryTokenizer
+
+	anonymousLabels   anonymousLabelTokenizer
+	quotedIdentifiers quotedIdentifierTokenizer
+	parameters        parameterTokenizer
+	dollarQuotes      dollarQuoteTokenizer
 }
 
 // NewTokenCreator initializes and returns a new TokenFactory for a given lexer.
 func NewTokenCreator(commentParser *comments.CommentParser, lc *LanguageConfig) *TokenCreator {
-	tf := &TokenCreator{commentParser: commentParser, languageConfig: lc}
-	tf.SetTokenizer(tf.tokenizerSelector())
+	tf := &TokenCreator{commentParser: commentParser, languageConfig: lc, folding: newCaseFolding(lc), asm: newAssemblerTables(lc), longestRun: -1}
+	tf.numbers.tf = tf
+	tf.strings.tf = tf
+	tf.identifiers.tf = tf
+	tf.symbols.tf = tf
+	tf.hex.tf = tf
+	tf.binary.tf = tf
+	tf.anonymousLabels.tf = tf
+	tf.quotedIdentifiers.tf = tf
+	tf.parameters.tf = tf
+	tf.dollarQuotes.tf = tf
+	if tf.asm != nil {
+		tf.anonymousLabels.style = tf.asm.profile.AnonymousLabels
+	}
+	tf.selector = tf.tokenizerSelector()
+	tf.SetTokenizer(tf.selector)
 	return tf
 }
 
+// reset discards any in-progress token, ready to start tokenizing a new line, unless the
+// token spans lines.
+func (tf *TokenCreator) reset() {
+	tf.hasOverflow = false
+	tf.hasPrevious = false
+	tf.longestRun = -1
+	if !tf.spansLines {
+		tf.SetTokenizer(tf.selector)
+	}
+}
+
 // Tokenize calls the current tokenizer, defaulting to the tokenizer identifier function.
 // Once a token has been created, it restores to identifying the type of the next token.
 func (tf *TokenCreator) Tokenize(r rune) ([]Token, error) {
+	tokens, err := tf.tokenize(r)
+	return slices.Clone(tokens), err
+}
+
+// tokenize is Tokenize for the Lexer, returning a slice that is reused by the next call,
+// so that lexing a line doesn't allocate for each token.
+func (tf *TokenCreator) tokenize(r rune) ([]Token, error) {
 	tokens, completed, err := tf.currentTokenizer(r)
 	if err != nil {
 		return nil, err
 	}
 	if completed {
-		tf.SetTokenizer(tf.tokenizerSelector())
+		tf.SetTokenizer(tf.selector)
+	}
+	if tf.asm != nil && len(tokens) > 0 {
+		tf.previous, tf.hasPrevious = tokens[len(tokens)-1], true
 	}
 	return tokens, err
 }
@@ -41,26 +100,35 @@ func (tf *TokenCreator) Tokenize(r rune) ([]Token, error) {
 func (tf *TokenCreator) tokenizerSelector() TokenizerHandler {
 	return func(r rune) ([]Token, completed, error) {
 		// Ignore the rune if in a comment or a space
-		if tf.commentParser.InComment() || unicode.IsSpace(r) {
+		if tf.commentParser.InComment() || tf.languageConfig.isWhitespace(r) {
 			return nil, false, nil
 		}
 
-		if tf.languageConfig.IsCustomTokenizer(string(r)) {
-			tf.SetTokenizer(tf.languageConfig.Tokenizer(string(r))(tf, string(r)))
+		if tf.asm != nil {
+			if tokens, started := tf.assemblerTokenizer(r); started {
+				return tokens, tokens != nil, nil
+			}
+		}
+
+		if tokenizer := tf.languageConfig.prefixTokenizer(nil, r); tokenizer != nil {
+			tf.SetTokenizer(tokenizer(tf, string(r)))
+			return nil, false, nil
+		} else if tokenizer, found := tf.quotingTokenizer(r); found {
+			tf.SetTokenizer(tokenizer)
 			return nil, false, nil
-		} else if _, found := tf.languageConfig.Symbols[r]; found {
-			tf.SetTokenizer(SymbolTokenizer(tf, string(r))) // Replace the defaultTokenizer with the symbolTokenizer
+		} else if _, found := tf.languageConfig.symbol(r); found {
+			tf.SetTokenizer(tf.symbols.start(string(r))) // Replace the defaultTokenizer with the symbolTokenizer
 			return nil, false, nil
 		} else if unicode.IsDigit(r) {
-			tf.SetTokenizer(NumberTokenizer(tf, string(r))) // Replace the defaultTokenizer with the numberTokenizer
+			tf.SetTokenizer(tf.numbers.start(string(r))) // Replace the defaultTokenizer with the numberTokenizer
 			return nil, false, nil
 
-		} else if utils.IsStringQuotes(r) {
-			tf.SetTokenizer(StringTokenizer(tf, string(r))) // Replace the defaultTokenizer with the stringTokenizer
+		} else if tf.languageConfig.isStringDelimiter(r) {
+			tf.SetTokenizer(tf.strings.start(string(r))) // Replace the defaultTokenizer with the stringTokenizer
 			return nil, false, nil
 
-		} else if utils.IsIdentifierChar(r, 0, tf.languageConfig.ExtendedIdentifierRunes, tf.languageConfig.IdentifierTermination) {
-			tf.SetTokenizer(IdentifierTokenizer(tf, string(r))) // Replace the defaultTokenizer with the identifierTokenizer
+		} else if tf.isIdentifierChar(r, 0) {
+			tf.SetTokenizer(tf.identifiers.start(string(r))) // Replace the defaultTokenizer with the identifierTokenizer
 			return nil, false, nil
 		} else if tf.commentParser.IsStartOfComment(string(r)) { // Check for being in a comment - could be assembly ";"
 			return nil, false, nil
@@ -70,26 +138,38 @@ func (tf *TokenCreator) tokenizerSelector() TokenizerHandler {
 	}
 }
 
+// isIdentifierChar reports whether r is valid at byte position pos of an identifier, which
+// includes invisible characters while InvisibleCheck reports them.
+func (tf *TokenCreator) isIdentifierChar(r rune, pos int) bool {
+	return tf.languageConfig.isIdentifierChar(r, pos) || (tf.invisibleIdentifiers && isInvisible(r))
+}
+
 func (tf *TokenCreator) SetTokenizer(tokenizer TokenizerHandler) {
 	tf.currentTokenizer = tokenizer
 }
 
 func (tf *TokenCreator) SetOverFlow(r rune) {
-	tf.overflowRune = &r
+	tf.overflowRune = r
+	tf.hasOverflow = true
 }
 
 // HasRuneOverflow checks if there's a pending rune to process.
 func (tf *TokenCreator) HasRuneOverflow() bool {
-	return !tf.commentParser.InComment() && tf.overflowRune != nil
+	return !tf.commentParser.InComment() && tf.hasOverflow
 }
 
 // OverflowRune returns an overflow rune if available, or '\n' if not.
 // It should be preceded by a call to HasRuneOverflow
 func (tf *TokenCreator) OverflowRune() rune {
-	if tf.overflowRune == nil {
+	if !tf.hasOverflow {
 		return '\n'
 	}
-	r := *tf.overflowRune
-	tf.overflowRune = nil
-	return r
+	tf.hasOverflow = false
+	return tf.overflowRune
+}
+
+// emit returns t as a single token result, reusing the creator's result slice.
+func (tf *TokenCreator) emit(t Token) []Token {
+	tf.out = append(tf.out[:0], t)
+	return tf.out
 }
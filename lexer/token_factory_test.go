@@ -0,0 +1,25 @@
+package lexer_test
+
+import (
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	commentparser "github.com/jrsteele09/go-lexer/lexer/comments"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenCreatorResultsArentReused(t *testing.T) {
+	language := NewBasicLanguage()
+	tf := lexer.NewTokenCreator(commentparser.NewCommentParser(language.Comments), language)
+	var results [][]lexer.Token
+	for _, r := range "ab cd " {
+		tokens, err := tf.Tokenize(r)
+		require.NoError(t, err)
+		if len(tokens) > 0 {
+			results = append(results, tokens)
+		}
+	}
+	require.Len(t, results, 2)
+	require.Equal(t, "ab", results[0][0].Literal)
+	require.Equal(t, "cd", results[1][0].Literal)
+}
@@ -0,0 +1,92 @@
+package lexer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// standardTokenNames are the names of the standard token identifiers.
+var standardTokenNames = map[TokenIdentifier]string{
+	NullType:       "NullType",
+	EOFType:        "EOFType",
+	EndOfLineType:  "EndOfLineType",
+	IntegerLiteral: "IntegerLiteral",
+	NumberLiteral:  "NumberLiteral",
+	HexLiteral:     "HexLiteral",
+	StringLiteral:  "StringLiteral",
+	IdentifierType: "IdentifierType",
+	LineNumberType: "LineNumberType",
+	ParameterType:  "ParameterType",
+	CommentType:    "CommentType",
+}
+
+// StandardTokens returns the standard token identifiers in order: those below
+// LastStdLiteral, then those of the reserved range.
+func StandardTokens() []TokenIdentifier {
+	ids := make([]TokenIdentifier, 0, len(standardTokenNames))
+	for id := NullType; id < LastStdLiteral; id++ {
+		ids = append(ids, id)
+	}
+	for id := FirstReservedType; id < lastReservedType; id++ {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// String returns the name of a standard token identifier, e.g. "StringLiteral", or the
+// number of any other identifier. Use LanguageConfig.TokenName to name a language's tokens.
+func (id TokenIdentifier) String() string {
+	if name, found := standardTokenNames[id]; found {
+		return name
+	}
+	return strconv.Itoa(int(id))
+}
+
+// TokenName returns a readable name for a token identifier of the language. In order of
+// preference it is the name given in TokenNames, the name of a standard token, or the
+// quoted keyword, operator or symbol the identifier is used for, e.g. "<=" or '+'.
+// Identifiers without a name are formatted as numbers.
+func (ll *LanguageConfig) TokenName(id TokenIdentifier) string {
+	if name, found := ll.TokenNames[id]; found {
+		return name
+	}
+	if name, found := standardTokenNames[id]; found {
+		return name
+	}
+	if name := firstKey(ll.Keywords, id); name != "" {
+		return strconv.Quote(name)
+	}
+	if name := firstKey(ll.Operators, id); name != "" {
+		return strconv.Quote(name)
+	}
+
+	symbols := make([]rune, 0, 1)
+	for r, symbolID := range ll.Symbols {
+		if symbolID == id {
+			symbols = append(symbols, r)
+		}
+	}
+	if len(symbols) > 0 {
+		sort.Slice(symbols, func(i, j int) bool { return symbols[i] < symbols[j] })
+		return strconv.QuoteRune(symbols[0])
+	}
+	return id.String()
+}
+
+// FormatToken returns a representation of a token like Token.String, with the token
+// identifier named by TokenName.
+func (ll *LanguageConfig) FormatToken(t Token) string {
+	return fmt.Sprintf("%s: %s: %v", ll.TokenName(t.ID), t.Literal, t.Value)
+}
+
+// firstKey returns the smallest key of m mapped to id, or "" if there isn't one.
+func firstKey(m map[string]TokenIdentifier, id TokenIdentifier) string {
+	first := ""
+	for key, keyID := range m {
+		if keyID == id && (first == "" || key < first) {
+			first = key
+		}
+	}
+	return first
+}
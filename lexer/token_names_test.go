@@ -0,0 +1,57 @@
+package lexer_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenIdentifierString(t *testing.T) {
+	require.Equal(t, "StringLiteral", lexer.StringLiteral.String())
+	require.Equal(t, "EndOfLineType", fmt.Sprint(lexer.EndOfLineType))
+	require.Equal(t, "269", LessThanOrEqualToken.String())
+	require.Equal(t, "IdentifierType", lexer.IdentifierType.String())
+	require.Equal(t, "ParameterType", lexer.ParameterType.String())
+	require.Equal(t, "HexLiteral: 0xff: 255", lexer.NewToken(lexer.HexLiteral, "0xff", 255).String())
+}
+
+func TestStandardTokens(t *testing.T) {
+	// Standard tokens added later are reserved, so that custom tokens keep their numbers
+	require.Equal(t, lexer.TokenIdentifier(7), lexer.LastStdLiteral)
+	require.Equal(t, []lexer.TokenIdentifier{
+		lexer.NullType, lexer.EOFType, lexer.EndOfLineType, lexer.IntegerLiteral, lexer.NumberLiteral,
+		lexer.HexLiteral, lexer.StringLiteral, lexer.IdentifierType, lexer.LineNumberType, lexer.ParameterType,
+		lexer.CommentType,
+	}, lexer.StandardTokens())
+	for _, id := range lexer.StandardTokens()[lexer.LastStdLiteral:] {
+		require.GreaterOrEqual(t, id, lexer.FirstReservedType)
+	}
+}
+
+func TestTokenName(t *testing.T) {
+	config := NewBasicLanguage()
+	config.TokenNames = map[lexer.TokenIdentifier]string{LessThanOrEqualToken: "LessThanOrEqual"}
+
+	require.Equal(t, "LessThanOrEqual", config.TokenName(LessThanOrEqualToken))
+	require.Equal(t, `"if"`, config.TokenName(IfStatementToken))
+	require.Equal(t, `">="`, config.TokenName(GreaterThanOrEqualToken))
+	require.Equal(t, "'+'", config.TokenName(AddSymbolToken))
+	require.Equal(t, "IntegerLiteral", config.TokenName(lexer.IntegerLiteral))
+	require.Equal(t, "245", config.TokenName(IntegerVariableToken))
+
+	tokens, err := lexer.NewLexer(config).TokenizeLine("a <= 10", "testfile", 0)
+	require.NoError(t, err)
+	require.Equal(t, "LessThanOrEqual: <=: <=", config.FormatToken(tokens[1]))
+	require.Equal(t, "IntegerLiteral: 10: 10", config.FormatToken(tokens[2]))
+}
+
+func TestLanguageBuilderTokenName(t *testing.T) {
+	config := lexer.NewLanguage("names").
+		Operator("<=", LessThanOrEqualToken).
+		TokenName(LessThanOrEqualToken, "LessThanOrEqual").
+		MustBuild()
+	require.Equal(t, "LessThanOrEqual", config.TokenName(LessThanOrEqualToken))
+	require.Equal(t, "'<'", config.TokenName(config.Symbols['<']))
+}
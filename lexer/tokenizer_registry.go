@@ -0,0 +1,66 @@
+package lexer
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	tokenizersMu sync.RWMutex
+
+	// tokenizers names the available prefix tokenizers, so that they can be referenced from
+	// outside Go code, e.g. by language definition files and the code generator.
+	tokenizers = map[string]TokenizerFunc{
+		"NumberTokenizer":      NumberTokenizer,
+		"BinaryTokenizer":      BinaryTokenizer,
+		"HexTokenizer":         HexTokenizer,
+		"StringTokenizer":      StringTokenizer,
+		"IdentifierTokenizer":  IdentifierTokenizer,
+		"SymbolTokenizer":      SymbolTokenizer,
+		"JSONStringTokenizer":  JSONStringTokenizer,
+		"JSONNumberTokenizer":  JSONNumberTokenizer,
+		"JSON5StringTokenizer": JSON5StringTokenizer,
+		"JSON5NumberTokenizer": JSON5NumberTokenizer,
+	}
+)
+
+// RegisterTokenizer makes a custom prefix tokenizer available by name, e.g. to language
+// definition files. It returns an error if the name is already registered.
+func RegisterTokenizer(name string, tokenizer TokenizerFunc) error {
+	if tokenizer == nil {
+		return fmt.Errorf("RegisterTokenizer: nil tokenizer for %q", name)
+	}
+	tokenizersMu.Lock()
+	defer tokenizersMu.Unlock()
+	if _, found := tokenizers[name]; found {
+		return fmt.Errorf("RegisterTokenizer: %q is already registered", name)
+	}
+	tokenizers[name] = tokenizer
+	return nil
+}
+
+// TokenizerByName returns the tokenizer registered with the given name, e.g. "HexTokenizer".
+func TokenizerByName(name string) (TokenizerFunc, bool) {
+	tokenizersMu.RLock()
+	defer tokenizersMu.RUnlock()
+	tokenizer, found := tokenizers[name]
+	return tokenizer, found
+}
+
+// TokenizerName returns the name a tokenizer is registered with, or false if it isn't registered.
+func TokenizerName(tokenizer TokenizerFunc) (string, bool) {
+	if tokenizer == nil {
+		return "", false
+	}
+	ptr := reflect.ValueOf(tokenizer).Pointer()
+
+	tokenizersMu.RLock()
+	defer tokenizersMu.RUnlock()
+	for name, registered := range tokenizers {
+		if reflect.ValueOf(registered).Pointer() == ptr {
+			return name, true
+		}
+	}
+	return "", false
+}
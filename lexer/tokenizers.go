@@ -3,6 +3,7 @@ package lexer
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/jrsteele09/go-lexer/lexer/utils"
 	"github.com/pkg/errors"
@@ -10,250 +11,411 @@ import (
 
 // NumberTokenizer processes numeric literals.
 func NumberTokenizer(tf *TokenCreator, initialString string) TokenizerHandler {
-	var parsedNumber strings.Builder
-	parsedNumber.WriteString(initialString)
-
-	return func(r rune) ([]Token, completed, error) {
-		if utils.IsDigit(r, parsedNumber.Len()) {
-			parsedNumber.WriteRune(r)
-		} else if tf.languageConfig.IsCustomTokenizer(parsedNumber.String() + string(r)) { // Not a digit, perhaps a custom tokenizer, i.e. hex: "0xFF"
-			parsedNumber.WriteRune(r)
-			tf.SetTokenizer(tf.languageConfig.PrefixTokenizers[parsedNumber.String()](tf, parsedNumber.String()))
-			return nil, false, nil
-		} else {
-			tf.SetOverFlow(r)
-			number, err := utils.StringToNumber(parsedNumber.String())
-			if err != nil {
-				return nil, false, errors.Wrap(err, "numberTokenizer stringToNumber")
-			}
-			switch number.(type) {
-			case float64:
-				return []Token{NewToken(NumberLiteral, parsedNumber.String(), number)}, true, nil
-			case int64:
-				return []Token{NewToken(IntegerLiteral, parsedNumber.String(), number)}, true, nil
-			}
-			return []Token{NewToken(NumberLiteral, parsedNumber.String(), number)}, true, nil
-		}
+	return (&numberTokenizer{tf: tf}).start(initialString)
+}
+
+type numberTokenizer struct {
+	tf           *TokenCreator
+	parsedNumber []byte
+	handler      TokenizerHandler
+}
+
+func (n *numberTokenizer) start(initialString string) TokenizerHandler {
+	n.parsedNumber = append(n.parsedNumber[:0], initialString...)
+	if n.handler == nil {
+		n.handler = n.tokenize
+	}
+	return n.handler
+}
+
+func (n *numberTokenizer) tokenize(r rune) ([]Token, completed, error) {
+	tf := n.tf
+	if utils.IsDigit(r, len(n.parsedNumber)) {
+		n.parsedNumber = utf8.AppendRune(n.parsedNumber, r)
+	} else if tokenizer := tf.languageConfig.prefixTokenizer(n.parsedNumber, r); tokenizer != nil { // Not a digit, perhaps a custom tokenizer, i.e. hex: "0xFF"
+		n.parsedNumber = utf8.AppendRune(n.parsedNumber, r)
+		tf.SetTokenizer(tokenizer(tf, string(n.parsedNumber)))
 		return nil, false, nil
+	} else {
+		tf.SetOverFlow(r)
+		literal := string(n.parsedNumber)
+		number, err := utils.StringToNumber(literal)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "numberTokenizer stringToNumber")
+		}
+		switch number.(type) {
+		case float64:
+			return tf.emit(newToken(LiteralCategory, NumberLiteral, literal, number)), true, nil
+		case int64:
+			return tf.emit(newToken(LiteralCategory, IntegerLiteral, literal, number)), true, nil
+		}
+		return tf.emit(newToken(LiteralCategory, NumberLiteral, literal, number)), true, nil
 	}
+	return nil, false, tf.checkLength(len(n.parsedNumber))
 }
 
 // BinaryTokenizer processes a binary number
 func BinaryTokenizer(tf *TokenCreator, initialString string) TokenizerHandler {
-	var builder strings.Builder
+	return tf.binary.start(initialString)
+}
+
+type binaryTokenizer struct {
+	tf      *TokenCreator
+	builder []byte
+	handler TokenizerHandler
+}
 
+func (b *binaryTokenizer) start(initialString string) TokenizerHandler {
+	b.builder = b.builder[:0]
 	if initialString == "0" || initialString == "1" {
-		builder.WriteString(initialString)
+		b.builder = append(b.builder, initialString...)
 	}
+	if b.handler == nil {
+		b.handler = b.tokenize
+	}
+	return b.handler
+}
 
-	return func(r rune) ([]Token, completed, error) {
-		if utils.IsBinaryDigit(r) {
-			builder.WriteRune(r)
-			return nil, false, nil
-		}
-
-		current := builder.String()
-		tf.SetOverFlow(r)
+func (b *binaryTokenizer) tokenize(r rune) ([]Token, completed, error) {
+	tf := b.tf
+	if utils.IsBinaryDigit(r) {
+		b.builder = utf8.AppendRune(b.builder, r)
+		return nil, false, tf.checkLength(len(b.builder))
+	}
 
-		number, err := utils.BinaryStringToNumber(current)
-		if err != nil {
-			return nil, true, fmt.Errorf("BinaryTokenizer BinaryStringToNumber [%w]", err)
-		}
+	current := string(b.builder)
+	tf.SetOverFlow(r)
 
-		return []Token{
-			NewToken(IntegerLiteral, current, number),
-		}, true, nil
+	number, err := utils.BinaryStringToNumber(current)
+	if err != nil {
+		return nil, true, fmt.Errorf("BinaryTokenizer BinaryStringToNumber [%w]", err)
 	}
+
+	return tf.emit(newToken(LiteralCategory, IntegerLiteral, current, number)), true, nil
 }
 
 // HexTokenizer processes hex literals.
 func HexTokenizer(tf *TokenCreator, initialString string) TokenizerHandler {
-	var builder strings.Builder
+	return tf.hex.start(initialString)
+}
+
+type hexTokenizer struct {
+	tf      *TokenCreator
+	builder []byte
+	handler TokenizerHandler
+}
 
+func (h *hexTokenizer) start(initialString string) TokenizerHandler {
+	h.builder = h.builder[:0]
 	if initialString == "" || initialString == "$" {
-		builder.WriteString("0x")
+		h.builder = append(h.builder, "0x"...)
 	}
+	if h.handler == nil {
+		h.handler = h.tokenize
+	}
+	return h.handler
+}
 
-	return func(r rune) ([]Token, completed, error) {
-		if utils.IsHexDigit(r) {
-			builder.WriteRune(r)
-			return nil, false, nil
-		}
-
-		tf.SetOverFlow(r)
+func (h *hexTokenizer) tokenize(r rune) ([]Token, completed, error) {
+	tf := h.tf
+	if utils.IsHexDigit(r) {
+		h.builder = utf8.AppendRune(h.builder, r)
+		return nil, false, tf.checkLength(len(h.builder))
+	}
 
-		parsedString := builder.String()
+	tf.SetOverFlow(r)
 
-		number, err := utils.HexToNumber(parsedString)
-		if err != nil {
-			return nil, false, errors.Wrap(err, "HexTokenizer HexToNumber")
-		}
+	parsedString := string(h.builder)
 
-		return []Token{
-			NewToken(HexLiteral, parsedString, number),
-		}, true, nil
+	number, err := utils.HexToNumber(parsedString)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "HexTokenizer HexToNumber")
 	}
+
+	return tf.emit(newToken(LiteralCategory, HexLiteral, parsedString, number)), true, nil
 }
 
 // StringTokenizer processes string literals, including backslash escape sequences.
 // Supported escapes: \n (newline), \r (carriage return), \t (tab), \0 (null),
-// \\ (backslash), and \<quote> to embed the surrounding quote character.
+// \\ (backslash), and \<quote> to embed the surrounding quote character. With
+// DoubledQuoteEscapes a doubled quote character embeds it instead, and backslashes aren't escapes.
 func StringTokenizer(tf *TokenCreator, initialString string) TokenizerHandler {
-	startRune := initialString
-
-	var builder strings.Builder
-	escaped := false
-
-	return func(r rune) ([]Token, completed, error) {
-		if escaped {
-			escaped = false
-			switch r {
-			case 'n':
-				builder.WriteRune('\n')
-			case 'r':
-				builder.WriteRune('\r')
-			case 't':
-				builder.WriteRune('\t')
-			case '0':
-				builder.WriteRune('\000')
-			default:
-				// Handles \\ and \<quote>, and any unrecognised sequence
-				builder.WriteRune(r)
-			}
-			return nil, false, nil
-		}
+	return (&stringTokenizer{tf: tf}).start(initialString)
+}
 
-		if r == '\\' {
-			escaped = true
-			return nil, false, nil
-		}
+type stringTokenizer struct {
+	tf        *TokenCreator
+	startRune string
+	builder   []byte
+	escaped   bool
+	closing   bool // A quote has been read with DoubledQuoteEscapes, ending the string unless it's doubled
+	handler   TokenizerHandler
+}
+
+func (s *stringTokenizer) start(initialString string) TokenizerHandler {
+	s.startRune = initialString
+	s.builder = s.builder[:0]
+	s.escaped = false
+	s.closing = false
+	if s.handler == nil {
+		s.handler = s.tokenize
+	}
+	return s.handler
+}
 
-		if string(r) == startRune {
-			return []Token{
-				NewToken(StringLiteral, "", builder.String()),
-			}, true, nil
+func (s *stringTokenizer) tokenize(r rune) ([]Token, completed, error) {
+	if s.tf.languageConfig.DoubledQuoteEscapes {
+		return s.tokenizeDoubled(r)
+	}
+	if s.escaped {
+		s.escaped = false
+		switch r {
+		case 'n':
+			s.builder = append(s.builder, '\n')
+		case 'r':
+			s.builder = append(s.builder, '\r')
+		case 't':
+			s.builder = append(s.builder, '\t')
+		case '0':
+			s.builder = append(s.builder, '\000')
+		default:
+			// Handles \\ and \<quote>, and any unrecognised sequence
+			s.builder = utf8.AppendRune(s.builder, r)
 		}
+		return nil, false, s.tf.checkLength(len(s.builder))
+	}
 
-		builder.WriteRune(r)
+	if r == '\\' {
+		s.escaped = true
 		return nil, false, nil
 	}
+
+	if isRuneString(r, s.startRune) {
+		return s.tf.emit(newToken(LiteralCategory, StringLiteral, "", string(s.builder))), true, nil
+	}
+
+	s.builder = utf8.AppendRune(s.builder, r)
+	return nil, false, s.tf.checkLength(len(s.builder))
+}
+
+// tokenizeDoubled reads a string whose quote is escaped by doubling it, so the string only
+// ends at the rune after a single quote.
+func (s *stringTokenizer) tokenizeDoubled(r rune) ([]Token, completed, error) {
+	isQuote := isRuneString(r, s.startRune)
+	if s.closing {
+		s.closing = false
+		if !isQuote {
+			s.tf.SetOverFlow(r)
+			return s.tf.emit(newToken(LiteralCategory, StringLiteral, "", string(s.builder))), true, nil
+		}
+	} else if isQuote {
+		s.closing = true
+		return nil, false, nil
+	}
+
+	s.builder = utf8.AppendRune(s.builder, r)
+	return nil, false, s.tf.checkLength(len(s.builder))
 }
 
 // IdentifierTokenizer processes identifiers like variable names.
 func IdentifierTokenizer(tf *TokenCreator, initialString string) TokenizerHandler {
-	var builder strings.Builder
-	builder.WriteString(initialString)
+	return (&identifierTokenizer{tf: tf}).start(initialString)
+}
 
-	return func(r rune) ([]Token, completed, error) {
-		if !utils.IsIdentifierChar(
-			r,
-			builder.Len(),
-			tf.languageConfig.ExtendedIdentifierRunes,
-			tf.languageConfig.IdentifierTermination,
-		) {
-			tf.SetOverFlow(r)
+type identifierTokenizer struct {
+	tf      *TokenCreator
+	builder []byte
+	handler TokenizerHandler
+}
 
-			identifier := builder.String()
+func (it *identifierTokenizer) start(initialString string) TokenizerHandler {
+	it.builder = append(it.builder[:0], initialString...)
+	if it.handler == nil {
+		it.handler = it.tokenize
+	}
+	return it.handler
+}
 
-			if tf.commentParser.IsStartOfComment(identifier) {
-				return nil, false, nil
-			}
+func (it *identifierTokenizer) tokenize(r rune) ([]Token, completed, error) {
+	tf := it.tf
+	if !tf.isIdentifierChar(r, len(it.builder)) {
+		if tf.asm != nil && tf.asm.isPrefix(it.builder) { // A lone directive or label prefix, e.g. the '!' of "!="
+			tf.SetTokenizer(tf.symbols.start(string(it.builder)))
+			return tf.symbols.tokenize(r)
+		}
+		tf.SetOverFlow(r)
+		if t, found := tf.keywordToken(it.builder); found {
+			return tf.emit(t), true, nil
+		}
+
+		identifier := string(it.builder)
 
-			t := tf.languageConfig.tokenFromIdentifier(identifier)
-			if t.ID == NullType {
-				return nil, true, fmt.Errorf("unknown identifier %s", identifier)
+		if tf.isStartOfComment(identifier) {
+			if t := tf.tokenFromIdentifier(identifier); tf.languageConfig.KeepComments && t.Category == KeywordCategory {
+				return tf.emit(t), true, nil // The keyword comes before the comment, e.g. BASIC's REM
 			}
+			return nil, false, nil
+		}
 
-			return []Token{t}, true, nil
+		t := tf.tokenFromIdentifier(identifier)
+		if t.ID == NullType {
+			return nil, true, fmt.Errorf("unknown identifier %s", identifier)
 		}
 
-		builder.WriteRune(r)
+		return tf.emit(t), true, nil
+	}
 
-		rStr := string(r)
-		if strings.Contains(tf.languageConfig.IdentifierTermination, rStr) {
-			identifier := builder.String()
+	it.builder = utf8.AppendRune(it.builder, r)
 
-			t := tf.languageConfig.tokenFromIdentifier(identifier)
-			if t.ID == NullType {
-				return nil, true, fmt.Errorf("unknown identifier %s", identifier)
-			}
+	if strings.ContainsRune(tf.languageConfig.IdentifierTermination, r) {
+		if t, found := tf.keywordToken(it.builder); found {
+			return tf.emit(t), true, nil
+		}
+		identifier := string(it.builder)
 
-			return []Token{t}, true, nil
+		t := tf.tokenFromIdentifier(identifier)
+		if t.ID == NullType {
+			return nil, true, fmt.Errorf("unknown identifier %s", identifier)
 		}
 
-		return nil, false, nil
+		return tf.emit(t), true, nil
 	}
+
+	return nil, false, tf.checkLength(len(it.builder))
 }
 
 // SymbolTokenizer processes operators
 func SymbolTokenizer(tf *TokenCreator, initialString string) TokenizerHandler {
-	symbolsString := string(initialString)
-
-	createToken := func(overflowRune rune) ([]Token, completed, error) {
-		// First check that the parsed symbolString + overflowRune doesn't match a custom tokenizer
-		resetTokenizer := completed(true)
-		if len(symbolsString) > 0 {
-			runes := []rune(symbolsString)
-			lastRune := runes[len(runes)-1]
-			tokenizerStr := string(lastRune) + string(overflowRune) //
-			if tf.languageConfig.IsCustomTokenizer(tokenizerStr) {  // CustomTokenizers take priority over symbols
-				tf.SetTokenizer(tf.languageConfig.Tokenizer(tokenizerStr)(tf, string(lastRune)))
-				symbolsString = string(runes[:len(runes)-1])
-				resetTokenizer = false // Don't want to reset as we've just swapped the tokenizer
-			} else if tf.languageConfig.IsCustomTokenizer(symbolsString) {
-				tf.SetTokenizer(tf.languageConfig.Tokenizer(symbolsString)(tf, symbolsString))
-				tf.SetOverFlow(overflowRune)
-				return nil, false, nil
-			}
+	return (&symbolTokenizer{tf: tf}).start(initialString)
+}
+
+type symbolTokenizer struct {
+	tf      *TokenCreator
+	symbols []byte
+	handler TokenizerHandler
+}
+
+func (s *symbolTokenizer) start(initialString string) TokenizerHandler {
+	s.symbols = append(s.symbols[:0], initialString...)
+	if s.handler == nil {
+		s.handler = s.tokenize
+	}
+	return s.handler
+}
+
+func (s *symbolTokenizer) tokenize(r rune) ([]Token, completed, error) {
+	tf := s.tf
+	if tf.languageConfig.prefixTokenizer(nil, r) != nil || tf.languageConfig.endsSymbols(s.symbols, r) { // CustomTokenizers and parameters take priority over symbols
+		return s.createToken(r)
+	} else if tf.languageConfig.mayStartComment(s.symbols, s.longestRun()) && tf.commentParser.IsStartOfComment(string(s.symbols)) { // Check for being in a comment - could be assembly ";"
+		return nil, true, nil
+	} else if _, found := tf.languageConfig.symbol(r); found {
+		s.symbols = utf8.AppendRune(s.symbols, r)
+	} else {
+		return s.createToken(r)
+	}
+
+	return nil, false, tf.checkLength(len(s.symbols))
+}
+
+func (s *symbolTokenizer) createToken(overflowRune rune) ([]Token, completed, error) {
+	tf := s.tf
+
+	// First check that the parsed symbols + overflowRune doesn't match a custom tokenizer
+	resetTokenizer := completed(true)
+	if len(s.symbols) > 0 {
+		_, size := utf8.DecodeLastRune(s.symbols)
+		lastRune := s.symbols[len(s.symbols)-size:]
+		if tokenizer := tf.languageConfig.prefixTokenizer(lastRune, overflowRune); tokenizer != nil { // CustomTokenizers take priority over symbols
+			tf.SetTokenizer(tokenizer(tf, string(lastRune)))
+			s.symbols = s.symbols[:len(s.symbols)-size]
+			resetTokenizer = false // Don't want to reset as we've just swapped the tokenizer
+		} else if tokenizer := tf.languageConfig.prefixTokenizerBytes(s.symbols); tokenizer != nil {
+			tf.SetTokenizer(tokenizer(tf, string(s.symbols)))
+			tf.SetOverFlow(overflowRune)
+			return nil, false, nil
 		}
+	}
+
+	tf.SetOverFlow(overflowRune)
+	symbolTokens := tf.out[:0]
 
-		tf.SetOverFlow(overflowRune)
-		symbolTokens := make([]Token, 0)
-
-		// Parses potentially larger Operator tokens such as "<=" and single symbols such as
-		// "+", "-" ...
-		i := 0
-		for i < len(symbolsString) {
-			var longestSymbol string
-			for x := i + 1; x < len(symbolsString); x++ {
-				symbolStr := symbolsString[i : x+1]
-				if _, found := tf.languageConfig.Operators[symbolStr]; found {
-					longestSymbol = symbolStr
-				} else if tf.commentParser.IsStartOfComment(symbolStr) {
-					return nil, false, nil
-				}
+	// Parses potentially larger Operator tokens such as "<=" and single symbols such as
+	// "+", "-" ...
+	i := 0
+	for i < len(s.symbols) {
+		length, inComment := tf.longestOperator(s.symbols, i, s.longestRun())
+		if inComment {
+			return nil, false, nil
+		}
+		if length > 0 {
+			longestSymbol := string(s.symbols[i : i+length])
+			tokenID, _ := tf.languageConfig.operator(longestSymbol)
+			symbolTokens = append(symbolTokens, newToken(OperatorCategory, tokenID, longestSymbol, longestSymbol))
+			i += length
+		} else {
+			if tf.languageConfig.mayStartComment(s.symbols, s.longestRun()) && tf.commentParser.IsStartOfComment(string(s.symbols)) {
+				return nil, false, nil
 			}
-			if longestSymbol != "" {
-				tokenID := tf.languageConfig.Operators[longestSymbol]
-				symbolTokens = append(symbolTokens, NewToken(tokenID, longestSymbol, longestSymbol))
-				i += len(longestSymbol)
-			} else {
-				if tf.commentParser.IsStartOfComment(symbolsString) {
-					return nil, false, nil
-				}
-				tokenID, found := tf.languageConfig.Symbols[rune(symbolsString[i])]
-				if !found {
-					return nil, false, fmt.Errorf("unknown symbol %s", string(symbolsString[i]))
-				}
-				symbolTokens = append(symbolTokens, NewToken(tokenID, string(symbolsString[i]), symbolsString[i]))
-				i++
+			tokenID, found := tf.languageConfig.symbol(rune(s.symbols[i]))
+			if !found {
+				return nil, false, fmt.Errorf("unknown symbol %s", string(s.symbols[i]))
 			}
+			symbolTokens = append(symbolTokens, newToken(SymbolCategory, tokenID, string(s.symbols[i]), s.symbols[i]))
+			i++
 		}
+	}
+
+	tf.out = symbolTokens
+	return symbolTokens, resetTokenizer, nil
+}
 
-		return symbolTokens, resetTokenizer, nil
+// longestRun returns the longestSymbolRun of the language, found once for each line, or 0
+// with compiled tables, which don't need it.
+func (s *symbolTokenizer) longestRun() int {
+	tf := s.tf
+	if tf.languageConfig.compiled != nil {
+		return 0
 	}
+	if tf.longestRun < 0 {
+		tf.longestRun = tf.languageConfig.longestSymbolRun()
+	}
+	return tf.longestRun
+}
 
-	return func(r rune) ([]Token, completed, error) {
-		if tf.languageConfig.IsCustomTokenizer(string(r)) { // CustomTokenizers take priority over symbols
-			return createToken(r)
-		} else if tf.commentParser.IsStartOfComment(symbolsString) { // Check for being in a comment - could be assembly ";"
-			return nil, true, nil
-		} else if _, found := tf.languageConfig.Symbols[r]; found {
-			symbolsString += string(r)
-		} else {
-			return createToken(r)
+// longestOperator finds the length of the longest operator in symbols starting at byte offset i,
+// searching no further than longest, the longestSymbolRun, without compiled tables.
+// It reports inComment if a comment opener is found before a longer operator, in which
+// case the comment parser has been moved into the comment.
+func (tf *TokenCreator) longestOperator(symbols []byte, i int, longest int) (length int, inComment bool) {
+	if tf.languageConfig.compiled != nil {
+		node := tf.languageConfig.compiled.root.next[symbols[i]]
+		for x := i + 1; x < len(symbols) && node != nil; x++ {
+			if node = node.next[symbols[x]]; node == nil {
+				break
+			}
+			if node.isOperator {
+				length = x + 1 - i
+			} else if node.isComment && tf.commentParser.IsStartOfComment(string(symbols[i:x+1])) {
+				return 0, true
+			}
 		}
+		return length, false
+	}
 
-		return nil, false, nil
+	end := min(len(symbols), i+longest)
+	for x := i + 1; x < end; x++ {
+		if _, found := tf.languageConfig.Operators[string(symbols[i:x+1])]; found {
+			length = x + 1 - i
+		} else if tf.commentParser.IsStartOfComment(string(symbols[i : x+1])) {
+			return 0, true
+		}
 	}
+	return length, false
+}
+
+// isRuneString reports whether s consists of exactly the rune r.
+func isRuneString(r rune, s string) bool {
+	first, size := utf8.DecodeRuneInString(s)
+	return size == len(s) && size > 0 && first == r
 }
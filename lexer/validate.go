@@ -0,0 +1,397 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kinds of problem reported by LanguageConfig.Validate. Use errors.Is to test for them.
+var (
+	ErrInvalidEntry     = errors.New("invalid entry")              // An empty key or a value that can't be used
+	ErrUnreachable      = errors.New("unreachable")                // An entry the lexer can never produce or recognise
+	ErrDuplicateTokenID = errors.New("duplicate token identifier") // A token identifier used for more than one entry
+	ErrConflict         = errors.New("conflict")                   // Entries that compete for the same input
+)
+
+// Validate checks the configuration for problems that would make entries unreachable or
+// ambiguous once lexing starts:
+//   - operators and comment openers made of runes that aren't in Symbols
+//   - keywords that can't be lexed as identifiers
+//   - token identifiers shared between entries, or with the standard tokens
+//   - symbols shadowed by, or starting, prefix tokenizers, e.g. '%' and "%0"
+//   - prefix tokenizers that can't be reached or that shadow each other
+//   - identifier-shaped comment openers, such as "rem", that are also keywords, unless the
+//     language keeps comments
+//   - keywords claimed by a TokenCreator that doesn't claim similar identifiers
+//   - assembler mnemonics, directives and registers that can't be lexed or are also keywords,
+//     and labels without token identifiers
+//   - quoted identifier delimiters and parameter prefixes that start other tokens, e.g. a '['
+//     that's also a symbol
+//
+// It returns nil if no problems are found, otherwise all of them joined with errors.Join.
+func (ll *LanguageConfig) Validate() error {
+	var problems []error
+	report := func(kind error, format string, args ...any) {
+		problems = append(problems, fmt.Errorf("%w: %s", kind, fmt.Sprintf(format, args...)))
+	}
+
+	ll.validateSymbols(report)
+	ll.validateKeywords(report)
+	ll.validateOperators(report)
+	ll.validateComments(report)
+	ll.validatePrefixTokenizers(report)
+	ll.validateAssembler(report)
+	ll.validateQuoting(report)
+	ll.validateTokenIDs(report)
+	return errors.Join(problems...)
+}
+
+type reportFunc func(kind error, format string, args ...any)
+
+func (ll *LanguageConfig) validateSymbols(report reportFunc) {
+	for _, r := range sortedRunes(ll.Symbols) {
+		switch {
+		case unicode.IsSpace(r):
+			report(ErrUnreachable, "symbol %s is white space", strconv.QuoteRune(r))
+		case unicode.IsDigit(r):
+			report(ErrConflict, "symbol %s is a digit", strconv.QuoteRune(r))
+		case ll.isStringDelimiter(r):
+			report(ErrConflict, "symbol %s is a string delimiter", strconv.QuoteRune(r))
+		case ll.isIdentifierChar(r, 0):
+			report(ErrConflict, "symbol %s starts identifiers", strconv.QuoteRune(r))
+		case r >= utf8.RuneSelf:
+			report(ErrUnreachable, "symbol %s isn't ASCII", strconv.QuoteRune(r))
+		}
+	}
+}
+
+func (ll *LanguageConfig) validateKeywords(report reportFunc) {
+	folded := make(map[string]string)
+	for _, keyword := range sortedKeys(ll.Keywords) {
+		if ll.KeywordCase != CaseSensitive {
+			key := FoldCase(keyword)
+			if other, found := folded[key]; found && ll.Keywords[other] != ll.Keywords[keyword] {
+				report(ErrConflict, "keywords %q and %q differ only in case", other, keyword)
+			}
+			folded[key] = keyword
+		}
+		if keyword == "" {
+			report(ErrInvalidEntry, "empty keyword")
+			continue
+		}
+		if !ll.isIdentifierShaped(keyword) {
+			report(ErrUnreachable, "keyword %q isn't lexed as an identifier", keyword)
+			continue
+		}
+		for i, creator := range ll.TokenCreators {
+			if creator(keyword).ID != NullType && creator(ll.similarIdentifier(keyword)).ID == NullType {
+				report(ErrConflict, "keyword %q shadows TokenCreators[%d]", keyword, i)
+			}
+		}
+	}
+}
+
+func (ll *LanguageConfig) validateOperators(report reportFunc) {
+	for _, operator := range sortedKeys(ll.Operators) {
+		if utf8.RuneCountInString(operator) < 2 {
+			report(ErrUnreachable, "operator %q must be at least two symbols, use Symbols for single runes", operator)
+			continue
+		}
+		for _, r := range operator {
+			if _, found := ll.Symbols[r]; !found {
+				report(ErrUnreachable, "operator %q uses %s, which isn't in Symbols", operator, strconv.QuoteRune(r))
+			}
+		}
+	}
+}
+
+func (ll *LanguageConfig) validateComments(report reportFunc) {
+	for _, open := range sortedKeys(ll.Comments) {
+		if open == "" {
+			report(ErrInvalidEntry, "empty comment opener")
+			continue
+		}
+		if ll.Comments[open] == "" {
+			report(ErrInvalidEntry, "comment %q has no closing delimiter", open)
+		}
+
+		first, _ := utf8.DecodeRuneInString(open)
+		switch {
+		case ll.isIdentifierShaped(open):
+			if ll.isKeyword(open) && !ll.KeepComments {
+				report(ErrConflict, "comment %q is also a keyword", open)
+			}
+		case utf8.RuneCountInString(open) == 1:
+			if unicode.IsDigit(first) || ll.isStringDelimiter(first) {
+				report(ErrUnreachable, "comment %q starts a number or string", open)
+			}
+		default:
+			for _, r := range open {
+				if _, found := ll.Symbols[r]; !found {
+					report(ErrUnreachable, "comment %q uses %s, which isn't in Symbols", open, strconv.QuoteRune(r))
+				}
+			}
+		}
+	}
+}
+
+func (ll *LanguageConfig) validatePrefixTokenizers(report reportFunc) {
+	prefixes := sortedKeys(ll.PrefixTokenizers)
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			report(ErrInvalidEntry, "empty prefix tokenizer")
+			continue
+		}
+		if ll.PrefixTokenizers[prefix] == nil {
+			report(ErrInvalidEntry, "prefix tokenizer %q is nil", prefix)
+		}
+
+		first, size := utf8.DecodeRuneInString(prefix)
+		if _, found := ll.Symbols[first]; found {
+			if size == len(prefix) {
+				report(ErrConflict, "symbol %s is shadowed by prefix tokenizer %q", strconv.QuoteRune(first), prefix)
+			} else {
+				report(ErrConflict, "symbol %s is also the start of prefix tokenizer %q", strconv.QuoteRune(first), prefix)
+			}
+		}
+		if size == len(prefix) && ll.isIdentifierChar(first, 0) {
+			report(ErrConflict, "prefix tokenizer %q shadows identifiers starting with it", prefix)
+		}
+		if !ll.isPrefixReachable(prefix) {
+			report(ErrUnreachable, "prefix tokenizer %q doesn't start with a symbol or digits", prefix)
+		}
+		if _, found := ll.Operators[prefix]; found {
+			report(ErrConflict, "prefix tokenizer %q is also an operator", prefix)
+		}
+		if _, found := ll.Comments[prefix]; found {
+			report(ErrConflict, "prefix tokenizer %q is also a comment", prefix)
+		}
+		if size < len(prefix) {
+			if _, found := ll.PrefixTokenizers[prefix[:size]]; found {
+				report(ErrUnreachable, "prefix tokenizer %q is shadowed by %q", prefix, prefix[:size])
+			}
+		}
+	}
+}
+
+// isPrefixReachable reports whether the selector, symbol or number tokenizers can hand over
+// to the prefix tokenizer: single runes, a symbol followed by a rune, a run of symbols, or
+// digits followed by a rune, e.g. "$", "%0", "&&" and "0x".
+func (ll *LanguageConfig) isPrefixReachable(prefix string) bool {
+	first, size := utf8.DecodeRuneInString(prefix)
+	if size == len(prefix) {
+		return !unicode.IsSpace(first)
+	}
+	if _, found := ll.Symbols[first]; found && utf8.RuneCountInString(prefix) == 2 {
+		return true
+	}
+
+	symbols, digits := true, true
+	last, lastSize := utf8.DecodeLastRuneInString(prefix)
+	for i, r := range prefix {
+		if _, found := ll.Symbols[r]; !found {
+			symbols = false
+		}
+		if i < len(prefix)-lastSize && !unicode.IsDigit(r) && !(i > 0 && r == '.') {
+			digits = false
+		}
+	}
+	return symbols || (digits && unicode.IsDigit(first) && !unicode.IsDigit(last))
+}
+
+func (ll *LanguageConfig) validateAssembler(report reportFunc) {
+	asm := ll.Assembler
+	if asm == nil {
+		return
+	}
+	names := func(kind string, table map[string]TokenIdentifier, prefixed bool) {
+		for _, name := range sortedKeys(table) {
+			lexed := name // A directive's prefix starts an identifier, so check its name as if it were a letter
+			if first, size := utf8.DecodeRuneInString(name); prefixed && !ll.isIdentifierChar(first, 0) {
+				lexed = "a" + name[size:]
+			}
+			switch {
+			case name == "":
+				report(ErrInvalidEntry, "empty %s", kind)
+			case !ll.isIdentifierShaped(lexed):
+				report(ErrUnreachable, "%s %q isn't lexed as an identifier", kind, name)
+			case ll.isKeyword(name):
+				report(ErrConflict, "%s %q is also a keyword", kind, name)
+			}
+		}
+	}
+	names("mnemonic", asm.Mnemonics, false)
+	names("directive", asm.Directives, true)
+	names("register", asm.Registers, false)
+
+	for _, r := range asm.LocalLabelPrefixes {
+		if unicode.IsSpace(r) || unicode.IsDigit(r) || ll.isStringDelimiter(r) {
+			report(ErrUnreachable, "local label prefix %s starts a number or string, or is white space", strconv.QuoteRune(r))
+		}
+	}
+	if asm.LocalLabelPrefixes != "" && asm.LocalLabel == NullType {
+		report(ErrInvalidEntry, "local labels have the null token identifier")
+	}
+	if asm.AnonymousLabels != NoAnonymousLabels && asm.AnonymousLabel == NullType {
+		report(ErrInvalidEntry, "anonymous labels have the null token identifier")
+	}
+}
+
+func (ll *LanguageConfig) validateQuoting(report reportFunc) {
+	// startsOther describes the token r would otherwise start, or returns "" if none.
+	startsOther := func(r rune) string {
+		_, symbol := ll.Symbols[r]
+		switch {
+		case unicode.IsDigit(r):
+			return "a number"
+		case ll.isStringDelimiter(r):
+			return "a string"
+		case ll.isIdentifierChar(r, 0):
+			return "identifiers"
+		case ll.PrefixTokenizers[string(r)] != nil:
+			return "a prefix tokenizer"
+		case symbol:
+			return "a symbol"
+		}
+		return ""
+	}
+
+	for _, open := range sortedRunes(ll.QuotedIdentifiers) {
+		close := ll.QuotedIdentifiers[open]
+		switch {
+		case unicode.IsSpace(open):
+			report(ErrUnreachable, "quoted identifier delimiter %s is white space", strconv.QuoteRune(open))
+		case close == 0 || close == newLine:
+			report(ErrInvalidEntry, "quoted identifier %s has no closing delimiter", strconv.QuoteRune(open))
+		case ll.isParameterPrefix(open) || (open == '$' && ll.DollarQuotedStrings):
+			report(ErrConflict, "quoted identifier delimiter %s also starts parameters or strings", strconv.QuoteRune(open))
+		case startsOther(open) != "":
+			report(ErrConflict, "quoted identifier delimiter %s also starts %s", strconv.QuoteRune(open), startsOther(open))
+		}
+	}
+
+	for _, r := range ll.Parameters {
+		other := startsOther(r)
+		switch {
+		case unicode.IsSpace(r):
+			report(ErrUnreachable, "parameter prefix %s is white space", strconv.QuoteRune(r))
+		case other != "" && other != "a symbol":
+			report(ErrConflict, "parameter prefix %s also starts %s", strconv.QuoteRune(r), other)
+		}
+	}
+	if other := startsOther('$'); ll.DollarQuotedStrings && other != "" && other != "a symbol" {
+		report(ErrConflict, "dollar-quoted strings start with '$', which also starts %s", other)
+	}
+}
+
+func (ll *LanguageConfig) validateTokenIDs(report reportFunc) {
+	entries := make(map[TokenIdentifier][]string)
+	for keyword, id := range ll.Keywords {
+		entries[id] = append(entries[id], fmt.Sprintf("keyword %q", keyword))
+	}
+	for operator, id := range ll.Operators {
+		entries[id] = append(entries[id], fmt.Sprintf("operator %q", operator))
+	}
+	for r, id := range ll.Symbols {
+		entries[id] = append(entries[id], "symbol "+strconv.QuoteRune(r))
+	}
+	if asm := ll.Assembler; asm != nil {
+		tables := []struct {
+			kind  string
+			table map[string]TokenIdentifier
+		}{{"mnemonic", asm.Mnemonics}, {"directive", asm.Directives}, {"register", asm.Registers}}
+		for _, t := range tables {
+			for name, id := range t.table {
+				entries[id] = append(entries[id], fmt.Sprintf("%s %q", t.kind, name))
+			}
+		}
+		if asm.LocalLabelPrefixes != "" && asm.LocalLabel != NullType {
+			entries[asm.LocalLabel] = append(entries[asm.LocalLabel], "local labels")
+		}
+		if asm.AnonymousLabels != NoAnonymousLabels && asm.AnonymousLabel != NullType {
+			entries[asm.AnonymousLabel] = append(entries[asm.AnonymousLabel], "anonymous labels")
+		}
+		if asm.Immediate != NullType {
+			entries[asm.Immediate] = append(entries[asm.Immediate], "immediate prefix")
+		}
+	}
+
+	ids := make([]TokenIdentifier, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		names := entries[id]
+		sort.Strings(names)
+		switch {
+		case id == NullType:
+			report(ErrInvalidEntry, "null token identifier used by %s", strings.Join(names, ", "))
+		case id < LastStdLiteral || id >= FirstReservedType:
+			report(ErrDuplicateTokenID, "standard token identifier %d used by %s", id, strings.Join(names, ", "))
+		case len(names) > 1:
+			report(ErrDuplicateTokenID, "token identifier %d shared by %s", id, strings.Join(names, ", "))
+		}
+	}
+}
+
+// isKeyword reports whether s matches a keyword, taking KeywordCase into account.
+func (ll *LanguageConfig) isKeyword(s string) bool {
+	if _, found := ll.Keywords[s]; found || ll.KeywordCase == CaseSensitive {
+		return found
+	}
+	for keyword := range ll.Keywords {
+		if FoldCase(keyword) == FoldCase(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdentifierShaped reports whether the identifier tokenizer reads s as a single identifier.
+func (ll *LanguageConfig) isIdentifierShaped(s string) bool {
+	for i, r := range s {
+		if !ll.isIdentifierChar(r, i) {
+			return false
+		}
+		if i > 0 && i+utf8.RuneLen(r) < len(s) && strings.ContainsRune(ll.IdentifierTermination, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// similarIdentifier returns an identifier that isn't identifier, but which a TokenCreator
+// claiming identifier in general would also claim: identifier with a digit inserted before
+// any termination rune, e.g. "left0$" for "left$".
+func (ll *LanguageConfig) similarIdentifier(identifier string) string {
+	last, size := utf8.DecodeLastRuneInString(identifier)
+	if strings.ContainsRune(ll.IdentifierTermination, last) {
+		return identifier[:len(identifier)-size] + "0" + string(last)
+	}
+	return identifier + "0"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedRunes[V any](m map[rune]V) []rune {
+	runes := make([]rune, 0, len(m))
+	for r := range m {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return runes
+}
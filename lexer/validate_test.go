@@ -0,0 +1,127 @@
+package lexer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jrsteele09/go-lexer/lexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBasicLanguage(t *testing.T) {
+	err := NewBasicLanguage().Validate()
+	require.ErrorIs(t, err, lexer.ErrConflict)
+	require.Equal(t, []string{
+		`conflict: symbol '$' is shadowed by prefix tokenizer "$"`,
+		`conflict: symbol '%' is also the start of prefix tokenizer "%0"`,
+		`conflict: symbol '%' is also the start of prefix tokenizer "%1"`,
+	}, problems(err))
+}
+
+func TestValidateValidLanguage(t *testing.T) {
+	config := lexer.NewLexerLanguage(lexer.LanguageConfig{
+		Keywords:         map[string]lexer.TokenIdentifier{"if": 10, "left$": 11},
+		Operators:        map[string]lexer.TokenIdentifier{"<=": 12},
+		Symbols:          map[rune]lexer.TokenIdentifier{'<': 13, '=': 14, '/': 15, '*': 16},
+		Comments:         map[string]string{"//": "\n", "/*": "*/", "rem": "\n", ";": "\n"},
+		PrefixTokenizers: map[string]lexer.TokenizerFunc{"$": lexer.HexTokenizer, "0x": lexer.HexTokenizer},
+		TokenCreators: []func(string) lexer.Token{
+			func(identifier string) lexer.Token {
+				if strings.HasSuffix(identifier, "$") {
+					return lexer.NewToken(17, identifier, nil)
+				}
+				return lexer.Token{}
+			},
+		},
+		IdentifierTermination: "$",
+	})
+	require.NoError(t, config.Validate())
+}
+
+func TestValidateOperatorsAndComments(t *testing.T) {
+	err := lexer.NewLexerLanguage(lexer.LanguageConfig{
+		Operators: map[string]lexer.TokenIdentifier{"<=": 10, "!": 11},
+		Symbols:   map[rune]lexer.TokenIdentifier{'<': 12, '*': 13},
+		Comments:  map[string]string{"/*": "*/", "'": "\n", "#": ""},
+	}).Validate()
+	require.ErrorIs(t, err, lexer.ErrUnreachable)
+	require.ErrorIs(t, err, lexer.ErrInvalidEntry)
+	require.Equal(t, []string{
+		`unreachable: operator "!" must be at least two symbols, use Symbols for single runes`,
+		`unreachable: operator "<=" uses '=', which isn't in Symbols`,
+		`invalid entry: comment "#" has no closing delimiter`,
+		`unreachable: comment "'" starts a number or string`,
+		`unreachable: comment "/*" uses '/', which isn't in Symbols`,
+	}, problems(err))
+}
+
+func TestValidateTokenIDs(t *testing.T) {
+	err := lexer.NewLexerLanguage(lexer.LanguageConfig{
+		Keywords: map[string]lexer.TokenIdentifier{"print": 10, "write": 10, "nothing": lexer.NullType},
+		Symbols:  map[rune]lexer.TokenIdentifier{'?': 10, '+': lexer.StringLiteral, '-': lexer.FirstReservedType + 0x10},
+	}).Validate()
+	require.ErrorIs(t, err, lexer.ErrDuplicateTokenID)
+	require.Equal(t, []string{
+		`invalid entry: null token identifier used by keyword "nothing"`,
+		`duplicate token identifier: standard token identifier 6 used by symbol '+'`,
+		`duplicate token identifier: token identifier 10 shared by keyword "print", keyword "write", symbol '?'`,
+		`duplicate token identifier: standard token identifier 32528 used by symbol '-'`,
+	}, problems(err))
+}
+
+func TestValidateIdentifiers(t *testing.T) {
+	err := lexer.NewLexerLanguage(lexer.LanguageConfig{
+		Keywords: map[string]lexer.TokenIdentifier{"rem": 10, "a": 11, "x": 12, "go-to": 13, "1st": 14},
+		Symbols:  map[rune]lexer.TokenIdentifier{'x': 15},
+		Comments: map[string]string{"rem": "\n"},
+		TokenCreators: []func(string) lexer.Token{
+			func(identifier string) lexer.Token { // Registers
+				if identifier == "a" {
+					return lexer.NewToken(16, identifier, nil)
+				}
+				return lexer.Token{}
+			},
+			func(identifier string) lexer.Token { // Variables
+				return lexer.NewToken(17, identifier, nil)
+			},
+		},
+	}).Validate()
+	require.Equal(t, []string{
+		`conflict: symbol 'x' starts identifiers`,
+		`unreachable: keyword "1st" isn't lexed as an identifier`,
+		`conflict: keyword "a" shadows TokenCreators[0]`,
+		`unreachable: keyword "go-to" isn't lexed as an identifier`,
+		`conflict: comment "rem" is also a keyword`,
+	}, problems(err))
+
+	// A kept comment's opener may be a keyword, which comes before the comment
+	err = lexer.NewLexerLanguage(lexer.LanguageConfig{
+		Keywords:     map[string]lexer.TokenIdentifier{"rem": 10},
+		Comments:     map[string]string{"rem": "\n"},
+		KeepComments: true,
+	}).Validate()
+	require.NoError(t, err)
+}
+
+func TestValidatePrefixTokenizers(t *testing.T) {
+	err := lexer.NewLexerLanguage(lexer.LanguageConfig{
+		Operators:        map[string]lexer.TokenIdentifier{"&&": 10},
+		Symbols:          map[rune]lexer.TokenIdentifier{'&': 11},
+		PrefixTokenizers: map[string]lexer.TokenizerFunc{"h'": lexer.HexTokenizer, "&&": lexer.HexTokenizer, "#": lexer.HexTokenizer, "#$": lexer.HexTokenizer},
+	}).Validate()
+	require.Equal(t, []string{
+		`unreachable: prefix tokenizer "#$" doesn't start with a symbol or digits`,
+		`unreachable: prefix tokenizer "#$" is shadowed by "#"`,
+		`conflict: symbol '&' is also the start of prefix tokenizer "&&"`,
+		`conflict: prefix tokenizer "&&" is also an operator`,
+		`unreachable: prefix tokenizer "h'" doesn't start with a symbol or digits`,
+	}, problems(err))
+}
+
+// problems splits the errors joined by Validate.
+func problems(err error) []string {
+	if err == nil {
+		return nil
+	}
+	return strings.Split(err.Error(), "\n")
+}